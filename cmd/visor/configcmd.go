@@ -0,0 +1,257 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/namyoungkim/visor/internal/config"
+)
+
+// runConfig implements `visor config <command>`.
+func runConfig(args []string, globalPath string) error {
+	if len(args) == 0 {
+		return errors.New("usage: visor config show [--effective] [--dir <dir>] | migrate [--dry-run] [--dir <dir>]")
+	}
+
+	switch args[0] {
+	case "show":
+		return runConfigShow(args[1:], globalPath)
+	case "migrate":
+		return runConfigMigrate(args[1:], globalPath)
+	default:
+		return fmt.Errorf("unknown config command %q (want show or migrate)", args[0])
+	}
+}
+
+// runConfigShow prints the config layers, or with --effective the merged
+// configuration annotated with where each value came from.
+func runConfigShow(args []string, globalPath string) error {
+	fs := flag.NewFlagSet("config show", flag.ContinueOnError)
+	effective := fs.Bool("effective", false, "Print the merged configuration with the origin of each value")
+	dir := fs.String("dir", "", "Directory to search for "+config.ProjectConfigName+" (default: current directory)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		*dir = wd
+	}
+
+	layered, err := loadConfig(globalPath, *dir)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range layered.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+
+	if *effective {
+		return layered.WriteEffective(os.Stdout)
+	}
+
+	fmt.Println("Config layers (lowest priority first):")
+	fmt.Println("  defaults")
+	for _, f := range layered.Files {
+		fmt.Printf("  %s\n", f)
+	}
+	var envVars []string
+	for _, origin := range layered.Origins {
+		if name, ok := strings.CutPrefix(origin, "env "); ok {
+			envVars = append(envVars, name)
+		}
+	}
+	sort.Strings(envVars)
+	for _, name := range envVars {
+		fmt.Printf("  $%s\n", name)
+	}
+	return nil
+}
+
+// runConfigMigrate upgrades the global and project config files to the
+// current config version, or with --dry-run prints the changes as a diff.
+// The original file is kept as <file>.bak.
+func runConfigMigrate(args []string, globalPath string) error {
+	fs := flag.NewFlagSet("config migrate", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "Print the changes as a diff without writing")
+	dir := fs.String("dir", "", "Directory to search for "+config.ProjectConfigName+" (default: current directory)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		*dir = wd
+	}
+
+	files := config.LayerFiles(config.LoadOptions{GlobalPath: globalPath, Dir: *dir, Environ: os.Environ()})
+	if len(files) == 0 {
+		fmt.Println("No config files to migrate")
+		return nil
+	}
+
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		result, err := config.Migrate(string(data))
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if result.From == result.To {
+			fmt.Printf("%s: already at config version %d\n", path, result.To)
+			continue
+		}
+
+		if *dryRun {
+			fmt.Printf("--- %s (version %d)\n+++ %s (version %d)\n", path, result.From, path, result.To)
+			fmt.Print(lineDiff(string(data), result.Source))
+			for _, c := range result.Changes {
+				if c.Line > 0 {
+					fmt.Fprintf(os.Stderr, "%s:%d: %s\n", path, c.Line, c.Description)
+				} else {
+					fmt.Fprintf(os.Stderr, "%s: %s\n", path, c.Description)
+				}
+			}
+			continue
+		}
+
+		if err := writeMigrated(path, data, result.Source); err != nil {
+			return err
+		}
+		fmt.Printf("%s: upgraded from config version %d to %d (%d changes, backup: %s.bak)\n",
+			path, result.From, result.To, len(result.Changes), path)
+	}
+	return nil
+}
+
+// writeMigrated saves the original as path.bak and replaces path,
+// keeping its permissions.
+func writeMigrated(path string, original []byte, migrated string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".bak", original, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.WriteString(migrated); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// lineDiff returns a unified diff of a and b with two lines of context.
+func lineDiff(a, b string) string {
+	x, y := strings.Split(a, "\n"), strings.Split(b, "\n")
+
+	// lcs[i][j] is the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type op struct {
+		kind byte // ' ', '-' or '+'
+		text string
+		i, j int // Line index in a and b
+	}
+	var ops []op
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			ops = append(ops, op{' ', x[i], i, j})
+			i, j = i+1, j+1
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{'-', x[i], i, j})
+			i++
+		default:
+			ops = append(ops, op{'+', y[j], i, j})
+			j++
+		}
+	}
+
+	const context = 2
+	var sb strings.Builder
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+		// Extend the hunk while changes are within 2*context lines
+		from := max(start-context, 0)
+		end := start
+		for k := start; k < len(ops); k++ {
+			if ops[k].kind != ' ' {
+				end = k
+			} else if k-end > 2*context {
+				break
+			}
+		}
+		to := min(end+context+1, len(ops))
+
+		var oldLines, newLines int
+		for _, o := range ops[from:to] {
+			if o.kind != '+' {
+				oldLines++
+			}
+			if o.kind != '-' {
+				newLines++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", ops[from].i+1, oldLines, ops[from].j+1, newLines)
+		for _, o := range ops[from:to] {
+			fmt.Fprintf(&sb, "%c%s\n", o.kind, o.text)
+		}
+		start = to
+	}
+	return sb.String()
+}
+
+// loadConfig loads the layered config (defaults, global file, project file
+// found from dir, VISOR_* environment).
+func loadConfig(globalPath, dir string) (*config.Layered, error) {
+	return config.LoadLayered(config.LoadOptions{
+		GlobalPath: globalPath,
+		Dir:        dir,
+		Environ:    os.Environ(),
+	})
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/importer"
+)
+
+// runImport implements `visor import [options] <file>`: it translates
+// another tool's statusline config into the lines of the global config.
+// Other settings of the global config are kept; the previous file is
+// saved as <file>.bak.
+func runImport(args []string, globalPath string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	from := fs.String("from", "", "Source format: ccstatusline, ccusage, p10k (default: detect)")
+	dryRun := fs.Bool("dry-run", false, "Print the resulting config instead of writing it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: visor import [--from ccstatusline|ccusage|p10k] [--dry-run] <file>")
+	}
+
+	result, err := importer.ImportFile(fs.Arg(0), importer.Format(*from))
+	if err != nil {
+		return err
+	}
+	for _, msg := range result.Approximate {
+		fmt.Fprintf(os.Stderr, "approximate: %s\n", msg)
+	}
+	for _, msg := range result.Skipped {
+		fmt.Fprintf(os.Stderr, "not imported: %s\n", msg)
+	}
+
+	path := config.GlobalConfigPath(config.LoadOptions{GlobalPath: globalPath, Environ: os.Environ()})
+	cfg, err := config.Load(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	result.Apply(cfg)
+
+	if *dryRun {
+		return config.Encode(os.Stdout, cfg)
+	}
+
+	backup := ""
+	if data, err := os.ReadFile(path); err == nil {
+		backup = path + ".bak"
+		if err := os.WriteFile(backup, data, 0644); err != nil {
+			return err
+		}
+	}
+	if err := config.Save(cfg, path); err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("Imported %d widgets from %s into %s", result.Widgets(), result.Format, path)
+	if backup != "" {
+		msg += " (previous config: " + backup + ")"
+	}
+	fmt.Println(msg)
+	if n := len(result.Skipped); n > 0 {
+		fmt.Printf("%d not imported (see above)\n", n)
+	}
+	return nil
+}
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/namyoungkim/visor/internal/auth"
 	"github.com/namyoungkim/visor/internal/config"
@@ -29,15 +33,58 @@ func main() {
 	checkFlag := flag.Bool("check", false, "Validate configuration file")
 	debugFlag := flag.Bool("debug", false, "Enable debug output to stderr")
 	tuiFlag := flag.Bool("tui", false, "Open interactive configuration editor")
+	storeCredsFlag := flag.Bool("store-credentials", false, "Encrypt OAuth credentials from stdin to ~/.config/visor/credentials.enc")
+	recordDir := flag.String("record", "", "Save each stdin payload and rendered output to `dir` for visor replay")
+	configPath := flag.String("config", "", "Global config `file` (default: $VISOR_CONFIG, then ~/.config/visor/config.toml)")
+	widthFlag := flag.Int("width", 0, "Terminal width in `columns` (default: [general] width, then detected)")
+	colorFlag := flag.String("color", "", "Color `mode`: truecolor, 256, 16 or none (default: [general] color, then detected)")
 
 	flag.Parse()
 
+	if *colorFlag != "" && *colorFlag != render.ColorModeAuto {
+		if _, ok := render.ParseColorMode(*colorFlag); !ok {
+			fmt.Fprintf(os.Stderr, "Unknown color mode %q (want %s)\n", *colorFlag, strings.Join(render.ColorModeNames, ", "))
+			os.Exit(2)
+		}
+	}
+
 	// Handle flags
 	if *versionFlag {
 		fmt.Printf("visor %s\n", version)
 		return
 	}
 
+	// Subcommands; with --init the first argument is a preset name instead
+	if !*initFlag && flag.NArg() > 0 {
+		args := flag.Args()[1:]
+		switch flag.Arg(0) {
+		case "replay":
+			if err := runReplay(args, *configPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Replay error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "import":
+			if err := runImport(args, *configPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Import error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "refresh-usage":
+			// Internal: started by the statusline, not meant to be run by hand
+			if err := runRefreshUsage(args, *configPath); err != nil {
+				os.Exit(1)
+			}
+			return
+		case "config":
+			if err := runConfig(args, *configPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Config error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	if *initFlag {
 		// Get preset name from positional argument
 		presetName := "default"
@@ -72,14 +119,52 @@ func main() {
 	}
 
 	if *checkFlag {
-		if err := config.Validate(""); err != nil {
-			fmt.Fprintf(os.Stderr, "Config error: %v\n", err)
+		wd, _ := os.Getwd()
+		opts := config.LoadOptions{GlobalPath: *configPath, Dir: wd, Environ: os.Environ()}
+
+		// Check each layer file on its own so problems point at the right file
+		total := 0
+		schema := widgets.Schema()
+		for _, path := range config.LayerFiles(opts) {
+			problems, err := config.Check(path, schema)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Config error: %v\n", err)
+				os.Exit(1)
+			}
+			for _, p := range problems {
+				fmt.Fprintf(os.Stderr, "%s:%s\n", path, p)
+			}
+			total += len(problems)
+		}
+		if total > 0 {
+			fmt.Fprintf(os.Stderr, "Config has %d problem(s)\n", total)
 			os.Exit(1)
 		}
+		if layered, err := config.LoadLayered(opts); err == nil {
+			for _, w := range layered.Warnings {
+				fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+			}
+		}
 		fmt.Println("Config is valid")
 		return
 	}
 
+	if *storeCredsFlag {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading credentials: %v\n", err)
+			os.Exit(1)
+		}
+		p := auth.NewEncryptedFileProvider()
+		if err := p.Save(data); err != nil {
+			fmt.Fprintf(os.Stderr, "Error storing credentials: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Stored encrypted credentials at %s\n", p.Path)
+		fmt.Println(`Set credential_source = "encrypted_file" under [usage] to use them`)
+		return
+	}
+
 	if *tuiFlag {
 		if err := tui.Run(); err != nil {
 			fmt.Fprintf(os.Stderr, "TUI error: %v\n", err)
@@ -89,28 +174,59 @@ func main() {
 	}
 
 	// Main pipeline: stdin → parse → render → stdout
-	session := input.Parse(os.Stdin)
+	var stdin io.Reader = os.Stdin
+	var captured bytes.Buffer
+	if *recordDir != "" {
+		stdin = io.TeeReader(os.Stdin, &captured)
+	}
+	session, diag := input.ParseWithDiagnostics(stdin)
 
 	// Set git working directory from session CWD
-	if session.CWD != "" {
-		git.SetWorkDir(session.CWD)
+	if dir := session.GetCurrentDir(); dir != "" {
+		git.SetWorkDir(dir)
 	}
 
-	cfg, err := config.Load("")
-	if err != nil {
-		if *debugFlag {
-			fmt.Fprintf(os.Stderr, "[visor] config error: %v\n", err)
-		}
-		return
+	// A broken config file falls back to the last-known-good config
+	layered := config.LoadWithFallback(config.LoadOptions{
+		GlobalPath: *configPath,
+		Dir:        session.GetCurrentDir(),
+		Environ:    os.Environ(),
+	})
+	if layered.LoadErr != nil && *debugFlag {
+		fmt.Fprintf(os.Stderr, "[visor] config error: %v\n", layered.LoadErr)
 	}
 
+	cfg := layered.Config
+
 	// Merge debug flag: CLI flag OR config option
 	debug := *debugFlag || cfg.General.Debug
 
 	if debug {
+		for _, path := range layered.Files {
+			fmt.Fprintf(os.Stderr, "[visor] config: %s\n", path)
+		}
+		for _, w := range layered.Warnings {
+			fmt.Fprintf(os.Stderr, "[visor] config warning: %s\n", w)
+		}
+		printInputDiagnostics(diag)
 		fmt.Fprintf(os.Stderr, "[visor] session: %s, model: %s\n", session.SessionID, session.Model.DisplayName)
 	}
 
+	// Claude Code runs visor without a TTY, so the width comes from the
+	// first source that knows it
+	width := render.ResolveWidth(*widthFlag, cfg.General.Width)
+	width.Margin = cfg.General.WidthMargin
+	render.SetTerminalWidth(width.Budget())
+	if debug {
+		fmt.Fprintf(os.Stderr, "[visor] width: %s\n", width)
+	}
+
+	colorMode, colorSource := render.ResolveColorMode(*colorFlag, cfg.General.Color, os.LookupEnv)
+	render.SetColorMode(colorMode)
+	if debug {
+		fmt.Fprintf(os.Stderr, "[visor] color: %s (from %s)\n", colorMode, colorSource)
+	}
+
 	// Load history for this session
 	hist, err := history.Load(session.SessionID)
 	if err != nil && debug {
@@ -122,7 +238,8 @@ func main() {
 		hist.BlockStartTime = history.LoadGlobalBlockStart()
 	}
 
-	// Update block timer (for Claude Pro rate limit tracking)
+	// Update block timer (for Claude Pro rate limit tracking).
+	// Replaced by the transcript-based block when usage tracking is enabled.
 	hist.UpdateBlockStartTime()
 
 	// Persist block start time globally (across sessions)
@@ -130,24 +247,8 @@ func main() {
 		fmt.Fprintf(os.Stderr, "[visor] failed to save global block start: %v\n", err)
 	}
 
-	// Calculate cache hit rate for history
-	var cacheHitPct float64
-	if cu := session.GetCurrentUsage(); cu != nil {
-		cacheRead := cu.GetCacheReadTokens()
-		total := cu.InputTokens + cacheRead
-		if total > 0 {
-			cacheHitPct = float64(cacheRead) / float64(total) * 100
-		}
-	}
-
 	// Add current session data to history
-	hist.Add(history.Entry{
-		ContextPct:   session.ContextWindow.UsedPercentage,
-		CostUSD:      session.Cost.TotalCostUSD,
-		DurationMs:   session.Cost.TotalDurationMs,
-		CacheHitPct:  cacheHitPct,
-		APILatencyMs: session.Cost.TotalAPIDurationMs,
-	})
+	hist.Add(historyEntry(session))
 
 	// Set history on context_spark widget
 	widgets.SetHistory(hist)
@@ -159,43 +260,166 @@ func main() {
 	if debug {
 		fmt.Fprintf(os.Stderr, "[visor] transcript: %s (tools=%d, agents=%d)\n",
 			session.TranscriptPath, len(transcriptData.Tools), len(transcriptData.Agents))
+		for _, a := range transcriptData.TopLevelAgents() {
+			total := transcriptData.TreeUsage(a.ID)
+			fmt.Fprintf(os.Stderr, "[visor] agent %s (%s): tokens=%d cost=$%.4f tools=%d children=%d\n",
+				a.Type, a.Status, total.TotalTokens(), total.CostUSD, a.ToolCount, len(a.Children))
+		}
 	}
 
 	// Load cost data for daily/weekly/block cost widgets (v0.6)
+	var limitsFetcher *usage.CachedFetcher
 	if cfg.Usage.Enabled {
 		costData := loadCostData(session, hist, cfg, debug)
 		widgets.SetCostData(costData)
 
-		// Try to load usage limits (API first, then local fallback)
-		limits := loadUsageLimits(costData, hist, cfg, debug)
+		// OAuth credentials, refreshed on use when expired
+		provider := newUsageProvider(cfg, debug)
+
+		// Snapshot auth state; a refresh, if needed, happens in the background fetch
+		authStatus := provider.Status()
+		widgets.SetAuthStatus(&authStatus)
+		if debug {
+			fmt.Fprintf(os.Stderr, "[visor] auth: state=%s, expires=%s\n",
+				authStatus.State, authStatus.ExpiresAt.Format(time.RFC3339))
+		}
+
+		// Load usage limits from the configured sources (OAuth cache, headers, local, ...)
+		limitsFetcher = usage.NewCachedFetcher(usage.NewClient(provider), "",
+			time.Duration(cfg.Usage.CacheTTL)*time.Second)
+		limitsFetcher.Spawn = usage.DetachedRefresh(refreshUsageArgs(*configPath, session.GetCurrentDir())...)
+		limits := loadUsageLimits(limitsFetcher, provider, session, costData, hist, cfg, debug)
 		widgets.SetUsageLimits(limits)
 	}
 
-	output := renderSession(session, cfg)
+	notice := ""
+	if layered.LoadErr != nil {
+		notice = configErrorNotice(layered.LoadErr)
+	}
+	output := renderSession(session, cfg, notice)
 	if output != "" {
 		fmt.Print(output)
 	}
 
+	if *recordDir != "" {
+		recordFrame(*recordDir, session.SessionID, captured.Bytes(), output, debug)
+	}
+
 	// Save history
 	if err := hist.Save(); err != nil && debug {
 		fmt.Fprintf(os.Stderr, "[visor] failed to save history: %v\n", err)
 	}
+
+	// The usage refresh runs in a detached child, so this only reports
+	// whether it could be started
+	if limitsFetcher != nil {
+		if err := limitsFetcher.Wait(); err != nil && debug {
+			fmt.Fprintf(os.Stderr, "[visor] usage refresh failed to start: %v\n", err)
+		}
+	}
+}
+
+// newUsageProvider returns the configured OAuth credential source, with
+// expired tokens refreshed on use.
+func newUsageProvider(cfg *config.Config, debug bool) *auth.RefreshingProvider {
+	baseProvider, err := auth.ProviderForSource(cfg.Usage.CredentialSource)
+	if err != nil {
+		if debug {
+			fmt.Fprintf(os.Stderr, "[visor] credential source %q: %v (using auto)\n", cfg.Usage.CredentialSource, err)
+		}
+		baseProvider = auth.DefaultProvider()
+	}
+	return auth.NewRefreshingProvider(baseProvider, auth.RefreshOptions{
+		TokenURL:  cfg.Auth.TokenURL,
+		ClientID:  cfg.Auth.ClientID,
+		Disabled:  cfg.Auth.DisableRefresh,
+		WriteBack: cfg.Auth.WriteBack,
+	})
+}
+
+// refreshUsageArgs returns the arguments that make a child visor load the
+// same config layers and refresh the usage cache.
+func refreshUsageArgs(configPath, dir string) []string {
+	var args []string
+	if configPath != "" {
+		args = append(args, "--config", configPath)
+	}
+	return append(args, "refresh-usage", dir)
+}
+
+// runRefreshUsage fetches usage limits into the shared cache. It runs in
+// the detached child started by the statusline, which already holds the
+// cache's refresh lock.
+func runRefreshUsage(args []string, configPath string) error {
+	dir := ""
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	cfg := config.LoadWithFallback(config.LoadOptions{
+		GlobalPath: configPath,
+		Dir:        dir,
+		Environ:    os.Environ(),
+	}).Config
+
+	fetcher := usage.NewCachedFetcher(usage.NewClient(newUsageProvider(cfg, false)), "",
+		time.Duration(cfg.Usage.CacheTTL)*time.Second)
+	return fetcher.Refresh()
+}
+
+// historyEntry builds the history entry for the current session data.
+func historyEntry(session *input.Session) history.Entry {
+	// Calculate cache hit rate for history
+	var cacheHitPct float64
+	if cu := session.GetCurrentUsage(); cu != nil {
+		cacheRead := cu.GetCacheReadTokens()
+		total := cu.InputTokens + cacheRead
+		if total > 0 {
+			cacheHitPct = float64(cacheRead) / float64(total) * 100
+		}
+	}
+
+	return history.Entry{
+		ContextPct:   session.ContextWindow.UsedPercentage,
+		CostUSD:      session.Cost.TotalCostUSD,
+		DurationMs:   session.Cost.TotalDurationMs,
+		CacheHitPct:  cacheHitPct,
+		APILatencyMs: session.Cost.TotalAPIDurationMs,
+	}
+}
+
+// configErrorNotice returns the "⚠ config" segment with the load error,
+// so a broken config never blanks the statusline.
+func configErrorNotice(err error) string {
+	return render.Colorize("⚠ config: "+config.ErrorSummary(err), "yellow")
 }
 
-func renderSession(session *input.Session, cfg *config.Config) string {
+// renderSession renders every line. A non-empty notice leads the first
+// line and is laid out with it, so it counts toward the line width.
+func renderSession(session *input.Session, cfg *config.Config, notice string) string {
+	render.SetWidths(cfg.General.AmbiguousWidth, cfg.General.PUAWidth)
+	render.SetHyperlinks(cfg.General.Hyperlinks)
+
+	if len(cfg.Lines) == 0 {
+		return render.Layout([]string{notice}, cfg.General.Separator)
+	}
+
 	var result []string
 
-	for _, line := range cfg.Lines {
+	for i, line := range cfg.Lines {
 		var lineOutput string
+		var lead []string
+		if i == 0 && notice != "" {
+			lead = []string{notice}
+		}
 
 		// Check if this is a split layout (left/right defined)
 		if len(line.Left) > 0 || len(line.Right) > 0 {
-			leftRendered := widgets.RenderAll(session, line.Left)
+			leftRendered := append(lead, widgets.RenderAll(session, line.Left)...)
 			rightRendered := widgets.RenderAll(session, line.Right)
 			lineOutput = render.SplitLayout(leftRendered, rightRendered, cfg.General.Separator)
 		} else {
 			// Regular layout
-			rendered := widgets.RenderAll(session, line.Widgets)
+			rendered := append(lead, widgets.RenderAll(session, line.Widgets)...)
 			lineOutput = render.Layout(rendered, cfg.General.Separator)
 		}
 
@@ -229,6 +453,27 @@ export CLAUDE_STATUSLINE_COMMAND="visor"
 3. Optionally customize with: visor --init`)
 }
 
+// printInputDiagnostics reports stdin schema drift to stderr (--debug).
+func printInputDiagnostics(diag *input.Diagnostics) {
+	if diag.Err != nil {
+		fmt.Fprintf(os.Stderr, "[visor] stdin: invalid JSON, rendering empty session: %v\n", diag.Err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "[visor] stdin: schema=%s\n", diag.SchemaVersion)
+	if len(diag.Missing) > 0 {
+		fmt.Fprintf(os.Stderr, "[visor] stdin: missing fields: %s\n", strings.Join(diag.Missing, ", "))
+	}
+	if len(diag.Invalid) > 0 {
+		fmt.Fprintf(os.Stderr, "[visor] stdin: fields with unexpected types (ignored): %s\n", strings.Join(diag.Invalid, ", "))
+	}
+	if len(diag.Coerced) > 0 {
+		fmt.Fprintf(os.Stderr, "[visor] stdin: converted fields: %s\n", strings.Join(diag.Coerced, ", "))
+	}
+	if len(diag.Unknown) > 0 {
+		fmt.Fprintf(os.Stderr, "[visor] stdin: unknown fields (available as {raw.<path>}): %s\n", strings.Join(diag.Unknown, ", "))
+	}
+}
+
 // loadCostData loads aggregated cost data from JSONL transcripts.
 func loadCostData(session *input.Session, hist *history.History, cfg *config.Config, debug bool) *cost.CostData {
 	// Parse cost entries from ALL sessions for accurate daily/weekly aggregation
@@ -237,8 +482,19 @@ func loadCostData(session *input.Session, hist *history.History, cfg *config.Con
 		fmt.Fprintf(os.Stderr, "[visor] cost parsing error: %v\n", err)
 	}
 
-	// Get block start time from history
+	// Reconstruct the current 5-hour block from transcript activity.
+	// The history-based start is only a fallback when there is none.
+	blocks := cost.DetectBlocks(entries)
+	if start := cost.CurrentBlockStart(blocks, time.Now()); !start.IsZero() {
+		hist.BlockStartTime = start.UnixMilli()
+		if err := history.SaveGlobalBlockStart(hist.BlockStartTime); err != nil && debug {
+			fmt.Fprintf(os.Stderr, "[visor] failed to save global block start: %v\n", err)
+		}
+	}
 	blockStart := hist.GetBlockStartTime()
+	if debug {
+		fmt.Fprintf(os.Stderr, "[visor] block start: %s (%d blocks detected)\n", blockStart.Format(time.RFC3339), len(blocks))
+	}
 
 	// Aggregate the data
 	data := cost.Aggregate(entries, blockStart)
@@ -256,49 +512,85 @@ func loadCostData(session *input.Session, hist *history.History, cfg *config.Con
 	return data
 }
 
-// loadUsageLimits loads usage limits from the OAuth API, falling back to local estimation.
+// loadUsageLimits tries the configured usage limit sources in order ([usage] limit_sources).
+// The OAuth source never blocks on the network; a due refresh runs in the background.
 // Only subscription users (claude_pro provider) get local estimation; API key users are skipped.
-func loadUsageLimits(costData *cost.CostData, hist *history.History, cfg *config.Config, debug bool) *usage.Limits {
-	// Try API first
-	provider := auth.DefaultProvider()
-	client := usage.NewClient(provider)
-
-	limits, err := client.GetLimits()
-	if err == nil {
-		if debug {
-			fmt.Fprintf(os.Stderr, "[visor] usage limits (API): 5h=%.0f%%, 7d=%.0f%%\n",
-				limits.FiveHour.Utilization, limits.SevenDay.Utilization)
-		}
-		return limits
+func loadUsageLimits(fetcher *usage.CachedFetcher, provider *auth.RefreshingProvider, session *input.Session, costData *cost.CostData, hist *history.History, cfg *config.Config, debug bool) *usage.Limits {
+	order := cfg.Usage.LimitSources
+	if len(order) == 0 {
+		order = usage.DefaultSourceOrder
 	}
 
-	if debug {
-		fmt.Fprintf(os.Stderr, "[visor] usage API error: %v (falling back to local)\n", err)
+	var sources []usage.LimitsSource
+	for _, name := range order {
+		switch name {
+		case usage.SourceOAuth:
+			sources = append(sources, &usage.OAuthSource{Fetcher: fetcher})
+		case usage.SourceHeaders:
+			sources = append(sources, &usage.HeaderSource{TranscriptPath: session.TranscriptPath})
+		case usage.SourceFile:
+			sources = append(sources, &usage.FileSource{Path: cfg.Usage.LimitsFile, TTL: time.Duration(cfg.Usage.CacheTTL) * time.Second})
+		case usage.SourceCommand:
+			sources = append(sources, &usage.CommandSource{Command: cfg.Usage.LimitsCommand})
+		case usage.SourceLocal:
+			// Local estimation only makes sense for subscription users (Pro/Max/Team).
+			// API key users (anthropic, aws, gcp) have token-based billing, not message limits.
+			if costData.Provider != cost.ProviderClaudePro && costData.Provider != cost.ProviderUnknown {
+				if debug {
+					fmt.Fprintf(os.Stderr, "[visor] skipping local usage estimation for provider: %s\n", costData.Provider)
+				}
+				continue
+			}
+
+			// Resolve tier once here to avoid redundant keychain lookups inside EstimateLimits.
+			tier := ""
+			creds, credErr := provider.Peek()
+			if credErr == nil && creds != nil {
+				tier = creds.RateLimitTier
+			}
+
+			// Learn per-user limits from limit hits seen in transcripts
+			calib := usage.LoadCalibration("")
+			if calib.Record(costData.LimitHits, tier) {
+				if err := calib.Save(); err != nil && debug {
+					fmt.Fprintf(os.Stderr, "[visor] failed to save limit calibration: %v\n", err)
+				}
+			}
+			if debug {
+				if learned, ok := calib.FiveHourLimit(tier); ok {
+					fmt.Fprintf(os.Stderr, "[visor] learned 5h limit: messages=%d, weighted=%.0f (samples=%d)\n",
+						learned.Messages, learned.Weighted, learned.Samples)
+				}
+			}
+
+			sources = append(sources, &usage.LocalSource{
+				CostData:      costData,
+				BlockStart:    hist.GetBlockStartTime(),
+				Tier:          tier,
+				FiveHourLimit: cfg.Usage.FiveHourLimit,
+				SevenDayLimit: cfg.Usage.SevenDayLimit,
+				Calibration:   calib,
+			})
+		default:
+			if debug {
+				fmt.Fprintf(os.Stderr, "[visor] unknown usage limit source: %q\n", name)
+			}
+		}
 	}
 
-	// Local estimation only makes sense for subscription users (Pro/Max/Team).
-	// API key users (anthropic, aws, gcp) have token-based billing, not message limits.
-	if costData.Provider != cost.ProviderClaudePro && costData.Provider != cost.ProviderUnknown {
+	limits, err := usage.FirstAvailable(sources)
+	if err != nil {
 		if debug {
-			fmt.Fprintf(os.Stderr, "[visor] skipping local usage estimation for provider: %s\n", costData.Provider)
+			fmt.Fprintf(os.Stderr, "[visor] no usage limits: %v\n", err)
 		}
 		return nil
 	}
 
-	// Resolve tier once here to avoid redundant keychain lookups inside EstimateLimits.
-	tier := ""
-	creds, credErr := provider.Get()
-	if credErr == nil && creds != nil {
-		tier = creds.RateLimitTier
-	}
-
-	// Fallback: local estimation from JSONL message counts
-	blockStart := hist.GetBlockStartTime()
-	limits = usage.EstimateLimits(costData, blockStart, tier, cfg.Usage.FiveHourLimit, cfg.Usage.SevenDayLimit)
-	if limits != nil && debug {
-		fmt.Fprintf(os.Stderr, "[visor] usage limits (local): 5h=%.0f%% (%d/%d), 7d=%.0f%% (%d/%d), tier=%q\n",
-			limits.FiveHour.Utilization, costData.FiveHourBlockMessages, limits.FiveHour.Total,
-			limits.SevenDay.Utilization, costData.WeekMessages, limits.SevenDay.Total, tier)
+	if debug {
+		fmt.Fprintf(os.Stderr, "[visor] usage limits (source=%s): 5h=%.0f%% (total=%d), 7d=%.0f%% (total=%d), fetched=%s, stale=%v\n",
+			limits.Source, limits.FiveHour.Utilization, limits.FiveHour.Total,
+			limits.SevenDay.Utilization, limits.SevenDay.Total,
+			limits.FetchedAt.Format(time.RFC3339), limits.Stale)
 	}
 
 	return limits
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/git"
+	"github.com/namyoungkim/visor/internal/history"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/render"
+	"github.com/namyoungkim/visor/internal/replay"
+	"github.com/namyoungkim/visor/internal/transcript"
+	"github.com/namyoungkim/visor/internal/widgets"
+)
+
+// maxPlaybackGap caps the pause between frames in --play mode, so idle
+// stretches of a recorded session don't stall playback.
+const maxPlaybackGap = 2 * time.Second
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// recordFrame appends the stdin payload and rendered output to dir (--record).
+func recordFrame(dir, sessionID string, stdin []byte, output string, debug bool) {
+	frame := replay.NewFrame(time.Now(), stdin, output)
+	if err := replay.Append(dir, sessionID, frame); err != nil && debug {
+		fmt.Fprintf(os.Stderr, "[visor] failed to record payload: %v\n", err)
+	}
+}
+
+// runReplay implements `visor replay [options] <file|dir>`: it re-renders
+// recorded payloads against the current config.
+func runReplay(args []string, globalPath string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: visor replay [options] <file|dir>")
+		fs.PrintDefaults()
+	}
+	play := fs.Bool("play", false, "Animate frames in the terminal at the recorded pace")
+	speed := fs.Float64("speed", 1, "Playback speed multiplier for --play")
+	diffOnly := fs.Bool("diff", false, "Only show frames whose output differs from the recording")
+	configPath := fs.String("config", globalPath, "Global config file to render with (default: $VISOR_CONFIG, then ~/.config/visor/config.toml)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected one recording file or directory")
+	}
+	if *speed <= 0 {
+		return fmt.Errorf("invalid speed %v: must be positive", *speed)
+	}
+
+	frames, err := replay.Load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	// Layered config depends on the session directory (project .visor.toml)
+	configs := make(map[string]*config.Config)
+	// History is rebuilt in memory per session, never saved
+	hists := make(map[string]*history.History)
+	changed := 0
+
+	for i, frame := range frames {
+		session := input.Parse(bytes.NewReader(frame.Stdin()))
+		dir := session.GetCurrentDir()
+		cfg, ok := configs[dir]
+		if !ok {
+			layered, err := loadConfig(*configPath, dir)
+			if err != nil {
+				return fmt.Errorf("config: %w", err)
+			}
+			cfg = layered.Config
+			configs[dir] = cfg
+		}
+		hist, ok := hists[session.SessionID]
+		if !ok {
+			hist = &history.History{SessionID: session.SessionID}
+			hists[session.SessionID] = hist
+		}
+		output := renderFrame(session, hist, frame, cfg)
+		differs := output != frame.Output
+		if differs {
+			changed++
+		}
+
+		header := fmt.Sprintf("── %d/%d %s (%s)", i+1, len(frames),
+			frame.Time.Local().Format("2006-01-02 15:04:05"), filepath.Base(frame.Source))
+		if differs {
+			header += " changed"
+		}
+		header = render.Colorize(header+" ──", "gray")
+
+		switch {
+		case *play:
+			if i > 0 {
+				gap := time.Duration(float64(frame.Time.Sub(frames[i-1].Time)) / *speed)
+				time.Sleep(max(0, min(gap, maxPlaybackGap)))
+			}
+			fmt.Print(clearScreen + header + "\n" + output + "\n")
+		case *diffOnly:
+			if differs {
+				fmt.Println(header)
+				fmt.Println(prefixLines("- ", frame.Output))
+				fmt.Println(prefixLines("+ ", output))
+			}
+		default:
+			fmt.Println(header)
+			fmt.Println(output)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%d of %d frames differ from the recording\n", changed, len(frames))
+	return nil
+}
+
+// renderFrame renders one recorded payload. Session-local data (history,
+// transcript, git) is rebuilt from the payload; cost and usage limits are
+// not, since they would reflect today rather than the recorded moment.
+func renderFrame(session *input.Session, hist *history.History, frame replay.Frame, cfg *config.Config) string {
+	if dir := session.GetCurrentDir(); dir != "" {
+		git.SetWorkDir(dir)
+	}
+
+	entry := historyEntry(session)
+	entry.Timestamp = frame.Time.Unix()
+	hist.Add(entry)
+	widgets.SetHistory(hist)
+	widgets.SetTranscript(transcript.Parse(session.TranscriptPath))
+
+	return renderSession(session, cfg, "")
+}
+
+// prefixLines prefixes every line of s.
+func prefixLines(prefix, s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
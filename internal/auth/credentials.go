@@ -2,6 +2,7 @@ package auth
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 )
@@ -91,3 +92,39 @@ func DefaultProvider() CredentialProvider {
 	// Fall back to platform-specific provider (keychain, etc.)
 	return platformProvider()
 }
+
+// Credential sources for [usage] credential_source.
+const (
+	SourceAuto          = "auto"
+	SourceFile          = "file"
+	SourceKeychain      = "keychain"
+	SourceSecretService = "secret_service"
+	SourceEncryptedFile = "encrypted_file"
+)
+
+// ProviderForSource returns the credential provider for a configured source.
+// An empty source or "auto" uses DefaultProvider.
+func ProviderForSource(source string) (CredentialProvider, error) {
+	switch source {
+	case "", SourceAuto:
+		return DefaultProvider(), nil
+	case SourceFile:
+		return NewFileProvider(), nil
+	case SourceKeychain:
+		return keychainSource()
+	case SourceSecretService:
+		return NewSecretServiceProvider(), nil
+	case SourceEncryptedFile:
+		return NewEncryptedFileProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown credential source: %q", source)
+	}
+}
+
+// currentUsername returns the current OS username.
+func currentUsername() string {
+	if u := os.Getenv("USER"); u != "" {
+		return u
+	}
+	return "unknown"
+}
@@ -5,7 +5,6 @@ package auth
 import (
 	"bytes"
 	"context"
-	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -19,6 +18,11 @@ func platformProvider() CredentialProvider {
 	return &keychainProvider{}
 }
 
+// keychainSource returns the provider for credential_source = "keychain".
+func keychainSource() (CredentialProvider, error) {
+	return &keychainProvider{}, nil
+}
+
 // keychainEntries defines the keychain service/account pairs to try, in order.
 // Claude Code has changed its keychain entry format over time.
 var keychainEntries = []struct {
@@ -79,11 +83,3 @@ func readKeychain(service, account string) (*Credentials, error) {
 
 	return parseCredentialJSON([]byte(password))
 }
-
-// currentUsername returns the current OS username.
-func currentUsername() string {
-	if u := os.Getenv("USER"); u != "" {
-		return u
-	}
-	return "unknown"
-}
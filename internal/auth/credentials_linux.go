@@ -2,13 +2,15 @@
 
 package auth
 
-// linuxProvider is a placeholder for Linux credential access.
-// On Linux, credentials are typically stored in the file system
-// rather than a system keychain.
-type linuxProvider struct{}
-
 // platformProvider returns the Linux-specific credential provider.
+// Only the credentials file is used, so rendering never spawns secret-tool;
+// the keyring and the encrypted file are read when credential_source
+// selects them.
 func platformProvider() CredentialProvider {
-	// On Linux, we rely on file-based credentials
 	return NewFileProvider()
 }
+
+// keychainSource returns the provider for credential_source = "keychain".
+func keychainSource() (CredentialProvider, error) {
+	return nil, ErrUnsupportedSource
+}
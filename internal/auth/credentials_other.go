@@ -6,3 +6,8 @@ package auth
 func platformProvider() CredentialProvider {
 	return NewFileProvider()
 }
+
+// keychainSource returns the provider for credential_source = "keychain".
+func keychainSource() (CredentialProvider, error) {
+	return nil, ErrUnsupportedSource
+}
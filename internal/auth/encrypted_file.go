@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// CredentialKeyEnv holds the passphrase for the encrypted credential file.
+	CredentialKeyEnv = "VISOR_CREDENTIAL_KEY"
+
+	encryptedFileVersion = 1
+	kdfIterations        = 200_000
+	keySize              = 32
+	saltSize             = 16
+)
+
+// ErrDecrypt is returned when the encrypted credential file cannot be decrypted.
+var ErrDecrypt = errors.New("cannot decrypt credential file (wrong key?)")
+
+// deriveKeyFunc derives the file key. Can be overridden in tests.
+var deriveKeyFunc = deriveKey
+
+// encryptedFile is the on-disk format of the encrypted credential file.
+type encryptedFile struct {
+	Version    int    `json:"v"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"data"`
+}
+
+// EncryptedFileProvider reads credentials from an AES-256-GCM encrypted file.
+// It is a fallback for machines without a keyring daemon.
+//
+// The key is derived from $VISOR_CREDENTIAL_KEY. Without it, a machine- and
+// user-bound key is used, which keeps the file unreadable if copied elsewhere
+// (e.g. in backups) but does not protect against the same user on this host.
+//
+// The derived key is kept in memory only, so repeated reads within one run
+// pay for PBKDF2 once. It is never written to disk, where it would decrypt
+// the file without the passphrase.
+type EncryptedFileProvider struct {
+	Path       string
+	passphrase func() string
+
+	mu            sync.Mutex
+	keyPassphrase string // Passphrase and salt the cached key was derived from
+	keySalt       []byte
+	key           []byte
+}
+
+// NewEncryptedFileProvider creates a provider for ~/.config/visor/credentials.enc.
+func NewEncryptedFileProvider() *EncryptedFileProvider {
+	p := &EncryptedFileProvider{passphrase: defaultPassphrase}
+	if home, err := os.UserHomeDir(); err == nil {
+		p.Path = filepath.Join(home, ".config", "visor", "credentials.enc")
+	}
+	return p
+}
+
+// Get decrypts and returns the stored credentials.
+func (p *EncryptedFileProvider) Get() (*Credentials, error) {
+	if p.Path == "" {
+		return nil, ErrNoCredentials
+	}
+
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoCredentials
+		}
+		return nil, err
+	}
+
+	var f encryptedFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid credential file: %w", err)
+	}
+	if f.Version != encryptedFileVersion {
+		return nil, fmt.Errorf("unsupported credential file version %d", f.Version)
+	}
+
+	plain, err := open(p.fileKey(p.passphrase(), f.Salt), f)
+	if err != nil {
+		return nil, err
+	}
+	return parseCredentialJSON(plain)
+}
+
+// fileKey returns the key for passphrase and salt, deriving it only when
+// they differ from the last call.
+func (p *EncryptedFileProvider) fileKey(passphrase string, salt []byte) []byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.key == nil || p.keyPassphrase != passphrase || !bytes.Equal(p.keySalt, salt) {
+		p.key = deriveKeyFunc([]byte(passphrase), salt)
+		p.keyPassphrase, p.keySalt = passphrase, salt
+	}
+	return p.key
+}
+
+// open decrypts the ciphertext of f with key.
+func open(key []byte, f encryptedFile) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, f.Nonce, f.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+// Save encrypts credentials (any JSON accepted by parseCredentialJSON)
+// and writes them with owner-only permissions.
+func (p *EncryptedFileProvider) Save(credentialJSON []byte) error {
+	if p.Path == "" {
+		return ErrNoCredentials
+	}
+	if _, err := parseCredentialJSON(credentialJSON); err != nil {
+		return fmt.Errorf("invalid credentials: %w", err)
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	gcm, err := newGCM(deriveKeyFunc([]byte(p.passphrase()), salt))
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	return writeCredentialFile(p.Path, encryptedFile{
+		Version:    encryptedFileVersion,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, credentialJSON, nil),
+	})
+}
+
+// newGCM returns an AES-GCM cipher for key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// deriveKey derives the file key from passphrase and salt with
+// PBKDF2-HMAC-SHA256.
+func deriveKey(passphrase, salt []byte) []byte {
+	return pbkdf2.Key(passphrase, salt, kdfIterations, keySize, sha256.New)
+}
+
+// defaultPassphrase returns $VISOR_CREDENTIAL_KEY, or a key bound to this
+// machine and user when it is unset.
+func defaultPassphrase() string {
+	if key := os.Getenv(CredentialKeyEnv); key != "" {
+		return key
+	}
+	machineID, _ := os.ReadFile("/etc/machine-id")
+	home, _ := os.UserHomeDir()
+	return "visor:" + strings.TrimSpace(string(machineID)) + ":" + currentUsername() + ":" + home
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestEncryptedProvider(t *testing.T, key string) *EncryptedFileProvider {
+	t.Helper()
+	dir := t.TempDir()
+	return &EncryptedFileProvider{
+		Path:       filepath.Join(dir, "credentials.enc"),
+		passphrase: func() string { return key },
+	}
+}
+
+func TestEncryptedFileProvider_RoundTrip(t *testing.T) {
+	p := newTestEncryptedProvider(t, "correct horse")
+	secret := `{"claudeAiOauth":{"accessToken":"enc-tok","refreshToken":"enc-ref","subscriptionType":"pro"}}`
+
+	if err := p.Save([]byte(secret)); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(p.Path)
+	if strings.Contains(string(data), "enc-tok") || strings.Contains(string(data), "enc-ref") {
+		t.Error("credential file contains plaintext tokens")
+	}
+	info, _ := os.Stat(p.Path)
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	creds, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if creds.AccessToken != "enc-tok" || creds.SubscriptionType != "pro" {
+		t.Errorf("creds = %+v", creds)
+	}
+}
+
+func TestEncryptedFileProvider_WrongKey(t *testing.T) {
+	p := newTestEncryptedProvider(t, "right")
+	if err := p.Save([]byte(`{"accessToken":"tok"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	p.passphrase = func() string { return "wrong" }
+	if _, err := p.Get(); err != ErrDecrypt {
+		t.Errorf("Get() error = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestEncryptedFileProvider_Missing(t *testing.T) {
+	p := newTestEncryptedProvider(t, "key")
+	if _, err := p.Get(); err != ErrNoCredentials {
+		t.Errorf("Get() error = %v, want ErrNoCredentials", err)
+	}
+}
+
+func TestEncryptedFileProvider_RejectsInvalidCredentials(t *testing.T) {
+	p := newTestEncryptedProvider(t, "key")
+	if err := p.Save([]byte(`{}`)); err == nil {
+		t.Error("Save() should reject credentials without an access token")
+	}
+}
+
+func TestEncryptedFileProvider_ReadsExistingFiles(t *testing.T) {
+	// Written before the KDF moved to x/crypto/pbkdf2
+	p := newTestEncryptedProvider(t, "old key")
+	file := `{"data":"N9btL20HBeA8xLI20fG3t/I/DcTlxbb7iqnAI2oVK0KJAU1VcIW9x9E=","nonce":"bm9uY2UtMTJieXRl","salt":"MDEyMzQ1Njc4OWFiY2RlZg==","v":1}`
+	if err := os.WriteFile(p.Path, []byte(file), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	creds, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if creds.AccessToken != "old-tok" {
+		t.Errorf("AccessToken = %q, want old-tok", creds.AccessToken)
+	}
+}
+
+func TestEncryptedFileProvider_CachesDerivedKey(t *testing.T) {
+	derivations := 0
+	deriveKeyFunc = func(passphrase, salt []byte) []byte {
+		derivations++
+		return deriveKey(passphrase, salt)
+	}
+	t.Cleanup(func() { deriveKeyFunc = deriveKey })
+
+	p := newTestEncryptedProvider(t, "correct horse")
+	if err := p.Save([]byte(`{"accessToken":"tok"}`)); err != nil {
+		t.Fatal(err)
+	}
+	derivations = 0
+
+	for i := 0; i < 3; i++ {
+		if creds, err := p.Get(); err != nil || creds.AccessToken != "tok" {
+			t.Fatalf("Get() = %+v, %v", creds, err)
+		}
+	}
+	if derivations != 1 {
+		t.Errorf("key derived %d times for 3 reads, want 1", derivations)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(p.Path)); len(entries) != 1 {
+		t.Errorf("dir has %d entries, want only the credential file", len(entries))
+	}
+
+	// A different passphrase doesn't get the cached key
+	p.passphrase = func() string { return "wrong" }
+	if _, err := p.Get(); err != ErrDecrypt {
+		t.Errorf("Get() with wrong key error = %v, want ErrDecrypt", err)
+	}
+
+	// A rewritten file has a new salt and needs a new key
+	p.passphrase = func() string { return "correct horse" }
+	if err := p.Save([]byte(`{"accessToken":"tok2"}`)); err != nil {
+		t.Fatal(err)
+	}
+	derivations = 0
+	if creds, err := p.Get(); err != nil || creds.AccessToken != "tok2" {
+		t.Fatalf("Get() after Save = %+v, %v", creds, err)
+	}
+	if derivations != 1 {
+		t.Errorf("key derived %d times after Save, want 1", derivations)
+	}
+}
@@ -10,3 +10,6 @@ var ErrExpiredCredentials = errors.New("credentials have expired")
 
 // ErrKeychainAccess is returned when keychain access fails.
 var ErrKeychainAccess = errors.New("keychain access denied")
+
+// ErrUnsupportedSource is returned when a credential source is not available on this platform.
+var ErrUnsupportedSource = errors.New("credential source not supported on this platform")
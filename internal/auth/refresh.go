@@ -0,0 +1,404 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTokenURL is the OAuth token endpoint used by Claude Code.
+	DefaultTokenURL = "https://console.anthropic.com/v1/oauth/token"
+
+	// DefaultClientID is Claude Code's public OAuth client ID.
+	DefaultClientID = "9d1c250a-e61b-44d9-88ed-5944d1962f5e"
+
+	// expirySkew treats tokens as expired slightly early so a request
+	// started just before expiry does not fail mid-flight.
+	expirySkew = 60 * time.Second
+
+	// refreshTimeout bounds the refresh-token exchange.
+	refreshTimeout = 5 * time.Second
+
+	refreshedFileName = "oauth_credentials.json"
+)
+
+// Expired reports whether the access token has expired at now.
+// Credentials without an expiry are treated as valid.
+func (c *Credentials) Expired(now time.Time) bool {
+	if c.ExpiresAt == 0 {
+		return false
+	}
+	return now.Add(expirySkew).UnixMilli() >= c.ExpiresAt
+}
+
+// ExpiresIn returns the time until the access token expires.
+// Returns 0 if the token has no expiry or has already expired.
+func (c *Credentials) ExpiresIn(now time.Time) time.Duration {
+	if c.ExpiresAt == 0 {
+		return 0
+	}
+	d := time.UnixMilli(c.ExpiresAt).Sub(now)
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// State describes the current authentication state.
+type State string
+
+const (
+	StateNone      State = "none"      // No credentials found
+	StateValid     State = "valid"     // Access token is valid
+	StateRefreshed State = "refreshed" // Token was refreshed by visor
+	StateExpired   State = "expired"   // Token expired and could not be refreshed
+	StateError     State = "error"     // Credential store could not be read
+)
+
+// Status is a snapshot of authentication state for display.
+type Status struct {
+	State     State
+	ExpiresAt time.Time // Zero if unknown
+	Err       error
+}
+
+// RefreshOptions configures a RefreshingProvider.
+type RefreshOptions struct {
+	// TokenURL is the OAuth token endpoint. Defaults to DefaultTokenURL.
+	TokenURL string
+
+	// ClientID is the OAuth client ID. Defaults to DefaultClientID.
+	ClientID string
+
+	// Dir stores tokens refreshed by visor. Defaults to ~/.cache/visor.
+	Dir string
+
+	// Disabled turns off refresh-token exchange; expired tokens are reported as-is.
+	Disabled bool
+
+	// WriteBack writes refreshed tokens to the underlying provider's
+	// credential file. Refresh tokens rotate on use, so an exchange whose new
+	// token Claude Code never sees logs Claude Code out; without WriteBack,
+	// expired tokens are reported as-is. Only file-based providers support it.
+	WriteBack bool
+}
+
+// RefreshingProvider wraps a CredentialProvider and refreshes expired
+// access tokens with the refresh token. It refreshes only when the new
+// tokens can be written back to the store they came from.
+//
+// Refreshed tokens are stored in visor's own file (0600) and used while the
+// base credentials remain expired, so a refresh is not repeated on every
+// statusline render.
+type RefreshingProvider struct {
+	base       CredentialProvider
+	opts       RefreshOptions
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu        sync.Mutex
+	loaded    bool
+	creds     *Credentials
+	loadErr   error
+	refreshed bool
+}
+
+// NewRefreshingProvider creates a provider that refreshes base's tokens.
+func NewRefreshingProvider(base CredentialProvider, opts RefreshOptions) *RefreshingProvider {
+	if base == nil {
+		base = DefaultProvider()
+	}
+	if opts.TokenURL == "" {
+		opts.TokenURL = DefaultTokenURL
+	}
+	if opts.ClientID == "" {
+		opts.ClientID = DefaultClientID
+	}
+	if opts.Dir == "" {
+		opts.Dir = defaultRefreshDir()
+	}
+	return &RefreshingProvider{
+		base:       base,
+		opts:       opts,
+		httpClient: &http.Client{Timeout: refreshTimeout},
+		now:        time.Now,
+	}
+}
+
+// Get returns valid credentials, refreshing them first if they have expired.
+// Returns ErrExpiredCredentials if the token expired and refresh failed.
+func (p *RefreshingProvider) Get() (*Credentials, error) {
+	creds, err := p.Peek()
+	if err != nil {
+		return nil, err
+	}
+	if !creds.Expired(p.now()) {
+		return creds, nil
+	}
+
+	if !p.canRefresh() || creds.RefreshToken == "" {
+		return nil, ErrExpiredCredentials
+	}
+
+	// The exchange runs without the lock so Status and Peek never wait on the network.
+	fresh, err := p.exchange(creds)
+	if err != nil {
+		return nil, fmt.Errorf("%w: refresh failed: %v", ErrExpiredCredentials, err)
+	}
+
+	// Persistence failures are not fatal; the token is still usable now.
+	_ = p.saveRefreshed(fresh)
+	_ = p.writeBack(fresh)
+
+	p.mu.Lock()
+	p.creds = fresh
+	p.refreshed = true
+	p.mu.Unlock()
+	return fresh, nil
+}
+
+// canRefresh reports whether refreshed tokens can reach the base store.
+// Refreshing otherwise spends a refresh token the base store still holds.
+func (p *RefreshingProvider) canRefresh() bool {
+	if p.opts.Disabled || !p.opts.WriteBack {
+		return false
+	}
+	switch base := p.base.(type) {
+	case *EncryptedFileProvider:
+		return base.Path != ""
+	case *FileCredentialProvider:
+		return base.Path != ""
+	}
+	return false
+}
+
+// Peek returns the current credentials without refreshing them.
+// Useful for metadata such as RateLimitTier, which survives token expiry.
+func (p *RefreshingProvider) Peek() (*Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current()
+}
+
+// Status reports the authentication state without touching the network.
+func (p *RefreshingProvider) Status() Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	creds, err := p.current()
+	if err == ErrNoCredentials {
+		return Status{State: StateNone}
+	}
+	if err != nil {
+		return Status{State: StateError, Err: err}
+	}
+
+	status := Status{State: StateValid}
+	if creds.ExpiresAt > 0 {
+		status.ExpiresAt = time.UnixMilli(creds.ExpiresAt)
+	}
+	if creds.Expired(p.now()) {
+		status.State = StateExpired
+	} else if p.refreshed {
+		status.State = StateRefreshed
+	}
+	return status
+}
+
+// current returns the best known credentials, loading them once per process.
+// Caller must hold p.mu.
+func (p *RefreshingProvider) current() (*Credentials, error) {
+	if !p.loaded {
+		p.creds, p.loadErr = p.load()
+		p.loaded = true
+	}
+	return p.creds, p.loadErr
+}
+
+// load reads base credentials. visor's refreshed token is used only when the
+// base token has expired and the refreshed one expires later, so a new login
+// (or logout) in Claude Code always takes precedence.
+func (p *RefreshingProvider) load() (*Credentials, error) {
+	base, err := p.base.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	if stored := p.loadRefreshed(); stored != nil && base.Expired(p.now()) && stored.ExpiresAt > base.ExpiresAt {
+		p.refreshed = true
+		return stored, nil
+	}
+	return base, nil
+}
+
+// tokenRequest is the refresh-token grant request body.
+type tokenRequest struct {
+	GrantType    string `json:"grant_type"`
+	RefreshToken string `json:"refresh_token"`
+	ClientID     string `json:"client_id"`
+}
+
+// tokenResponse is the token endpoint response.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // seconds
+}
+
+// exchange trades the refresh token for a new access token.
+func (p *RefreshingProvider) exchange(creds *Credentials) (*Credentials, error) {
+	body, err := json.Marshal(tokenRequest{
+		GrantType:    "refresh_token",
+		RefreshToken: creds.RefreshToken,
+		ClientID:     p.opts.ClientID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", p.opts.TokenURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "visor")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Body may echo the token; only report the status.
+		io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("token endpoint returned %d", resp.StatusCode)
+	}
+
+	var tr tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tr.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access_token")
+	}
+
+	fresh := *creds
+	fresh.AccessToken = tr.AccessToken
+	if tr.RefreshToken != "" {
+		fresh.RefreshToken = tr.RefreshToken
+	}
+	if tr.ExpiresIn > 0 {
+		fresh.ExpiresAt = p.now().Add(time.Duration(tr.ExpiresIn) * time.Second).UnixMilli()
+	} else {
+		fresh.ExpiresAt = 0
+	}
+	return &fresh, nil
+}
+
+// refreshedPath returns the path of visor's refreshed-token file.
+func (p *RefreshingProvider) refreshedPath() string {
+	return filepath.Join(p.opts.Dir, refreshedFileName)
+}
+
+// loadRefreshed reads visor's refreshed token, or nil if absent.
+func (p *RefreshingProvider) loadRefreshed() *Credentials {
+	data, err := os.ReadFile(p.refreshedPath())
+	if err != nil {
+		return nil
+	}
+	creds, err := parseCredentialJSON(data)
+	if err != nil {
+		return nil
+	}
+	return creds
+}
+
+// saveRefreshed writes the refreshed token to visor's own file.
+func (p *RefreshingProvider) saveRefreshed(creds *Credentials) error {
+	return writeCredentialFile(p.refreshedPath(), creds)
+}
+
+// writeBack writes refreshed credentials to the base provider's file,
+// keeping the {"claudeAiOauth": {...}} envelope and any other keys intact.
+// The encrypted file is re-encrypted; keyring-backed providers are never modified.
+func (p *RefreshingProvider) writeBack(creds *Credentials) error {
+	if ep, ok := p.base.(*EncryptedFileProvider); ok {
+		data, err := json.Marshal(creds)
+		if err != nil {
+			return err
+		}
+		return ep.Save(data)
+	}
+
+	fp, ok := p.base.(*FileCredentialProvider)
+	if !ok || fp.Path == "" {
+		return fmt.Errorf("write-back is only supported for file credentials")
+	}
+
+	existing, err := os.ReadFile(fp.Path)
+	if err != nil {
+		return err
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(existing, &doc); err != nil {
+		return err
+	}
+	if _, nested := doc["claudeAiOauth"]; !nested {
+		return writeCredentialFile(fp.Path, creds)
+	}
+
+	// Merge into the existing object so unknown fields survive
+	var inner map[string]json.RawMessage
+	if err := json.Unmarshal(doc["claudeAiOauth"], &inner); err != nil || inner == nil {
+		inner = map[string]json.RawMessage{}
+	}
+	updated, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(updated, &fields); err != nil {
+		return err
+	}
+	for _, k := range []string{"accessToken", "refreshToken", "expiresAt"} {
+		inner[k] = fields[k]
+	}
+	if doc["claudeAiOauth"], err = json.Marshal(inner); err != nil {
+		return err
+	}
+	return writeCredentialFile(fp.Path, doc)
+}
+
+// writeCredentialFile writes v as JSON atomically with owner-only permissions.
+func writeCredentialFile(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// defaultRefreshDir returns ~/.cache/visor.
+func defaultRefreshDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return os.TempDir()
+	}
+	return filepath.Join(home, ".cache", "visor")
+}
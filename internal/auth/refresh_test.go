@@ -0,0 +1,250 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// writeCredsFile writes Claude Code-style credentials and returns a file provider.
+func writeCredsFile(t *testing.T, expiresAt int64) *FileCredentialProvider {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	data := `{"claudeAiOauth":{"accessToken":"old-tok","refreshToken":"ref-1","expiresAt":` +
+		jsonInt(expiresAt) + `,"subscriptionType":"max","scopes":["user:inference"]},"other":true}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return &FileCredentialProvider{Path: path}
+}
+
+func jsonInt(n int64) string {
+	b, _ := json.Marshal(n)
+	return string(b)
+}
+
+// newTokenServer returns a stub token endpoint responding with status.
+func newTokenServer(t *testing.T, status int, hits *int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		var req tokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil ||
+			req.GrantType != "refresh_token" || req.RefreshToken != "ref-1" || req.ClientID != "test-client" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			w.Write([]byte(`{"access_token":"new-tok","refresh_token":"ref-2","expires_in":3600}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestRefresher(base CredentialProvider, tokenURL, dir string) *RefreshingProvider {
+	return NewRefreshingProvider(base, RefreshOptions{
+		TokenURL:  tokenURL,
+		ClientID:  "test-client",
+		Dir:       dir,
+		WriteBack: true,
+	})
+}
+
+func TestCredentials_Expired(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		expiresAt int64
+		want      bool
+	}{
+		{"no expiry", 0, false},
+		{"future", now.Add(time.Hour).UnixMilli(), false},
+		{"past", now.Add(-time.Minute).UnixMilli(), true},
+		{"within skew", now.Add(30 * time.Second).UnixMilli(), true},
+	}
+	for _, tt := range tests {
+		c := &Credentials{ExpiresAt: tt.expiresAt}
+		if got := c.Expired(now); got != tt.want {
+			t.Errorf("%s: Expired() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	c := &Credentials{ExpiresAt: now.Add(-time.Hour).UnixMilli()}
+	if got := c.ExpiresIn(now); got != 0 {
+		t.Errorf("ExpiresIn() for expired = %v, want 0", got)
+	}
+}
+
+func TestRefreshingProvider_ValidTokenSkipsRefresh(t *testing.T) {
+	var hits int32
+	srv := newTokenServer(t, http.StatusOK, &hits)
+	base := writeCredsFile(t, time.Now().Add(time.Hour).UnixMilli())
+	p := newTestRefresher(base, srv.URL, t.TempDir())
+
+	creds, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if creds.AccessToken != "old-tok" {
+		t.Errorf("AccessToken = %q, want old-tok", creds.AccessToken)
+	}
+	if hits != 0 {
+		t.Errorf("token endpoint hits = %d, want 0", hits)
+	}
+	if st := p.Status(); st.State != StateValid {
+		t.Errorf("Status().State = %q, want %q", st.State, StateValid)
+	}
+}
+
+func TestRefreshingProvider_RefreshesExpiredToken(t *testing.T) {
+	var hits int32
+	srv := newTokenServer(t, http.StatusOK, &hits)
+	base := writeCredsFile(t, time.Now().Add(-time.Hour).UnixMilli())
+	dir := t.TempDir()
+	p := newTestRefresher(base, srv.URL, dir)
+
+	if st := p.Status(); st.State != StateExpired {
+		t.Errorf("Status().State before refresh = %q, want %q", st.State, StateExpired)
+	}
+
+	creds, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if creds.AccessToken != "new-tok" || creds.RefreshToken != "ref-2" {
+		t.Errorf("creds = %+v, want refreshed tokens", creds)
+	}
+	if creds.SubscriptionType != "max" {
+		t.Errorf("SubscriptionType = %q, want preserved %q", creds.SubscriptionType, "max")
+	}
+	if st := p.Status(); st.State != StateRefreshed || st.ExpiresAt.IsZero() {
+		t.Errorf("Status() = %+v, want refreshed with expiry", st)
+	}
+
+	// Refreshed token is stored in visor's own file with owner-only permissions
+	info, err := os.Stat(filepath.Join(dir, refreshedFileName))
+	if err != nil {
+		t.Fatalf("refreshed token not stored: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("stored token mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// The rotated refresh token reaches Claude Code's file
+	data, _ := os.ReadFile(base.Path)
+	if written, _ := parseCredentialJSON(data); written.RefreshToken != "ref-2" {
+		t.Errorf("base refresh token = %q, want ref-2", written.RefreshToken)
+	}
+
+	// A new process reuses the stored token instead of refreshing again
+	p2 := newTestRefresher(base, srv.URL, dir)
+	creds, err = p2.Get()
+	if err != nil || creds.AccessToken != "new-tok" {
+		t.Errorf("second provider Get() = %v, %v; want stored new-tok", creds, err)
+	}
+	if hits != 1 {
+		t.Errorf("token endpoint hits = %d, want 1", hits)
+	}
+}
+
+func TestRefreshingProvider_WriteBackKeepsEnvelope(t *testing.T) {
+	var hits int32
+	srv := newTokenServer(t, http.StatusOK, &hits)
+	base := writeCredsFile(t, time.Now().Add(-time.Hour).UnixMilli())
+	p := NewRefreshingProvider(base, RefreshOptions{
+		TokenURL:  srv.URL,
+		ClientID:  "test-client",
+		Dir:       t.TempDir(),
+		WriteBack: true,
+	})
+
+	if _, err := p.Get(); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(base.Path)
+	var doc struct {
+		ClaudeAiOauth map[string]any `json:"claudeAiOauth"`
+		Other         bool           `json:"other"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("written file is not valid JSON: %v", err)
+	}
+	if doc.ClaudeAiOauth["accessToken"] != "new-tok" {
+		t.Errorf("accessToken = %v, want new-tok", doc.ClaudeAiOauth["accessToken"])
+	}
+	if doc.ClaudeAiOauth["scopes"] == nil || !doc.Other {
+		t.Error("write-back dropped unrelated fields")
+	}
+}
+
+func TestRefreshingProvider_RefreshFailure(t *testing.T) {
+	var hits int32
+	srv := newTokenServer(t, http.StatusUnauthorized, &hits)
+	base := writeCredsFile(t, time.Now().Add(-time.Hour).UnixMilli())
+	p := newTestRefresher(base, srv.URL, t.TempDir())
+
+	_, err := p.Get()
+	if !errors.Is(err, ErrExpiredCredentials) {
+		t.Errorf("Get() error = %v, want ErrExpiredCredentials", err)
+	}
+	if st := p.Status(); st.State != StateExpired {
+		t.Errorf("Status().State = %q, want %q", st.State, StateExpired)
+	}
+}
+
+func TestRefreshingProvider_Disabled(t *testing.T) {
+	var hits int32
+	srv := newTokenServer(t, http.StatusOK, &hits)
+	base := writeCredsFile(t, time.Now().Add(-time.Hour).UnixMilli())
+	p := NewRefreshingProvider(base, RefreshOptions{TokenURL: srv.URL, Dir: t.TempDir(), Disabled: true})
+
+	if _, err := p.Get(); err != ErrExpiredCredentials {
+		t.Errorf("Get() error = %v, want ErrExpiredCredentials", err)
+	}
+	if hits != 0 {
+		t.Errorf("token endpoint hits = %d, want 0 when disabled", hits)
+	}
+}
+
+func TestRefreshingProvider_NoRefreshWithoutWriteBack(t *testing.T) {
+	var hits int32
+	srv := newTokenServer(t, http.StatusOK, &hits)
+	base := writeCredsFile(t, time.Now().Add(-time.Hour).UnixMilli())
+	p := NewRefreshingProvider(base, RefreshOptions{TokenURL: srv.URL, ClientID: "test-client", Dir: t.TempDir()})
+
+	if _, err := p.Get(); err != ErrExpiredCredentials {
+		t.Errorf("Get() error = %v, want ErrExpiredCredentials", err)
+	}
+	if hits != 0 {
+		t.Errorf("token endpoint hits = %d, want 0 without write-back", hits)
+	}
+
+	// Keyring stores can't be written back, so they are never refreshed
+	keyring := &SecretServiceProvider{lookup: (&fakeSecretService{items: map[string]string{
+		"Claude Code-credentials/alice": `{"claudeAiOauth":{"accessToken":"old-tok","refreshToken":"ref-1","expiresAt":1}}`,
+	}}).lookup}
+	t.Setenv("USER", "alice")
+	p = NewRefreshingProvider(keyring, RefreshOptions{TokenURL: srv.URL, ClientID: "test-client", Dir: t.TempDir(), WriteBack: true})
+	if _, err := p.Get(); err != ErrExpiredCredentials {
+		t.Errorf("Get() from keyring error = %v, want ErrExpiredCredentials", err)
+	}
+	if hits != 0 {
+		t.Errorf("token endpoint hits = %d, want 0 for keyring credentials", hits)
+	}
+}
+
+func TestRefreshingProvider_NoCredentials(t *testing.T) {
+	p := newTestRefresher(&FileCredentialProvider{}, "http://unused", t.TempDir())
+	if st := p.Status(); st.State != StateNone {
+		t.Errorf("Status().State = %q, want %q", st.State, StateNone)
+	}
+}
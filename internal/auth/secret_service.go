@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// secretLookupFunc looks up a secret by attribute key/value pairs.
+// Returns ErrNoCredentials if no item matches.
+type secretLookupFunc func(attrs ...string) ([]byte, error)
+
+// SecretServiceProvider reads credentials from the freedesktop Secret Service
+// (GNOME Keyring, KWallet) through libsecret's secret-tool, which talks to
+// the org.freedesktop.secrets D-Bus service.
+type SecretServiceProvider struct {
+	lookup secretLookupFunc
+}
+
+// NewSecretServiceProvider creates a provider backed by secret-tool.
+func NewSecretServiceProvider() *SecretServiceProvider {
+	return &SecretServiceProvider{lookup: secretToolLookup}
+}
+
+// secretServiceEntries defines the attribute sets to try, in order.
+// Mirrors the macOS keychain entries so the same item names work everywhere.
+var secretServiceEntries = []struct {
+	service string
+	account string
+}{
+	{"Claude Code-credentials", ""}, // account = OS username (auto-detected)
+	{"claude.ai", "oauth"},          // Legacy
+}
+
+// Get retrieves credentials from the Secret Service.
+func (p *SecretServiceProvider) Get() (*Credentials, error) {
+	var lastErr error = ErrNoCredentials
+	for _, entry := range secretServiceEntries {
+		account := entry.account
+		if account == "" {
+			account = currentUsername()
+		}
+
+		secret, err := p.lookup("service", entry.service, "account", account)
+		if err != nil {
+			// A locked or unreachable keyring is worth reporting over "not found"
+			if errors.Is(err, ErrKeychainAccess) {
+				lastErr = err
+			}
+			continue
+		}
+		if creds, err := parseCredentialJSON(secret); err == nil {
+			return creds, nil
+		}
+	}
+	return nil, lastErr
+}
+
+// secretToolLookup runs `secret-tool lookup attr value ...`.
+func secretToolLookup(attrs ...string) ([]byte, error) {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil, ErrNoCredentials
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "secret-tool", append([]string{"lookup"}, attrs...)...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// secret-tool exits 1 with empty stderr when nothing matches
+		msg := stderr.String()
+		if strings.Contains(msg, "locked") || strings.Contains(msg, "org.freedesktop") ||
+			strings.Contains(msg, "Cannot autolaunch") {
+			return nil, ErrKeychainAccess
+		}
+		return nil, ErrNoCredentials
+	}
+
+	secret := bytes.TrimSpace(stdout.Bytes())
+	if len(secret) == 0 {
+		return nil, ErrNoCredentials
+	}
+	return secret, nil
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeSecretService is an in-memory stand-in for the Secret Service keyring.
+type fakeSecretService struct {
+	items map[string]string // "service/account" → secret
+	err   error             // returned for every lookup when set
+	calls []string
+}
+
+func (f *fakeSecretService) lookup(attrs ...string) ([]byte, error) {
+	f.calls = append(f.calls, strings.Join(attrs, " "))
+	if f.err != nil {
+		return nil, f.err
+	}
+	var service, account string
+	for i := 0; i+1 < len(attrs); i += 2 {
+		switch attrs[i] {
+		case "service":
+			service = attrs[i+1]
+		case "account":
+			account = attrs[i+1]
+		}
+	}
+	secret, ok := f.items[service+"/"+account]
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+	return []byte(secret), nil
+}
+
+func TestSecretServiceProvider_CurrentEntry(t *testing.T) {
+	t.Setenv("USER", "alice")
+	fake := &fakeSecretService{items: map[string]string{
+		"Claude Code-credentials/alice": `{"claudeAiOauth":{"accessToken":"ss-tok","rateLimitTier":"default_claude_max_5x"}}`,
+	}}
+	p := &SecretServiceProvider{lookup: fake.lookup}
+
+	creds, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if creds.AccessToken != "ss-tok" || creds.RateLimitTier != "default_claude_max_5x" {
+		t.Errorf("creds = %+v", creds)
+	}
+	if len(fake.calls) != 1 {
+		t.Errorf("lookups = %v, want 1", fake.calls)
+	}
+}
+
+func TestSecretServiceProvider_LegacyEntry(t *testing.T) {
+	t.Setenv("USER", "alice")
+	fake := &fakeSecretService{items: map[string]string{
+		"claude.ai/oauth": `{"accessToken":"legacy-tok"}`,
+	}}
+	p := &SecretServiceProvider{lookup: fake.lookup}
+
+	creds, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if creds.AccessToken != "legacy-tok" {
+		t.Errorf("AccessToken = %q, want legacy-tok", creds.AccessToken)
+	}
+}
+
+func TestSecretServiceProvider_NotFound(t *testing.T) {
+	p := &SecretServiceProvider{lookup: (&fakeSecretService{}).lookup}
+	if _, err := p.Get(); err != ErrNoCredentials {
+		t.Errorf("Get() error = %v, want ErrNoCredentials", err)
+	}
+}
+
+func TestSecretServiceProvider_LockedKeyring(t *testing.T) {
+	p := &SecretServiceProvider{lookup: (&fakeSecretService{err: ErrKeychainAccess}).lookup}
+	if _, err := p.Get(); !errors.Is(err, ErrKeychainAccess) {
+		t.Errorf("Get() error = %v, want ErrKeychainAccess", err)
+	}
+}
+
+func TestProviderForSource(t *testing.T) {
+	for _, source := range []string{"", SourceAuto, SourceFile, SourceSecretService, SourceEncryptedFile} {
+		if p, err := ProviderForSource(source); err != nil || p == nil {
+			t.Errorf("ProviderForSource(%q) = %v, %v", source, p, err)
+		}
+	}
+	if _, err := ProviderForSource("bogus"); err == nil {
+		t.Error("ProviderForSource(bogus) should fail")
+	}
+}
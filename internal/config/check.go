@@ -0,0 +1,622 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/namyoungkim/visor/internal/render"
+)
+
+// Schema maps widget names to the extra options each accepts.
+// It is built by the caller (the widgets package depends on config).
+type Schema map[string]Options
+
+// Problem is a single finding from Check.
+type Problem struct {
+	Line    int    // 1-based line in the config file; 0 if unknown
+	Column  int    // 1-based column; 0 if unknown
+	Key     string // Key path, e.g. "line[0].widget[1].extra.show_lable"
+	Message string
+	Hint    string // Suggested fix, may be empty
+}
+
+func (p Problem) String() string {
+	var sb strings.Builder
+	if p.Line > 0 {
+		fmt.Fprintf(&sb, "%d:%d: ", p.Line, p.Column)
+	}
+	if p.Key != "" {
+		sb.WriteString(p.Key + ": ")
+	}
+	sb.WriteString(p.Message)
+	if p.Hint != "" {
+		fmt.Fprintf(&sb, " (%s)", p.Hint)
+	}
+	return sb.String()
+}
+
+// Check validates the config file at path and reports every problem found,
+// ordered by line. Widget names and extra options are checked against
+// schema when it is non-nil. A missing file has no problems (defaults are
+// used); err is only set when the file cannot be read.
+func Check(path string, schema Schema) ([]Problem, error) {
+	if path == "" {
+		path = DefaultConfigPath()
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	src := string(data)
+
+	// Older files are checked as rendering sees them: upgraded in memory,
+	// with lines mapped back to the file
+	var migrated *Document
+	if v, err := sourceVersion(src); err == nil && v < ConfigVersion {
+		migrated = migrateInMemory(src, v)
+		src = migrated.String()
+	}
+
+	// Syntax errors stop everything else
+	var raw map[string]any
+	if _, err := toml.Decode(src, &raw); err != nil {
+		return []Problem{decodeProblem(err, src)}, nil
+	}
+
+	c := &checker{loc: locateKeys(src), schema: schema}
+	c.checkLines(raw)
+
+	// Non-string extras were reported above; blank their lines so the
+	// typed decode can check the rest of the file
+	var cfg Config
+	md, err := toml.Decode(blankLines(src, c.skipLines), &cfg)
+	if err != nil {
+		c.problems = append(c.problems, decodeProblem(err, src))
+	} else {
+		c.checkUndecoded(md.Undecoded())
+		c.checkConfig(&cfg)
+	}
+
+	if migrated != nil {
+		for i := range c.problems {
+			if c.problems[i].Line = migrated.OriginalLine(c.problems[i].Line); c.problems[i].Line == 0 {
+				c.problems[i].Column = 0
+			}
+		}
+	}
+
+	sort.SliceStable(c.problems, func(i, j int) bool {
+		return c.problems[i].Line < c.problems[j].Line
+	})
+	return c.problems, nil
+}
+
+// checker collects problems while walking a config.
+type checker struct {
+	loc       *keyLocator
+	schema    Schema
+	problems  []Problem
+	skipLines map[int]bool // Lines hidden from the typed decode
+}
+
+func (c *checker) add(path, message, hint string) keyPos {
+	pos := c.loc.find(path)
+	c.problems = append(c.problems, Problem{Line: pos.Line, Column: pos.Column, Key: path, Message: message, Hint: hint})
+	return pos
+}
+
+// checkLines validates widget entries from the untyped document, so
+// problems in one widget don't hide problems in the next.
+func (c *checker) checkLines(raw map[string]any) {
+	for i, line := range tableList(raw["line"]) {
+		for _, section := range []string{"widget", "left", "right"} {
+			for j, w := range tableList(line[section]) {
+				c.checkWidget(fmt.Sprintf("line[%d].%s[%d]", i, section, j), w)
+			}
+		}
+	}
+}
+
+func (c *checker) checkWidget(path string, w map[string]any) {
+	name, _ := w["name"].(string)
+	var options Options
+	known := false
+	switch {
+	case name == "":
+		c.add(path, "widget has no name", `add name = "<widget>"`)
+	case c.schema != nil:
+		options, known = c.schema[name]
+		if !known {
+			c.add(path+".name", fmt.Sprintf("unknown widget %q", name), didYouMean(name, mapKeys(c.schema)))
+		}
+	}
+
+	if extra, ok := w["extra"].(map[string]any); ok {
+		for _, key := range c.loc.sortByPosition(path+".extra", mapKeys(extra)) {
+			keyPath := path + ".extra." + key
+			value, isString := extra[key].(string)
+			if !isString {
+				pos := c.add(keyPath, "option values must be quoted strings", fmt.Sprintf(`write %s = "%v"`, key, extra[key]))
+				if c.skipLines == nil {
+					c.skipLines = make(map[int]bool)
+				}
+				c.skipLines[pos.Line] = true
+				continue
+			}
+			if !known {
+				continue
+			}
+			opt, ok := options.Lookup(key)
+			if !ok {
+				c.add(keyPath, fmt.Sprintf("unknown option for widget %q", name), didYouMean(key, options.Keys()))
+				continue
+			}
+			if err := opt.Validate(value); err != nil {
+				c.add(keyPath, err.Error(), didYouMean(value, opt.Enum))
+			}
+		}
+	}
+
+	if style, ok := w["style"].(map[string]any); ok {
+		if fg, ok := style["fg"].(string); ok && fg != "" && !render.IsColor(fg) {
+			c.add(path+".style.fg", fmt.Sprintf("unknown color %q", fg), didYouMean(fg, mapKeys(render.ColorMap)))
+		}
+		if bg, ok := style["bg"].(string); ok && bg != "" && !render.IsBgColor(bg) {
+			c.add(path+".style.bg", fmt.Sprintf("unknown background color %q", bg), didYouMean(bg, mapKeys(render.BgColorMap)))
+		}
+	}
+}
+
+// checkUndecoded reports keys that don't map to any config field.
+// Only the outermost unknown key of a table is reported.
+func (c *checker) checkUndecoded(keys []toml.Key) {
+	unknown := make(map[string]bool)
+	seen := make(map[string]int)
+	for _, key := range keys {
+		unknown[key.String()] = true
+		if len(key) > 1 && unknown[key[:len(key)-1].String()] {
+			continue
+		}
+
+		pos := c.loc.findNth(key.String(), seen[key.String()])
+		seen[key.String()]++
+		c.problems = append(c.problems, Problem{
+			Line:    pos.Line,
+			Column:  pos.Column,
+			Key:     key.String(),
+			Message: "unknown key",
+			Hint:    didYouMean(key[len(key)-1], knownKeys(key[:len(key)-1])),
+		})
+	}
+}
+
+// checkConfig validates values of the typed config.
+func (c *checker) checkConfig(cfg *Config) {
+	if cfg.Version < 0 || cfg.Version > ConfigVersion {
+		c.add("version", fmt.Sprintf("unsupported config version %d (this visor supports up to %d)", cfg.Version, ConfigVersion),
+			"upgrade visor or remove the key")
+	}
+
+	if mode := cfg.General.Color; mode != "" && mode != render.ColorModeAuto {
+		if _, ok := render.ParseColorMode(mode); !ok {
+			c.add("general.color", fmt.Sprintf("invalid color mode %q (want %s)", mode, strings.Join(render.ColorModeNames, ", ")),
+				didYouMean(mode, render.ColorModeNames))
+		}
+	}
+
+	for _, f := range []struct {
+		key   string
+		value int
+	}{
+		{"general.width", cfg.General.Width},
+		{"general.width_margin", cfg.General.WidthMargin},
+	} {
+		if f.value < 0 {
+			c.add(f.key, fmt.Sprintf("invalid value %d (want 0 or more)", f.value), "")
+		}
+	}
+
+	for _, f := range []struct {
+		key   string
+		value int
+	}{
+		{"general.ambiguous_width", cfg.General.AmbiguousWidth},
+		{"general.pua_width", cfg.General.PUAWidth},
+	} {
+		if f.value < 0 || f.value > 2 {
+			c.add(f.key, fmt.Sprintf("invalid width %d (want 1 or 2)", f.value), "")
+		}
+	}
+
+	if colors := cfg.Theme.Colors; colors != nil {
+		for _, f := range []struct{ name, value string }{
+			{"normal", colors.Normal},
+			{"warning", colors.Warning},
+			{"critical", colors.Critical},
+			{"good", colors.Good},
+			{"primary", colors.Primary},
+			{"secondary", colors.Secondary},
+			{"muted", colors.Muted},
+		} {
+			if !validateColor(f.value) {
+				c.add("theme.colors."+f.name, fmt.Sprintf("invalid color %q", f.value), didYouMean(f.value, mapKeys(render.ColorMap)))
+			}
+		}
+		for i, bg := range colors.Backgrounds {
+			if !validateBgColor(bg) {
+				c.add("theme.colors.backgrounds", fmt.Sprintf("invalid background color at index %d: %q", i, bg),
+					didYouMean(bg, mapKeys(render.BgColorMap)))
+			}
+		}
+	}
+
+	if !validCredentialSources[cfg.Usage.CredentialSource] {
+		c.add("usage.credential_source", fmt.Sprintf("invalid value %q (want auto, file, keychain, secret_service, encrypted_file)",
+			cfg.Usage.CredentialSource), didYouMean(cfg.Usage.CredentialSource, mapKeys(validCredentialSources)))
+	}
+
+	for _, src := range cfg.Usage.LimitSources {
+		if !validLimitSources[src] {
+			c.add("usage.limit_sources", fmt.Sprintf("invalid entry %q (want oauth, headers, file, command, local)", src),
+				didYouMean(src, mapKeys(validLimitSources)))
+		}
+	}
+}
+
+// blankLines empties the given 1-based lines, keeping line numbers intact.
+func blankLines(src string, lines map[int]bool) string {
+	if len(lines) == 0 {
+		return src
+	}
+	parts := strings.Split(src, "\n")
+	for n := range lines {
+		if n > 0 && n <= len(parts) {
+			parts[n-1] = ""
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// tableList returns v as a list of tables, for both [[array]] tables and
+// inline arrays of tables.
+func tableList(v any) []map[string]any {
+	switch x := v.(type) {
+	case []map[string]any:
+		return x
+	case []any:
+		var tables []map[string]any
+		for _, item := range x {
+			if m, ok := item.(map[string]any); ok {
+				tables = append(tables, m)
+			}
+		}
+		return tables
+	}
+	return nil
+}
+
+// knownKeys returns the TOML keys of the Config table at parent.
+func knownKeys(parent toml.Key) []string {
+	t := reflect.TypeOf(Config{})
+	for _, part := range parent {
+		t = tableType(t)
+		if t.Kind() != reflect.Struct {
+			return nil
+		}
+		field, ok := fieldByTag(t, part)
+		if !ok {
+			return nil
+		}
+		t = field.Type
+	}
+	t = tableType(t)
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		if name := tomlName(t.Field(i)); name != "" {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// tableType unwraps pointers and slices to the table element type.
+func tableType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t
+}
+
+func fieldByTag(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if tomlName(t.Field(i)) == name {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func tomlName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("toml"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// decodeLineRegex extracts position and key from toml decode errors that
+// are not ParseErrors, e.g. `toml: line 5 (last key "x"): incompatible types`.
+var decodeLineRegex = regexp.MustCompile(`^toml: (?:line (\d+) )?(?:\(last key "([^"]*)"\): )?(.*)$`)
+
+// decodeProblem converts a toml decode error to a Problem.
+func decodeProblem(err error, src string) Problem {
+	var pe toml.ParseError
+	if errors.As(err, &pe) {
+		p := Problem{Line: pe.Position.Line, Key: pe.LastKey, Message: pe.Message}
+		if p.Message == "" {
+			p.Message = strings.TrimPrefix(err.Error(), "toml: ")
+			if m := decodeLineRegex.FindStringSubmatch(err.Error()); m != nil {
+				p.Message = m[3]
+			}
+		}
+		if start := pe.Position.Start; start > 0 && start <= len(src) {
+			p.Column = start - strings.LastIndex(src[:start], "\n")
+		}
+		return p
+	}
+
+	m := decodeLineRegex.FindStringSubmatch(err.Error())
+	if m == nil {
+		return Problem{Message: err.Error()}
+	}
+	line, _ := strconv.Atoi(m[1])
+	p := Problem{Line: line, Key: m[2], Message: m[3]}
+	if line > 0 {
+		p.Column = 1
+	}
+	return p
+}
+
+// didYouMean suggests the closest candidate to s, or returns "".
+// Case, '_' and '-' differences count as exact matches (brightred → bright_red).
+func didYouMean(s string, candidates []string) string {
+	if s == "" {
+		return ""
+	}
+	norm := func(x string) string {
+		return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(x))
+	}
+
+	best, bestDist := "", -1
+	for _, cand := range candidates {
+		if cand == s {
+			continue
+		}
+		d := levenshtein(norm(s), norm(cand))
+		if bestDist < 0 || d < bestDist || d == bestDist && cand < best {
+			best, bestDist = cand, d
+		}
+	}
+	if bestDist < 0 || bestDist > max(1, len(s)/3) {
+		return ""
+	}
+	return fmt.Sprintf("did you mean %q?", best)
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// keyPos is where a key appears in the TOML source.
+type keyPos struct {
+	Line, Column int
+}
+
+// keyLocator maps key paths to source positions. BurntSushi/toml doesn't
+// expose key positions, so the source is scanned line by line. Paths of
+// array table elements are indexed: "line[0].widget[1].name".
+type keyLocator struct {
+	byPath map[string]keyPos
+	byKey  map[string][]keyPos // Unindexed path → positions in source order
+}
+
+var (
+	indexRegex     = regexp.MustCompile(`\[\d+\]`)
+	inlineKeyRegex = regexp.MustCompile(`([A-Za-z0-9_-]+|"[^"]*")\s*=`)
+)
+
+func unindexed(path string) string {
+	return indexRegex.ReplaceAllString(path, "")
+}
+
+// locateKeys scans TOML source for table headers and keys.
+func locateKeys(src string) *keyLocator {
+	l := &keyLocator{byPath: make(map[string]keyPos), byKey: make(map[string][]keyPos)}
+	counts := make(map[string]int) // Array table path → elements seen
+	table := ""
+	closing := "" // Delimiter ending the current multi-line string
+
+	for n, text := range strings.Split(src, "\n") {
+		if closing != "" {
+			if strings.Contains(text, closing) {
+				closing = ""
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+		pos := keyPos{Line: n + 1, Column: len(text) - len(strings.TrimLeft(text, " \t")) + 1}
+
+		switch {
+		case strings.HasPrefix(trimmed, "[["):
+			parts := splitKey(headerName(trimmed, "[[", "]]"))
+			if len(parts) == 0 {
+				continue
+			}
+			array := joinKey(resolveKey(parts[:len(parts)-1], counts), parts[len(parts)-1])
+			table = fmt.Sprintf("%s[%d]", array, counts[array])
+			counts[array]++
+			l.add(table, pos)
+
+		case trimmed[0] == '[':
+			table = resolveKey(splitKey(headerName(trimmed, "[", "]")), counts)
+			l.add(table, pos)
+
+		default:
+			eq := strings.Index(trimmed, "=")
+			if eq < 0 {
+				continue
+			}
+			path := joinKey(table, splitKey(trimmed[:eq])...)
+			l.add(path, pos)
+
+			value := strings.TrimSpace(trimmed[eq+1:])
+			if strings.HasPrefix(value, "{") {
+				offset := strings.Index(text, "{")
+				for _, m := range inlineKeyRegex.FindAllStringSubmatchIndex(text[offset:], -1) {
+					key := strings.Trim(text[offset+m[2]:offset+m[3]], `"`)
+					l.add(joinKey(path, key), keyPos{Line: n + 1, Column: offset + m[2] + 1})
+				}
+			}
+			for _, delim := range []string{`"""`, `'''`} {
+				if strings.HasPrefix(value, delim) && strings.Count(value, delim) == 1 {
+					closing = delim
+				}
+			}
+		}
+	}
+	return l
+}
+
+func (l *keyLocator) add(path string, pos keyPos) {
+	if _, ok := l.byPath[path]; !ok {
+		l.byPath[path] = pos
+	}
+	key := unindexed(path)
+	l.byKey[key] = append(l.byKey[key], pos)
+}
+
+// find returns the position of an indexed path, falling back to its
+// closest located parent.
+func (l *keyLocator) find(path string) keyPos {
+	for path != "" {
+		if pos, ok := l.byPath[path]; ok {
+			return pos
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return keyPos{}
+}
+
+// findNth returns the position of the nth occurrence of an unindexed key.
+func (l *keyLocator) findNth(key string, n int) keyPos {
+	if positions := l.byKey[key]; n < len(positions) {
+		return positions[n]
+	}
+	return keyPos{}
+}
+
+// sortByPosition orders keys of the table at path by source position.
+func (l *keyLocator) sortByPosition(path string, keys []string) []string {
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := l.byPath[joinKey(path, keys[i])], l.byPath[joinKey(path, keys[j])]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return keys
+}
+
+// resolveKey indexes array table components with their current element.
+func resolveKey(parts []string, counts map[string]int) string {
+	path := ""
+	for _, part := range parts {
+		path = joinKey(path, part)
+		if n, ok := counts[path]; ok {
+			path = fmt.Sprintf("%s[%d]", path, n-1)
+		}
+	}
+	return path
+}
+
+func headerName(s, open, close string) string {
+	s = strings.TrimPrefix(s, open)
+	if i := strings.Index(s, close); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// splitKey splits a dotted TOML key, removing quotes and spaces.
+func splitKey(s string) []string {
+	var parts []string
+	for _, part := range strings.Split(s, ".") {
+		part = strings.Trim(strings.TrimSpace(part), `"'`)
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+func joinKey(base string, parts ...string) string {
+	for _, p := range parts {
+		if base == "" {
+			base = p
+		} else {
+			base += "." + p
+		}
+	}
+	return base
+}
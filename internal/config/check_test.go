@@ -0,0 +1,276 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+	return path
+}
+
+var testSchema = Schema{
+	"model": nil,
+	"context": {
+		{Key: "show_bar", Type: OptionBool, Default: "true"},
+		{Key: "bar_width", Type: OptionInt, Default: "10", Min: "1", Max: "50"},
+	},
+	"cost": {{Key: "show_label", Type: OptionBool, Default: "false"}},
+	"tools": {
+		{Key: "sort", Type: OptionString, Default: "recent", Enum: []string{"recent", "count", "slowest", "errors"}},
+	},
+}
+
+func TestCheck_ReportsAllProblems(t *testing.T) {
+	path := writeConfig(t, `version = 2
+
+[general]
+separator = " | "
+debgu = true
+ambiguous_width = 3
+
+[theme.colors]
+warning = "brightred"
+
+[[line]]
+  [[line.widget]]
+  name = "contxt"
+
+  [[line.widget]]
+  name = "context"
+  style = { fg = "Red", bg = "#12345" }
+  [line.widget.extra]
+  show_bar = true
+  bar_widht = "10"
+
+[[line]]
+  [[line.widget]]
+  name = "cost"
+  [line.widget.extra]
+  show_label = "yes"
+
+  [[line.widget]]
+  name = "tools"
+  [line.widget.extra]
+  sort = "slowst"
+
+  [[line.widget]]
+  name = "context"
+  [line.widget.extra]
+  bar_width = "80"
+
+  [[line.widget]]
+  name = "model"
+  [line.widget.extra]
+  show_label = "true"
+`)
+
+	problems, err := Check(path, testSchema)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	want := []string{
+		`5:1: general.debgu: unknown key`,
+		`6:1: general.ambiguous_width: invalid width 3 (want 1 or 2)`,
+		`9:1: theme.colors.warning: invalid color "brightred" (did you mean "bright_red"?)`,
+		`13:3: line[0].widget[0].name: unknown widget "contxt" (did you mean "context"?)`,
+		`17:13: line[0].widget[1].style.fg: unknown color "Red" (did you mean "red"?)`,
+		`17:25: line[0].widget[1].style.bg: unknown background color "#12345"`,
+		`19:3: line[0].widget[1].extra.show_bar: option values must be quoted strings (write show_bar = "true")`,
+		`20:3: line[0].widget[1].extra.bar_widht: unknown option for widget "context" (did you mean "bar_width"?)`,
+		`26:3: line[1].widget[0].extra.show_label: must be a boolean, got "yes"`,
+		`31:3: line[1].widget[1].extra.sort: must be one of recent, count, slowest, errors, got "slowst" (did you mean "slowest"?)`,
+		`36:3: line[1].widget[2].extra.bar_width: must be between 1 and 50, got 80`,
+		`41:3: line[1].widget[3].extra.show_label: unknown option for widget "model"`,
+	}
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Check() problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCheck_MigratesOlderVersions(t *testing.T) {
+	// Version 1 color names are upgraded in memory when rendering, so
+	// --check accepts them too; problems left after migration still count
+	path := writeConfig(t, `[theme.colors]
+warning = "brightred"
+
+[[line]]
+  [[line.widget]]
+  name = "api_latency"
+  style = { bg = "Redd", fg = "BrightCyan" }
+  [line.widget.extra]
+  show_label = "true"
+`)
+
+	problems, err := Check(path, Schema{"api_latency": nil})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(problems) != 1 || problems[0].String() != `7:13: line[0].widget[0].style.bg: unknown background color "Redd" (did you mean "red"?)` {
+		t.Errorf("Check() problems = %v, want only the bg color", problems)
+	}
+}
+
+func TestCheck_NilSchemaSkipsWidgetChecks(t *testing.T) {
+	path := writeConfig(t, `[[line]]
+  [[line.widget]]
+  name = "anything"
+  [line.widget.extra]
+  whatever = "1"
+`)
+
+	problems, err := Check(path, nil)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("Check() = %v, want no problems", problems)
+	}
+}
+
+func TestCheck_SyntaxError(t *testing.T) {
+	path := writeConfig(t, `[general]
+separator = " | "
+debug = tru
+`)
+
+	problems, err := Check(path, nil)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(problems) != 1 || problems[0].Line != 3 {
+		t.Fatalf("Check() = %v, want one problem on line 3", problems)
+	}
+}
+
+func TestCheck_TypeError(t *testing.T) {
+	path := writeConfig(t, `[usage]
+enabled = "yes"
+`)
+
+	problems, err := Check(path, nil)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(problems) != 1 || problems[0].Line != 2 || problems[0].Key != "usage.enabled" {
+		t.Fatalf("Check() = %v, want type error for usage.enabled on line 2", problems)
+	}
+}
+
+func TestCheck_UnknownTable(t *testing.T) {
+	path := writeConfig(t, `[genral]
+separator = " | "
+debug = true
+`)
+
+	problems, err := Check(path, nil)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("Check() = %v, want only the table reported", problems)
+	}
+	if got := problems[0].String(); got != `1:1: genral: unknown key (did you mean "general"?)` {
+		t.Errorf("problem = %q", got)
+	}
+}
+
+func TestCheck_MissingFile(t *testing.T) {
+	problems, err := Check("/nonexistent/path/config.toml", nil)
+	if err != nil || len(problems) != 0 {
+		t.Errorf("Check() = %v, %v; want no problems", problems, err)
+	}
+}
+
+func TestLocateKeys(t *testing.T) {
+	loc := locateKeys(`[[line]]
+  [[line.widget]]
+  name = "a"
+  [[line.widget]]
+  name = "b"
+  extra = { x = "1", "y" = "2" }
+  format = """
+not = "a key"
+"""
+[[line]]
+  [[line.widget]]
+  name = "c"
+`)
+
+	tests := []struct {
+		path string
+		line int
+		col  int
+	}{
+		{"line[0].widget[0].name", 3, 3},
+		{"line[0].widget[1].name", 5, 3},
+		{"line[0].widget[1].extra.x", 6, 13},
+		{"line[0].widget[1].extra.y", 6, 22},
+		{"line[1].widget[0]", 11, 3},
+		{"line[1].widget[0].name", 12, 3},
+	}
+	for _, tt := range tests {
+		pos := loc.find(tt.path)
+		if pos.Line != tt.line || pos.Column != tt.col {
+			t.Errorf("find(%q) = %d:%d, want %d:%d", tt.path, pos.Line, pos.Column, tt.line, tt.col)
+		}
+	}
+	if pos := loc.findNth("line.widget.name", 2); pos.Line != 12 {
+		t.Errorf("findNth(line.widget.name, 2) = line %d, want 12", pos.Line)
+	}
+	if _, ok := loc.byPath["line[0].widget[1].not"]; ok {
+		t.Error("keys inside multi-line strings should be ignored")
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	candidates := []string{"show_label", "show_bar", "bar_width"}
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"show_lable", `did you mean "show_label"?`},
+		{"ShowBar", `did you mean "show_bar"?`},
+		{"completely_different", ""},
+	}
+	for _, tt := range tests {
+		if got := didYouMean(tt.input, candidates); got != tt.expected {
+			t.Errorf("didYouMean(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestCheck_GeneralValues(t *testing.T) {
+	path := writeConfig(t, `[general]
+color = "256colors"
+width = -1
+`)
+
+	problems, err := Check(path, nil)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	want := []string{
+		`2:1: general.color: invalid color mode "256colors" (want auto, truecolor, 256, 16, none)`,
+		`3:1: general.width: invalid value -1 (want 0 or more)`,
+	}
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Check() problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -1,5 +1,7 @@
 package config
 
+import "strconv"
+
 // DefaultSeparator is the default separator between widgets.
 const DefaultSeparator = " | "
 
@@ -9,6 +11,7 @@ const DefaultTheme = "default"
 // DefaultConfig returns the default configuration.
 func DefaultConfig() *Config {
 	return &Config{
+		Version: ConfigVersion,
 		General: GeneralConfig{
 			Separator: DefaultSeparator,
 		},
@@ -44,6 +47,8 @@ func DefaultConfigTOML() string {
 	return `# visor configuration
 # Place at ~/.config/visor/config.toml
 
+version = ` + strconv.Itoa(ConfigVersion) + `  # Config schema version (upgrade with: visor config migrate)
+
 [general]
 separator = " | "  # Widget separator (default: " | ")
 
@@ -74,6 +79,10 @@ enabled = true     # Enable usage tracking (daily/weekly cost, rate limits)
 # provider = ""    # Auto-detect: anthropic, claude_pro, aws, gcp
 # projects_dir = "" # Default: ~/.claude/projects
 
+# [auth]
+# disable_refresh = false  # Don't refresh expired OAuth tokens
+# write_back = false       # Write refreshed tokens to Claude Code's credential file; required for refresh
+
 # === Single-line layout (default) ===
 [[line]]
   [[line.widget]]
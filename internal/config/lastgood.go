@@ -0,0 +1,126 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LastGoodFile holds the last config that loaded successfully, per set of
+// config files, in the cache directory.
+const LastGoodFile = "config_last_good.json"
+
+// CacheDirFunc returns the directory for LastGoodFile.
+// Can be overridden in tests.
+var CacheDirFunc = defaultCacheDir
+
+// defaultCacheDir returns ~/.cache/visor, shared with session history.
+func defaultCacheDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "/tmp"
+	}
+	return filepath.Join(home, ".cache", "visor")
+}
+
+// LoadWithFallback loads the layered config like LoadLayered, but never
+// fails: when a config file is broken it returns the last config that
+// loaded from the same files (or the defaults) with LoadErr set, so the
+// statusline keeps rendering. A successful load is saved as the new
+// last-known-good config.
+func LoadWithFallback(opts LoadOptions) *Layered {
+	key := strings.Join(LayerFiles(opts), "\n")
+
+	l, err := LoadLayered(opts)
+	if err == nil {
+		// Best effort: a read-only cache only loses the fallback
+		_ = saveLastGood(key, l.Config)
+		return l
+	}
+
+	cfg := loadLastGood(key)
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	return &Layered{
+		Config:  cfg,
+		Origins: make(map[string]string),
+		LoadErr: err,
+	}
+}
+
+// ErrorSummary returns a short description of a config load error for the
+// statusline.
+func ErrorSummary(err error) string {
+	var fe *FileError
+	if errors.As(err, &fe) {
+		return fe.Summary()
+	}
+	return truncate(err.Error(), summaryMessageLen)
+}
+
+func lastGoodPath() string {
+	return filepath.Join(CacheDirFunc(), LastGoodFile)
+}
+
+// readLastGood reads all snapshots, keyed by config file list.
+func readLastGood() map[string]json.RawMessage {
+	snapshots := make(map[string]json.RawMessage)
+	if data, err := os.ReadFile(lastGoodPath()); err == nil {
+		_ = json.Unmarshal(data, &snapshots)
+	}
+	return snapshots
+}
+
+func loadLastGood(key string) *Config {
+	raw, ok := readLastGood()[key]
+	if !ok {
+		return nil
+	}
+	var cfg Config
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil
+	}
+	return &cfg
+}
+
+// saveLastGood stores cfg under key. The file is only rewritten when the
+// config changed, since this runs on every render.
+func saveLastGood(key string, cfg *Config) error {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	snapshots := readLastGood()
+	if bytes.Equal(snapshots[key], data) {
+		return nil
+	}
+	snapshots[key] = data
+
+	out, err := json.Marshal(snapshots)
+	if err != nil {
+		return err
+	}
+	path := lastGoodPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), LastGoodFile+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(out); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useTempCache points the last-known-good snapshot at a temp directory.
+func useTempCache(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	orig := CacheDirFunc
+	CacheDirFunc = func() string { return dir }
+	t.Cleanup(func() { CacheDirFunc = orig })
+	return dir
+}
+
+func TestLoadWithFallback_UsesLastGood(t *testing.T) {
+	useTempCache(t)
+	path := writeConfig(t, "[general]\nseparator = \" :: \"\n")
+
+	l := LoadWithFallback(LoadOptions{GlobalPath: path})
+	if l.LoadErr != nil {
+		t.Fatalf("LoadErr = %v, want nil", l.LoadErr)
+	}
+
+	// Break the file: the last good config is used instead
+	if err := os.WriteFile(path, []byte("[general]\nseparator = @\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	l = LoadWithFallback(LoadOptions{GlobalPath: path})
+	if l.LoadErr == nil {
+		t.Fatal("LoadErr = nil, want the parse error")
+	}
+	if l.Config.General.Separator != " :: " {
+		t.Errorf("Separator = %q, want last good value", l.Config.General.Separator)
+	}
+
+	summary := ErrorSummary(l.LoadErr)
+	if !strings.HasPrefix(summary, "config.toml:2: ") {
+		t.Errorf("ErrorSummary() = %q, want config.toml:2 prefix", summary)
+	}
+}
+
+func TestLoadWithFallback_NoSnapshot(t *testing.T) {
+	useTempCache(t)
+	path := writeConfig(t, "[general\n")
+
+	l := LoadWithFallback(LoadOptions{GlobalPath: path})
+	if l.LoadErr == nil {
+		t.Fatal("LoadErr = nil, want the parse error")
+	}
+	if l.Config.General.Separator != DefaultConfig().General.Separator {
+		t.Errorf("Separator = %q, want default", l.Config.General.Separator)
+	}
+}
+
+func TestLoadWithFallback_UnchangedNotRewritten(t *testing.T) {
+	dir := useTempCache(t)
+	path := writeConfig(t, "[general]\nseparator = \" :: \"\n")
+
+	LoadWithFallback(LoadOptions{GlobalPath: path})
+	snapshot := filepath.Join(dir, LastGoodFile)
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(snapshot, old, old); err != nil {
+		t.Fatalf("snapshot not written: %v", err)
+	}
+
+	LoadWithFallback(LoadOptions{GlobalPath: path})
+	info, err := os.Stat(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(old) {
+		t.Error("snapshot rewritten although the config did not change")
+	}
+}
+
+func TestErrorSummary_Truncates(t *testing.T) {
+	err := &FileError{
+		Path:    "/home/u/.config/visor/config.toml",
+		Problem: Problem{Line: 3, Message: strings.Repeat("x", 50)},
+	}
+	want := "config.toml:3: " + strings.Repeat("x", summaryMessageLen-1) + "…"
+	if got := ErrorSummary(err); got != want {
+		t.Errorf("ErrorSummary() = %q, want %q", got, want)
+	}
+
+	if got := ErrorSummary(errors.New("boom")); got != "boom" {
+		t.Errorf("ErrorSummary() = %q, want boom", got)
+	}
+}
@@ -0,0 +1,592 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ProjectConfigName is the per-project config file, discovered from the
+// session's working directory upward.
+const ProjectConfigName = ".visor.toml"
+
+// EnvPrefix prefixes environment overrides: general.separator is
+// VISOR_GENERAL_SEPARATOR, theme.colors.warning is VISOR_THEME_COLORS_WARNING.
+const EnvPrefix = "VISOR_"
+
+// EnvConfigPath overrides the global config file path.
+const EnvConfigPath = "VISOR_CONFIG"
+
+// Line merge modes ([general] line_merge) for a layer's [[line]] tables.
+const (
+	LineMergeReplace = "replace" // Replace lower layers' lines (default)
+	LineMergeAppend  = "append"  // Add after lower layers' lines
+	LineMergePrepend = "prepend" // Add before lower layers' lines
+)
+
+// OriginDefault marks values that no layer set.
+const OriginDefault = "default"
+
+// projectDeniedKeys can't be set from a project file: a cloned repository
+// must not be able to run commands or redirect credentials.
+var projectDeniedKeys = []string{
+	"auth.",
+	"usage.credential_source",
+	"usage.limits_command",
+	"usage.limits_file",
+	"usage.projects_dir",
+}
+
+// LoadOptions controls LoadLayered.
+type LoadOptions struct {
+	// GlobalPath is the global config file.
+	// Empty means $VISOR_CONFIG, then DefaultConfigPath().
+	GlobalPath string
+
+	// Dir is searched upward for ProjectConfigName. Empty disables the project layer.
+	Dir string
+
+	// Environ holds VISOR_* overrides in os.Environ() form. Nil disables the env layer.
+	Environ []string
+}
+
+// Layered is a configuration merged from defaults, the global file, the
+// project file and the environment, in increasing priority.
+type Layered struct {
+	Config *Config
+
+	// Files are the config files applied, lowest priority first.
+	Files []string
+
+	// Origins maps keys ("usage.enabled") to where their value came from:
+	// "path:line", "env VISOR_USAGE_ENABLED" or OriginDefault.
+	Origins map[string]string
+
+	// LineOrigins holds the origin of each entry in Config.Lines.
+	LineOrigins []string
+
+	// Warnings are ignored keys and unusable environment values.
+	Warnings []string
+
+	// LoadErr is set by LoadWithFallback when a config file failed to load
+	// and Config is the last-known-good config or the defaults.
+	LoadErr error
+}
+
+// Origin returns where the value at key came from.
+func (l *Layered) Origin(key string) string {
+	if origin, ok := l.Origins[key]; ok {
+		return origin
+	}
+	return OriginDefault
+}
+
+// LoadLayered loads the layered configuration. Missing files are skipped;
+// a file that fails to parse is an error.
+func LoadLayered(opts LoadOptions) (*Layered, error) {
+	env := envMap(opts.Environ)
+
+	l := &Layered{
+		Config:  DefaultConfig(),
+		Origins: make(map[string]string),
+	}
+	l.LineOrigins = make([]string, len(l.Config.Lines))
+	for i := range l.LineOrigins {
+		l.LineOrigins[i] = OriginDefault
+	}
+
+	global, project := opts.paths(env)
+	if err := l.applyFile(global, false); err != nil {
+		return nil, err
+	}
+	if project != "" {
+		if err := l.applyFile(project, true); err != nil {
+			return nil, err
+		}
+	}
+
+	l.applyEnv(env)
+
+	if l.Config.General.Separator == "" {
+		l.Config.General.Separator = DefaultSeparator
+	}
+	return l, nil
+}
+
+// LayerFiles returns the existing config files LoadLayered would apply,
+// lowest priority first, without parsing them.
+func LayerFiles(opts LoadOptions) []string {
+	global, project := opts.paths(envMap(opts.Environ))
+	var files []string
+	for _, path := range []string{global, project} {
+		if info, err := os.Stat(path); path != "" && err == nil && !info.IsDir() {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// GlobalConfigPath returns the global config file LoadLayered would use,
+// whether or not it exists.
+func GlobalConfigPath(opts LoadOptions) string {
+	global, _ := opts.paths(envMap(opts.Environ))
+	return global
+}
+
+// paths resolves the global and project config paths.
+// project is "" when there is none or it is the global file.
+func (opts LoadOptions) paths(env map[string]string) (global, project string) {
+	global = opts.GlobalPath
+	if global == "" {
+		global = env[EnvConfigPath]
+	}
+	if global == "" {
+		global = DefaultConfigPath()
+	}
+	project = FindProjectConfig(opts.Dir)
+	if project != "" && sameFile(project, global) {
+		project = ""
+	}
+	return global, project
+}
+
+// FileError is a config file that could not be parsed.
+type FileError struct {
+	Path    string
+	Problem Problem
+}
+
+func (e *FileError) Error() string {
+	return e.Path + ":" + e.Problem.String()
+}
+
+// Summary returns a short form for the statusline, e.g.
+// "config.toml:3: expected value but found…".
+func (e *FileError) Summary() string {
+	s := filepath.Base(e.Path)
+	if e.Problem.Line > 0 {
+		s += ":" + strconv.Itoa(e.Problem.Line)
+	}
+	return s + ": " + truncate(e.Problem.Message, summaryMessageLen)
+}
+
+// summaryMessageLen caps the message in FileError.Summary.
+const summaryMessageLen = 32
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// FindProjectConfig returns the nearest ProjectConfigName in dir or its
+// parents, or "" if there is none.
+func FindProjectConfig(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ProjectConfigName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// applyFile merges the keys defined in a config file.
+func (l *Layered) applyFile(path string, project bool) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	text := string(data)
+	var layer Config
+	md, err := toml.Decode(text, &layer)
+	if err != nil {
+		return &FileError{Path: path, Problem: decodeProblem(err, text)}
+	}
+
+	// Older files are upgraded in memory; `visor config migrate` rewrites them
+	fileLine := func(line int) int { return line }
+	switch v := fileVersion(layer.Version); {
+	case v > ConfigVersion:
+		l.Warnings = append(l.Warnings, fmt.Sprintf("%s: config version %d is newer than this visor supports (%d)",
+			path, v, ConfigVersion))
+	case v < ConfigVersion:
+		if doc := migrateInMemory(text, v); len(doc.changes) > 0 {
+			migrated := doc.String()
+			layer = Config{}
+			if md, err = toml.Decode(migrated, &layer); err != nil {
+				problem := decodeProblem(err, migrated)
+				problem.Line = doc.OriginalLine(problem.Line)
+				return &FileError{Path: path, Problem: problem}
+			}
+			text = migrated
+			fileLine = doc.OriginalLine
+			l.Warnings = append(l.Warnings, fmt.Sprintf("%s: upgraded from config version %d in memory (%d changes); run `visor config migrate` to update the file",
+				path, v, len(doc.changes)))
+		}
+	}
+
+	l.Files = append(l.Files, path)
+	loc := locateKeys(text)
+	origin := func(key string) string {
+		if line := fileLine(loc.find(key).Line); line > 0 {
+			return fmt.Sprintf("%s:%d", path, line)
+		}
+		return path
+	}
+
+	// A global file with [[line]] used to replace the whole default config,
+	// so the keys it leaves out stay zero (e.g. usage.enabled = false)
+	if !project && md.IsDefined("line") {
+		l.zeroUndefined(md, path)
+	}
+
+	src := reflect.ValueOf(&layer).Elem()
+	dst := reflect.ValueOf(l.Config).Elem()
+	for _, key := range leafKeys() {
+		parts := strings.Split(key, ".")
+		if !md.IsDefined(parts...) {
+			continue
+		}
+		if project && projectDenied(key) {
+			l.Warnings = append(l.Warnings, fmt.Sprintf("%s: %s is ignored in project config", origin(key), key))
+			continue
+		}
+		fieldByPath(dst, parts, true).Set(fieldByPath(src, parts, false))
+		l.Origins[key] = origin(key)
+	}
+
+	if !md.IsDefined("line") {
+		return nil
+	}
+	lineOrigins := make([]string, len(layer.Lines))
+	for i := range layer.Lines {
+		lineOrigins[i] = origin(fmt.Sprintf("line[%d]", i))
+	}
+	switch layer.General.LineMerge {
+	case LineMergeAppend:
+		l.Config.Lines = append(l.Config.Lines, layer.Lines...)
+		l.LineOrigins = append(l.LineOrigins, lineOrigins...)
+	case LineMergePrepend:
+		l.Config.Lines = append(layer.Lines, l.Config.Lines...)
+		l.LineOrigins = append(lineOrigins, l.LineOrigins...)
+	default:
+		if mode := layer.General.LineMerge; mode != "" && mode != LineMergeReplace {
+			l.Warnings = append(l.Warnings, fmt.Sprintf("%s: unknown line_merge %q, using %q",
+				origin("general.line_merge"), mode, LineMergeReplace))
+		}
+		l.Config.Lines = layer.Lines
+		l.LineOrigins = lineOrigins
+	}
+	return nil
+}
+
+// zeroUndefined resets the keys md does not define to their zero value,
+// recording path as their origin.
+func (l *Layered) zeroUndefined(md toml.MetaData, path string) {
+	dst := reflect.ValueOf(l.Config).Elem()
+	for _, key := range leafKeys() {
+		parts := strings.Split(key, ".")
+		if md.IsDefined(parts...) {
+			continue
+		}
+		field := fieldByPath(dst, parts, false)
+		if !field.IsValid() || field.IsZero() {
+			continue
+		}
+		field.Set(reflect.Zero(field.Type()))
+		l.Origins[key] = path
+	}
+}
+
+// applyEnv merges VISOR_* overrides.
+func (l *Layered) applyEnv(env map[string]string) {
+	dst := reflect.ValueOf(l.Config).Elem()
+	for _, key := range leafKeys() {
+		name := EnvName(key)
+		value, ok := env[name]
+		if !ok {
+			continue
+		}
+
+		field := fieldByPath(dst, strings.Split(key, "."), true)
+		if err := setFromString(field, value); err != nil {
+			l.Warnings = append(l.Warnings, fmt.Sprintf("%s=%q ignored: %v", name, value, err))
+			continue
+		}
+		l.Origins[key] = "env " + name
+	}
+}
+
+// EnvName returns the environment variable overriding key.
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// setFromString parses an environment value into field.
+// Lists are comma-separated.
+func setFromString(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("want true or false")
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("want an integer")
+		}
+		field.SetInt(int64(n))
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// leafKeys lists the dotted keys of every non-table Config value except
+// version and [[line]], in declaration order.
+func leafKeys() []string {
+	var keys []string
+	var walk func(t reflect.Type, prefix string)
+	walk = func(t reflect.Type, prefix string) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := tomlName(f)
+			if name == "" || prefix == "" && (name == "line" || name == "version") {
+				continue
+			}
+			key := joinKey(prefix, name)
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				walk(ft, key)
+			} else {
+				keys = append(keys, key)
+			}
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "")
+	return keys
+}
+
+// fieldByPath returns the field at a key path, allocating nil table
+// pointers along the way when alloc is set.
+func fieldByPath(v reflect.Value, parts []string, alloc bool) reflect.Value {
+	for _, part := range parts {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		f, ok := fieldByTag(v.Type(), part)
+		if !ok {
+			return reflect.Value{}
+		}
+		v = v.FieldByIndex(f.Index)
+	}
+	return v
+}
+
+func projectDenied(key string) bool {
+	for _, denied := range projectDeniedKeys {
+		if key == denied || strings.HasSuffix(denied, ".") && strings.HasPrefix(key, denied) {
+			return true
+		}
+	}
+	return false
+}
+
+func envMap(environ []string) map[string]string {
+	env := make(map[string]string)
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(k, EnvPrefix) {
+			env[k] = v
+		}
+	}
+	return env
+}
+
+func sameFile(a, b string) bool {
+	ia, errA := os.Stat(a)
+	ib, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(ia, ib)
+}
+
+// WriteEffective writes the merged configuration as TOML, annotating each
+// value with its origin.
+func (l *Layered) WriteEffective(w io.Writer) error {
+	var sb strings.Builder
+	cfg := reflect.ValueOf(l.Config).Elem()
+
+	table := ""
+	for _, key := range leafKeys() {
+		field := fieldByPath(cfg, strings.Split(key, "."), false)
+		if !field.IsValid() {
+			continue // Unset optional table, e.g. [theme.colors]
+		}
+
+		parent, name := "", key
+		if i := strings.LastIndex(key, "."); i >= 0 {
+			parent, name = key[:i], key[i+1:]
+		}
+		if parent != table {
+			if sb.Len() > 0 {
+				sb.WriteString("\n")
+			}
+			fmt.Fprintf(&sb, "[%s]\n", parent)
+			table = parent
+		}
+
+		fmt.Fprintf(&sb, "%s = %s  # %s\n", name, tomlValue(field), l.Origin(key))
+	}
+
+	for i, line := range l.Config.Lines {
+		fmt.Fprintf(&sb, "\n[[line]]  # %s\n", l.LineOrigins[i])
+		for _, section := range []struct {
+			name    string
+			widgets []WidgetConfig
+		}{{"widget", line.Widgets}, {"left", line.Left}, {"right", line.Right}} {
+			for _, wc := range section.widgets {
+				writeWidget(&sb, section.name, wc)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeWidget(sb *strings.Builder, section string, wc WidgetConfig) {
+	fmt.Fprintf(sb, "  [[line.%s]]\n  name = %s\n", section, tomlString(wc.Name))
+	if wc.Format != "" {
+		fmt.Fprintf(sb, "  format = %s\n", tomlString(wc.Format))
+	}
+	if wc.Style != (StyleConfig{}) {
+		fmt.Fprintf(sb, "  style = { fg = %s, bg = %s, bold = %t", tomlString(wc.Style.Fg), tomlString(wc.Style.Bg), wc.Style.Bold)
+		for _, attr := range []struct {
+			name string
+			on   bool
+		}{{"dim", wc.Style.Dim}, {"italic", wc.Style.Italic}, {"underline", wc.Style.Underline}} {
+			if attr.on {
+				fmt.Fprintf(sb, ", %s = true", attr.name)
+			}
+		}
+		sb.WriteString(" }\n")
+	}
+	if len(wc.Extra) > 0 {
+		fmt.Fprintf(sb, "  [line.%s.extra]\n", section)
+		keys := mapKeys(wc.Extra)
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(sb, "  %s = %s\n", tomlKey(k), tomlString(wc.Extra[k]))
+		}
+	}
+}
+
+// tomlValue formats a leaf value as TOML.
+func tomlValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return tomlString(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = tomlValue(v.Index(i))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+// tomlString quotes s as a TOML basic string. Unlike strconv.Quote it only
+// uses escapes TOML accepts; invalid UTF-8 becomes U+FFFD.
+func tomlString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range strings.ToValidUTF8(s, "\uFFFD") {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&sb, `\u%04X`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// tomlKey returns k as a bare key when possible, else quoted.
+func tomlKey(k string) string {
+	if k == "" {
+		return `""`
+	}
+	for _, r := range k {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return tomlString(k)
+		}
+	}
+	return k
+}
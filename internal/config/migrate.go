@@ -0,0 +1,609 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/namyoungkim/visor/internal/render"
+)
+
+// ConfigVersion is the config schema version of this visor. Files without
+// a version key predate versioning and are treated as version 1.
+const ConfigVersion = 2
+
+// Migration upgrades config source to Version from the version before it.
+type Migration struct {
+	Version     int
+	Description string
+	Apply       func(doc *Document)
+}
+
+// Migrations lists the schema migrations in version order.
+var Migrations = []Migration{
+	{
+		Version:     2,
+		Description: "normalize color names, remove unused widget options",
+		Apply:       migrateV2,
+	},
+}
+
+// migrateV2 upgrades configs written by visor 0.11 and earlier.
+func migrateV2(doc *Document) {
+	for _, e := range doc.Entries() {
+		switch {
+		case isColorEntry(e):
+			if s, ok := e.String(); ok {
+				if color := normalizeColor(s, e.Key == "bg"); color != s {
+					doc.Set(e, tomlString(color), "color names are lowercase with bright_ prefix")
+				}
+			}
+		case e.Key == "show_label" && strings.HasSuffix(e.Table, ".extra") && doc.WidgetName(e.Table) == "api_latency":
+			doc.Remove(e, "api_latency has no show_label option")
+		}
+	}
+}
+
+// isColorEntry reports whether e holds a single color: [theme.colors]
+// values and widget style fg/bg.
+func isColorEntry(e *Entry) bool {
+	switch unindexed(e.Table) {
+	case "theme.colors":
+		return e.Key != "backgrounds"
+	case "line.widget.style", "line.left.style", "line.right.style":
+		return e.Key == "fg" || e.Key == "bg"
+	}
+	return false
+}
+
+// normalizeColor rewrites color names accepted before version 2
+// ("BrightRed", "brightred") to renderer names ("bright_red"). Values that
+// don't become valid are returned unchanged for --check to report.
+func normalizeColor(color string, bg bool) string {
+	valid := render.IsColor
+	if bg {
+		valid = render.IsBgColor
+	}
+	if valid(color) || strings.HasPrefix(color, "#") {
+		return color
+	}
+	name := strings.ToLower(color)
+	if rest, ok := strings.CutPrefix(name, "bright"); ok && !strings.HasPrefix(rest, "_") {
+		name = "bright_" + rest
+	}
+	if valid(name) {
+		return name
+	}
+	return color
+}
+
+// Change is one edit made by a migration.
+type Change struct {
+	Line        int // 1-based line in the original source; 0 for added lines
+	Version     int // Migration that made the change
+	Description string
+}
+
+// MigrateResult is the outcome of upgrading config source.
+type MigrateResult struct {
+	From, To int
+	Source   string // Upgraded source with the version key set
+	Changes  []Change
+}
+
+// Migrate upgrades config source to ConfigVersion. Source already at
+// ConfigVersion is returned unchanged.
+func Migrate(src string) (*MigrateResult, error) {
+	from, err := sourceVersion(src)
+	if err != nil {
+		return nil, err
+	}
+	if from > ConfigVersion {
+		return nil, fmt.Errorf("config version %d is newer than this visor supports (%d)", from, ConfigVersion)
+	}
+
+	result := &MigrateResult{From: from, To: ConfigVersion, Source: src}
+	if from == ConfigVersion {
+		return result, nil
+	}
+	doc := parseDocument(src)
+	applyMigrations(doc, from, Migrations)
+	doc.setVersion(ConfigVersion)
+	result.Source = doc.String()
+	result.Changes = doc.changes
+	return result, nil
+}
+
+// migrateInMemory applies pending migrations without setting the version
+// key. Origins and diagnostics map lines back with OriginalLine.
+func migrateInMemory(src string, from int) *Document {
+	doc := parseDocument(src)
+	applyMigrations(doc, from, Migrations)
+	return doc
+}
+
+func applyMigrations(doc *Document, from int, migrations []Migration) {
+	for _, m := range migrations {
+		if m.Version > from {
+			doc.version = m.Version
+			m.Apply(doc)
+		}
+	}
+}
+
+// sourceVersion returns the version key of config source, 1 if unset.
+func sourceVersion(src string) (int, error) {
+	var v struct {
+		Version int `toml:"version"`
+	}
+	if _, err := toml.Decode(src, &v); err != nil {
+		return 0, err
+	}
+	return fileVersion(v.Version), nil
+}
+
+func fileVersion(v int) int {
+	if v == 0 {
+		return 1
+	}
+	return v
+}
+
+// Document is config source that migrations edit. Edits rewrite single
+// lines in place, so comments and layout elsewhere are kept; only Move and
+// the version key add lines, and OriginalLine maps lines back to the
+// source. Multi-line strings and arrays are not edited.
+type Document struct {
+	lines    []string
+	orig     []int // 1-based source line of each line; 0 for added lines
+	entries  []*Entry
+	sections []*section
+	changes  []Change
+	version  int // Migration being applied
+}
+
+// section is a table header and the lines up to the next one.
+type section struct {
+	table  string // Indexed table path, e.g. "line[0].widget[1].extra"
+	header int    // Line index of the header
+	last   int    // Line index of the last non-blank, non-comment line
+	indent string // Indent of the header
+}
+
+// Entry is a key = value pair in a Document.
+type Entry struct {
+	Table string // Indexed table path, e.g. "line[0].widget[1].extra"
+	Key   string
+	Value string // Raw TOML value, e.g. `"bright_red"` or `true`
+	Line  int    // 1-based, in the edited document
+
+	keyStart, keyEnd, valueStart, valueEnd int // Byte offsets in the line
+	inline                                 bool
+	removed                                bool
+}
+
+// Path returns the dotted key path of the entry.
+func (e *Entry) Path() string {
+	return joinKey(e.Table, e.Key)
+}
+
+// String returns the value of a string entry.
+func (e *Entry) String() (string, bool) {
+	var v struct{ V any }
+	if _, err := toml.Decode("V = "+e.Value, &v); err != nil {
+		return "", false
+	}
+	s, ok := v.V.(string)
+	return s, ok
+}
+
+// Entries returns the entries not removed by a migration, in source order.
+func (d *Document) Entries() []*Entry {
+	var entries []*Entry
+	for _, e := range d.entries {
+		if !e.removed {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Lookup returns the entry for key in table, or nil.
+func (d *Document) Lookup(table, key string) *Entry {
+	for _, e := range d.Entries() {
+		if e.Table == table && e.Key == key {
+			return e
+		}
+	}
+	return nil
+}
+
+// WidgetName returns the name of the widget owning table, e.g. the widget
+// of "line[0].widget[2].extra".
+func (d *Document) WidgetName(table string) string {
+	i := strings.LastIndex(table, "]")
+	if i < 0 {
+		return ""
+	}
+	if e := d.Lookup(table[:i+1], "name"); e != nil {
+		s, _ := e.String()
+		return s
+	}
+	return ""
+}
+
+// Set replaces the value of e with a raw TOML value.
+func (d *Document) Set(e *Entry, value, why string) {
+	d.record(e.Line, fmt.Sprintf("%s: %s → %s (%s)", e.Path(), e.Value, value, why))
+	d.replace(e, e.valueStart, e.valueEnd, value)
+	e.Value = value
+}
+
+// Rename changes the key of e within its table.
+func (d *Document) Rename(e *Entry, key, why string) {
+	d.record(e.Line, fmt.Sprintf("%s → %s (%s)", e.Path(), joinKey(e.Table, key), why))
+	d.replace(e, e.keyStart, e.keyEnd, key)
+	e.Key = key
+}
+
+// Remove deletes e. A key on its own line is commented out with the
+// reason; an inline table entry is cut from the line.
+func (d *Document) Remove(e *Entry, why string) {
+	d.record(e.Line, fmt.Sprintf("%s removed (%s)", e.Path(), why))
+	d.drop(e, fmt.Sprintf("removed in config version %d: %s", d.version, why))
+}
+
+// Move moves e with its value to the end of table, dropping the old key
+// like Remove. A table without a header gets one after its parent's last
+// table. Returns false, changing nothing, when table is an array element
+// or belongs to one that doesn't exist.
+func (d *Document) Move(e *Entry, table, why string) bool {
+	at, indent, header := d.insertionPoint(table)
+	if at < 0 {
+		return false
+	}
+	path := joinKey(table, e.Key)
+	d.record(e.Line, fmt.Sprintf("%s → %s (%s)", e.Path(), path, why))
+	d.drop(e, fmt.Sprintf("moved to %s in config version %d", path, d.version))
+
+	d.insertLines(at, append(header, indent+e.Key+" = "+e.Value)...)
+	line := at + len(header) + 1
+	if header != nil {
+		d.sections = append(d.sections, &section{table: table, header: line - 2, indent: indent})
+		sort.SliceStable(d.sections, func(i, j int) bool { return d.sections[i].header < d.sections[j].header })
+	}
+	for _, s := range d.sections {
+		if s.table == table {
+			s.last = line - 1
+		}
+	}
+
+	d.scanEntry(line, d.lines[line-1], len(indent), table, false)
+	sort.SliceStable(d.entries, func(i, j int) bool { return d.entries[i].Line < d.entries[j].Line })
+	return true
+}
+
+// insertionPoint returns the line index where a key of table goes, its
+// indent, and the header lines to add first when table has none.
+func (d *Document) insertionPoint(table string) (int, string, []string) {
+	for _, s := range d.sections {
+		if s.table == table {
+			indent := s.indent
+			if s.last > s.header {
+				indent = leadingSpace(d.lines[s.last])
+			}
+			return s.last + 1, indent, nil
+		}
+	}
+	if table == "" || strings.HasSuffix(table, "]") {
+		return -1, "", nil
+	}
+
+	// After the parent table and its subtables
+	parent := ""
+	if i := strings.LastIndexByte(table, '.'); i >= 0 {
+		parent = table[:i]
+	}
+	at, indent := -1, ""
+	for _, s := range d.sections {
+		if parent == "" || s.table == parent || strings.HasPrefix(s.table, parent+".") {
+			at = s.last + 1
+			if s.table == parent {
+				indent = s.indent
+			}
+		}
+	}
+	header := []string{indent + "[" + unindexed(table) + "]"}
+	if at < 0 {
+		if strings.Contains(table, "[") {
+			return -1, "", nil
+		}
+		at = len(d.lines)
+		if at > 0 && d.lines[at-1] == "" {
+			at--
+		}
+	}
+	if parent == "" && at > 0 {
+		header = append([]string{""}, header...)
+	}
+	return at, indent, header
+}
+
+// insertLines adds lines before line index at, shifting the entries and
+// sections after it.
+func (d *Document) insertLines(at int, lines ...string) {
+	n := len(lines)
+	d.lines = append(d.lines[:at], append(append([]string{}, lines...), d.lines[at:]...)...)
+	d.orig = append(d.orig[:at], append(make([]int, n), d.orig[at:]...)...)
+	for _, e := range d.entries {
+		if e.Line > at {
+			e.Line += n
+		}
+	}
+	for _, s := range d.sections {
+		if s.header >= at {
+			s.header += n
+		}
+		if s.last >= at {
+			s.last += n
+		}
+	}
+}
+
+// OriginalLine returns the source line of 1-based line in the edited
+// document, or 0 for a line added by a migration.
+func (d *Document) OriginalLine(line int) int {
+	if line < 1 || line > len(d.orig) {
+		return 0
+	}
+	return d.orig[line-1]
+}
+
+// drop comments out e's line with note, or cuts an inline table entry
+// from its line.
+func (d *Document) drop(e *Entry, note string) {
+	text := d.lines[e.Line-1]
+
+	if !e.inline {
+		d.lines[e.Line-1] = fmt.Sprintf("%s# %s  # %s", leadingSpace(text), strings.TrimSpace(text), note)
+		for _, other := range d.entries {
+			if other.Line == e.Line {
+				other.removed = true
+			}
+		}
+		return
+	}
+
+	// Take the following comma, or the preceding one for the last entry
+	start, end := e.keyStart, e.valueEnd
+	rest := strings.TrimLeft(text[end:], " \t")
+	if strings.HasPrefix(rest, ",") {
+		end = len(text) - len(strings.TrimLeft(rest[1:], " \t"))
+	} else if before := strings.TrimRight(text[:start], " \t"); strings.HasSuffix(before, ",") {
+		start = len(before) - 1
+	}
+	d.replace(e, start, end, "")
+	e.removed = true
+}
+
+// replace rewrites bytes [start, end) of e's line, shifting the offsets
+// at or after end on the same line.
+func (d *Document) replace(e *Entry, start, end int, text string) {
+	line := d.lines[e.Line-1]
+	d.lines[e.Line-1] = line[:start] + text + line[end:]
+	delta := len(text) - (end - start)
+
+	for _, other := range d.entries {
+		if other.Line != e.Line {
+			continue
+		}
+		for _, off := range []*int{&other.keyStart, &other.keyEnd, &other.valueStart, &other.valueEnd} {
+			if *off >= end {
+				*off += delta
+			}
+		}
+	}
+}
+
+func (d *Document) record(line int, description string) {
+	d.changes = append(d.changes, Change{Line: d.OriginalLine(line), Version: d.version, Description: description})
+}
+
+func leadingSpace(text string) string {
+	return text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+}
+
+// setVersion sets the top-level version key, adding it before the first
+// key or table if needed.
+func (d *Document) setVersion(version int) {
+	value := strconv.Itoa(version)
+	if e := d.Lookup("", "version"); e != nil {
+		if e.Value != value {
+			d.Set(e, value, "config version")
+		}
+		return
+	}
+
+	at := len(d.lines)
+	for i, text := range d.lines {
+		if trimmed := strings.TrimSpace(text); trimmed != "" && trimmed[0] != '#' {
+			at = i
+			break
+		}
+	}
+	lines := []string{"version = " + value}
+	if at < len(d.lines) && strings.HasPrefix(strings.TrimSpace(d.lines[at]), "[") {
+		lines = append(lines, "")
+	}
+	d.insertLines(at, lines...)
+	d.changes = append(d.changes, Change{Version: version, Description: "version = " + value + " added"})
+}
+
+// String returns the edited source.
+func (d *Document) String() string {
+	return strings.Join(d.lines, "\n")
+}
+
+// parseDocument scans config source for key = value entries, tracking the
+// current table like locateKeys.
+func parseDocument(src string) *Document {
+	d := &Document{lines: strings.Split(src, "\n")}
+	d.orig = make([]int, len(d.lines))
+	for i := range d.orig {
+		d.orig[i] = i + 1
+	}
+	counts := make(map[string]int)
+	table := ""
+	closing := ""
+	var current *section
+
+	for n, text := range d.lines {
+		if closing != "" {
+			if strings.Contains(text, closing) {
+				closing = ""
+			}
+			if current != nil {
+				current.last = n
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "[["):
+			parts := splitKey(headerName(trimmed, "[[", "]]"))
+			if len(parts) == 0 {
+				continue
+			}
+			array := joinKey(resolveKey(parts[:len(parts)-1], counts), parts[len(parts)-1])
+			table = fmt.Sprintf("%s[%d]", array, counts[array])
+			counts[array]++
+			current = &section{table: table, header: n, last: n, indent: leadingSpace(text)}
+			d.sections = append(d.sections, current)
+
+		case trimmed[0] == '[':
+			table = resolveKey(splitKey(headerName(trimmed, "[", "]")), counts)
+			current = &section{table: table, header: n, last: n, indent: leadingSpace(text)}
+			d.sections = append(d.sections, current)
+
+		default:
+			if current != nil {
+				current.last = n
+			}
+			start := len(text) - len(strings.TrimLeft(text, " \t"))
+			if _, open := d.scanEntry(n+1, text, start, table, false); open != "" {
+				closing = open
+			}
+		}
+	}
+	return d
+}
+
+// scanEntry parses the key = value at text[pos:], returning the offset
+// after the value and the delimiter of an unterminated multi-line string.
+func (d *Document) scanEntry(line int, text string, pos int, table string, inline bool) (int, string) {
+	eq := strings.IndexByte(text[pos:], '=')
+	if eq < 0 {
+		return len(text), ""
+	}
+	rawKey := text[pos : pos+eq]
+	keyEnd := pos + len(strings.TrimRight(rawKey, " \t"))
+	parts := splitKey(rawKey)
+	if len(parts) == 0 {
+		return len(text), ""
+	}
+	if n := len(parts); n > 1 {
+		table = joinKey(table, parts[:n-1]...)
+		pos = keyEnd - len(strings.TrimSpace(rawKey[strings.LastIndexByte(rawKey, '.')+1:]))
+	}
+
+	valueStart := pos + eq + 1
+	for valueStart < len(text) && (text[valueStart] == ' ' || text[valueStart] == '\t') {
+		valueStart++
+	}
+	e := &Entry{Table: table, Key: parts[len(parts)-1], Line: line,
+		keyStart: pos, keyEnd: keyEnd, valueStart: valueStart, inline: inline}
+	d.entries = append(d.entries, e)
+
+	end, open := d.scanValue(line, text, valueStart, e.Path())
+	e.valueEnd = end
+	e.Value = text[valueStart:end]
+	return end, open
+}
+
+// scanValue returns the end offset of the value at text[pos:]. Inline
+// table entries are added to the document.
+func (d *Document) scanValue(line int, text string, pos int, path string) (int, string) {
+	if pos >= len(text) {
+		return pos, ""
+	}
+	switch text[pos] {
+	case '"', '\'':
+		quote := text[pos : pos+1]
+		if strings.HasPrefix(text[pos:], quote+quote+quote) {
+			delim := quote + quote + quote
+			if i := strings.Index(text[pos+3:], delim); i >= 0 {
+				return pos + 3 + i + 3, ""
+			}
+			return len(text), delim
+		}
+		for i := pos + 1; i < len(text); i++ {
+			switch {
+			case text[i] == '\\' && quote == `"`:
+				i++
+			case text[i] == quote[0]:
+				return i + 1, ""
+			}
+		}
+		return len(text), ""
+
+	case '{':
+		i := pos + 1
+		for i < len(text) {
+			for i < len(text) && (text[i] == ' ' || text[i] == '\t' || text[i] == ',') {
+				i++
+			}
+			if i >= len(text) || text[i] == '}' {
+				break
+			}
+			i, _ = d.scanEntry(line, text, i, path, true)
+		}
+		return min(i+1, len(text)), ""
+
+	case '[':
+		depth := 0
+		var quote byte
+		for i := pos; i < len(text); i++ {
+			switch c := text[i]; {
+			case quote != 0:
+				if c == '\\' && quote == '"' {
+					i++
+				} else if c == quote {
+					quote = 0
+				}
+			case c == '"' || c == '\'':
+				quote = c
+			case c == '[':
+				depth++
+			case c == ']':
+				depth--
+				if depth == 0 {
+					return i + 1, ""
+				}
+			}
+		}
+		return len(text), ""
+	}
+
+	end := pos
+	for end < len(text) && !strings.ContainsRune(" \t,}#", rune(text[end])) {
+		end++
+	}
+	return end, ""
+}
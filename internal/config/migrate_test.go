@@ -0,0 +1,255 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+const migrateV1 = `# my config
+
+[theme]
+name = "default"
+  [theme.colors]
+  warning = "BrightYellow"  # brighter
+  backgrounds = ["black"]
+
+[[line]]
+  [[line.widget]]
+  name = "api_latency"
+  style = { fg = "brightred", bold = true }
+  [line.widget.extra]
+  show_label = "true"
+  [[line.widget]]
+  name = "cost"
+  [line.widget.extra]
+  show_label = "true"
+`
+
+func TestMigrate_V1(t *testing.T) {
+	result, err := Migrate(migrateV1)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if result.From != 1 || result.To != ConfigVersion {
+		t.Errorf("From/To = %d/%d, want 1/%d", result.From, result.To, ConfigVersion)
+	}
+
+	for _, want := range []string{
+		"# my config\n\nversion = 2\n\n[theme]\n",
+		`  warning = "bright_yellow"  # brighter`,
+		`  style = { fg = "bright_red", bold = true }`,
+		`  # show_label = "true"  # removed in config version 2: api_latency has no show_label option`,
+	} {
+		if !strings.Contains(result.Source, want) {
+			t.Errorf("Source missing %q in:\n%s", want, result.Source)
+		}
+	}
+	// Options of other widgets are kept
+	if !strings.HasSuffix(result.Source, "  name = \"cost\"\n  [line.widget.extra]\n  show_label = \"true\"\n") {
+		t.Errorf("cost show_label should be kept:\n%s", result.Source)
+	}
+	if len(result.Changes) != 4 {
+		t.Errorf("Changes = %+v, want 4", result.Changes)
+	}
+	if c := result.Changes[0]; c.Line != 6 || c.Version != 2 {
+		t.Errorf("Changes[0] = %+v, want line 6 from version 2", c)
+	}
+
+	// The result is current and migrates to itself
+	again, err := Migrate(result.Source)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if again.Source != result.Source || len(again.Changes) != 0 {
+		t.Errorf("migrating a current config changed it: %+v", again.Changes)
+	}
+}
+
+func TestMigrate_NewerVersion(t *testing.T) {
+	if _, err := Migrate("version = 99\n"); err == nil {
+		t.Error("Migrate() should reject a newer config version")
+	}
+}
+
+func TestMigrate_ExistingVersionKey(t *testing.T) {
+	result, err := Migrate("version = 1\n[general]\n")
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if result.Source != "version = 2\n[general]\n" {
+		t.Errorf("Source = %q", result.Source)
+	}
+}
+
+func TestDocument_Edits(t *testing.T) {
+	doc := parseDocument(`[[line]]
+  [[line.widget]]
+  name = "old_name"
+  extra = { max = "3", sort = "count" }
+  [[line.widget]]
+  name = "other"
+`)
+	applyMigrations(doc, 1, []Migration{{
+		Version: 2,
+		Apply: func(doc *Document) {
+			for _, e := range doc.Entries() {
+				switch {
+				case e.Key == "name" && e.Value == `"old_name"`:
+					doc.Set(e, `"new_name"`, "renamed")
+				case e.Key == "max" && doc.WidgetName(e.Table) == "new_name":
+					doc.Rename(e, "max_display", "moved")
+				case e.Key == "sort":
+					doc.Remove(e, "deprecated")
+				}
+			}
+		},
+	}})
+
+	want := `[[line]]
+  [[line.widget]]
+  name = "new_name"
+  extra = { max_display = "3" }
+  [[line.widget]]
+  name = "other"
+`
+	if got := doc.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+	if len(doc.changes) != 3 {
+		t.Errorf("changes = %+v, want 3", doc.changes)
+	}
+}
+
+func TestDocument_Move(t *testing.T) {
+	doc := parseDocument(`# visor
+[general]
+separator = " | "
+block_warn = "80"
+
+[[line]]
+  [[line.widget]]
+  name = "block_timer"
+  style = { fg = "red", show_label = "true" }
+  [[line.widget]]
+  name = "cost"
+  [line.widget.extra]
+  show_label = "true"
+`)
+	applyMigrations(doc, 1, []Migration{{
+		Version: 2,
+		Apply: func(doc *Document) {
+			for _, e := range doc.Entries() {
+				switch {
+				case e.Path() == "general.separator":
+					doc.Move(e, "display", "new table")
+				case e.Path() == "general.block_warn":
+					doc.Move(e, "line[0].widget[0].extra", "widget option")
+					doc.Rename(doc.Lookup("line[0].widget[0].extra", "block_warn"), "warn_threshold", "renamed")
+				case e.Key == "name" && e.Value == `"block_timer"`:
+					doc.Set(e, `"block_limit"`, "widget renamed")
+				case e.Key == "show_label" && e.inline:
+					doc.Move(e, "line[0].widget[0].extra", "widget option")
+				}
+			}
+		},
+	}})
+
+	want := `# visor
+[general]
+# separator = " | "  # moved to display.separator in config version 2
+# block_warn = "80"  # moved to line[0].widget[0].extra.block_warn in config version 2
+
+[[line]]
+  [[line.widget]]
+  name = "block_limit"
+  style = { fg = "red" }
+  [line.widget.extra]
+  warn_threshold = "80"
+  show_label = "true"
+  [[line.widget]]
+  name = "cost"
+  [line.widget.extra]
+  show_label = "true"
+
+[display]
+separator = " | "
+`
+	if got := doc.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+
+	var cfg Config
+	if _, err := toml.Decode(doc.String(), &cfg); err != nil {
+		t.Fatalf("moved document does not decode: %v", err)
+	}
+	if extra := cfg.Lines[0].Widgets[0].Extra; extra["warn_threshold"] != "80" || extra["show_label"] != "true" {
+		t.Errorf("Extra = %v, want moved options", extra)
+	}
+
+	var lines []int
+	for _, c := range doc.changes {
+		lines = append(lines, c.Line)
+	}
+	if fmt.Sprint(lines) != "[3 4 0 8 9]" {
+		t.Errorf("change lines = %v, want [3 4 0 8 9]", lines)
+	}
+	// Lines after the added ones map back to the source
+	if got := doc.OriginalLine(14); got != 11 {
+		t.Errorf("OriginalLine(14) = %d, want 11", got)
+	}
+	if got := doc.OriginalLine(11); got != 0 {
+		t.Errorf("OriginalLine(11) = %d, want 0 for an added line", got)
+	}
+}
+
+func TestDocument_MoveToMissingArrayElement(t *testing.T) {
+	doc := parseDocument("[general]\nseparator = \" | \"\n")
+	doc.version = 2
+	if doc.Move(doc.Lookup("general", "separator"), "line[3].widget[0]", "x") {
+		t.Error("Move() into a missing array element should fail")
+	}
+	if doc.String() != "[general]\nseparator = \" | \"\n" || len(doc.changes) != 0 {
+		t.Errorf("failed Move() changed the document:\n%s", doc.String())
+	}
+}
+
+func TestLoadLayered_UpgradesOldFile(t *testing.T) {
+	path := writeConfig(t, migrateV1)
+
+	l, err := LoadLayered(LoadOptions{GlobalPath: path})
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+	if got := l.Config.Theme.Colors.Warning; got != "bright_yellow" {
+		t.Errorf("Warning = %q, want bright_yellow", got)
+	}
+	if _, ok := l.Config.Lines[0].Widgets[0].Extra["show_label"]; ok {
+		t.Error("api_latency show_label should be removed")
+	}
+	if l.Config.Version != ConfigVersion {
+		t.Errorf("Version = %d, want %d", l.Config.Version, ConfigVersion)
+	}
+	// Line numbers are unchanged by the in-memory upgrade
+	if got := l.Origin("theme.colors.warning"); got != path+":6" {
+		t.Errorf("Origin(theme.colors.warning) = %q, want %s:6", got, path)
+	}
+	if len(l.Warnings) != 1 || !strings.Contains(l.Warnings[0], "visor config migrate") {
+		t.Errorf("Warnings = %v, want a migrate hint", l.Warnings)
+	}
+}
+
+func TestLoad_UpgradesOldFile(t *testing.T) {
+	cfg, err := Load(writeConfig(t, migrateV1))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Lines[0].Widgets[0].Style.Fg != "bright_red" {
+		t.Errorf("Fg = %q, want bright_red", cfg.Lines[0].Widgets[0].Style.Fg)
+	}
+	if cfg.Version != ConfigVersion {
+		t.Errorf("Version = %d, want %d", cfg.Version, ConfigVersion)
+	}
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OptionType is the value type of a widget extra option.
+type OptionType int
+
+const (
+	OptionBool OptionType = iota
+	OptionInt
+	OptionFloat
+	OptionString
+)
+
+// String returns the option type name used in diagnostics.
+func (t OptionType) String() string {
+	switch t {
+	case OptionBool:
+		return "a boolean"
+	case OptionInt:
+		return "an integer"
+	case OptionFloat:
+		return "a number"
+	}
+	return "a string"
+}
+
+// Name returns the short type name used in docs and the TUI.
+func (t OptionType) Name() string {
+	switch t {
+	case OptionBool:
+		return "bool"
+	case OptionInt:
+		return "int"
+	case OptionFloat:
+		return "float"
+	}
+	return "string"
+}
+
+// Option describes one extra option of a widget. Values are strings in
+// TOML, so Default, Min and Max are written the same way.
+type Option struct {
+	Key         string
+	Type        OptionType
+	Default     string
+	Min         string   // Lower bound for int/float options; "" for none
+	Max         string   // Upper bound for int/float options; "" for none
+	Enum        []string // Allowed values for string options; nil for any
+	Description string
+}
+
+// Validate reports why value is not acceptable for the option.
+func (o Option) Validate(value string) error {
+	switch o.Type {
+	case OptionBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("must be %s, got %q", o.Type, value)
+		}
+	case OptionInt, OptionFloat:
+		n, err := o.parseNumber(value)
+		if err != nil {
+			return fmt.Errorf("must be %s, got %q", o.Type, value)
+		}
+		if o.Min != "" && n < o.bound(o.Min) || o.Max != "" && n > o.bound(o.Max) {
+			return fmt.Errorf("must be %s, got %s", o.Range(), value)
+		}
+	case OptionString:
+		if o.Enum != nil && !o.allows(value) {
+			return fmt.Errorf("must be one of %s, got %q", strings.Join(o.Enum, ", "), value)
+		}
+	}
+	return nil
+}
+
+// Range describes the numeric bounds, e.g. "between 1 and 50" or
+// "at least 0". It is empty for unbounded options.
+func (o Option) Range() string {
+	switch {
+	case o.Min != "" && o.Max != "":
+		return fmt.Sprintf("between %s and %s", o.Min, o.Max)
+	case o.Min != "":
+		return "at least " + o.Min
+	case o.Max != "":
+		return "at most " + o.Max
+	}
+	return ""
+}
+
+func (o Option) parseNumber(value string) (float64, error) {
+	if o.Type == OptionInt {
+		n, err := strconv.Atoi(value)
+		return float64(n), err
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func (o Option) bound(s string) float64 {
+	n, _ := strconv.ParseFloat(s, 64)
+	return n
+}
+
+func (o Option) allows(value string) bool {
+	for _, v := range o.Enum {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Options is the option schema of a widget. Its getters read a value from
+// the widget config and fall back to the declared default when the value
+// is unset or not valid for the option.
+type Options []Option
+
+// Lookup returns the option with the given key.
+func (o Options) Lookup(key string) (Option, bool) {
+	for _, opt := range o {
+		if opt.Key == key {
+			return opt, true
+		}
+	}
+	return Option{}, false
+}
+
+// Keys returns the option keys in declaration order.
+func (o Options) Keys() []string {
+	keys := make([]string, len(o))
+	for i, opt := range o {
+		keys[i] = opt.Key
+	}
+	return keys
+}
+
+// Value returns the string value of key.
+func (o Options) Value(cfg *WidgetConfig, key string) string {
+	opt, ok := o.Lookup(key)
+	v, set := cfg.Extra[key]
+	switch {
+	case !ok:
+		return v
+	case !set || v == "":
+		return opt.Default
+	case opt.Type == OptionBool:
+		return v // Read leniently by Bool
+	case opt.Validate(v) != nil:
+		return opt.Default
+	}
+	return v
+}
+
+// Bool returns the boolean value of key. "true", "1" and "yes" are true.
+func (o Options) Bool(cfg *WidgetConfig, key string) bool {
+	v := o.Value(cfg, key)
+	return v == "true" || v == "1" || v == "yes"
+}
+
+// Int returns the integer value of key.
+func (o Options) Int(cfg *WidgetConfig, key string) int {
+	n, _ := strconv.Atoi(o.Value(cfg, key))
+	return n
+}
+
+// Float returns the numeric value of key.
+func (o Options) Float(cfg *WidgetConfig, key string) float64 {
+	f, _ := strconv.ParseFloat(o.Value(cfg, key), 64)
+	return f
+}
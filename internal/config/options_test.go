@@ -0,0 +1,76 @@
+package config
+
+import "testing"
+
+var testOptions = Options{
+	{Key: "show_bar", Type: OptionBool, Default: "true"},
+	{Key: "bar_width", Type: OptionInt, Default: "10", Min: "1", Max: "50"},
+	{Key: "warn", Type: OptionFloat, Default: "0.5", Min: "0"},
+	{Key: "mode", Type: OptionString, Default: "flat", Enum: []string{"flat", "tree"}},
+	{Key: "text", Type: OptionString, Default: "hi"},
+}
+
+func TestOption_Validate(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		want  string
+	}{
+		{"show_bar", "false", ""},
+		{"show_bar", "yes", `must be a boolean, got "yes"`},
+		{"bar_width", "50", ""},
+		{"bar_width", "0", "must be between 1 and 50, got 0"},
+		{"bar_width", "1.5", `must be an integer, got "1.5"`},
+		{"warn", "-1", "must be at least 0, got -1"},
+		{"mode", "tree", ""},
+		{"mode", "Tree", `must be one of flat, tree, got "Tree"`},
+		{"text", "anything", ""},
+	}
+
+	for _, tt := range tests {
+		opt, _ := testOptions.Lookup(tt.key)
+		err := opt.Validate(tt.value)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("%s=%q: Validate() = %q, want %q", tt.key, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestOptions_Getters(t *testing.T) {
+	unset := &WidgetConfig{}
+	if !testOptions.Bool(unset, "show_bar") || testOptions.Int(unset, "bar_width") != 10 ||
+		testOptions.Float(unset, "warn") != 0.5 || testOptions.Value(unset, "mode") != "flat" {
+		t.Error("unset options should return declared defaults")
+	}
+
+	cfg := &WidgetConfig{Extra: map[string]string{
+		"show_bar":  "yes",
+		"bar_width": "99",
+		"warn":      "abc",
+		"mode":      "tree",
+		"text":      "",
+		"other":     "x",
+	}}
+	if !testOptions.Bool(cfg, "show_bar") {
+		t.Error(`Bool("yes") should be true`)
+	}
+	if got := testOptions.Int(cfg, "bar_width"); got != 10 {
+		t.Errorf("out-of-range Int = %d, want default 10", got)
+	}
+	if got := testOptions.Float(cfg, "warn"); got != 0.5 {
+		t.Errorf("invalid Float = %v, want default 0.5", got)
+	}
+	if got := testOptions.Value(cfg, "mode"); got != "tree" {
+		t.Errorf("Value(mode) = %q, want tree", got)
+	}
+	if got := testOptions.Value(cfg, "text"); got != "hi" {
+		t.Errorf("empty Value = %q, want default", got)
+	}
+	if got := testOptions.Value(cfg, "other"); got != "x" {
+		t.Errorf("undeclared Value = %q, want raw value", got)
+	}
+}
@@ -130,6 +130,8 @@ func GetPresetTOML(name string) (string, error) {
 # Preset: %s - %s
 # Place at ~/.config/visor/config.toml
 
+version = %d  # Config schema version (upgrade with: visor config migrate)
+
 [general]
 separator = " | "  # Widget separator
 
@@ -138,10 +140,19 @@ name = "default"   # Theme: default, powerline, gruvbox, nord, gruvbox-powerline
 
 [usage]
 enabled = true     # Enable usage tracking (daily/weekly cost, rate limits)
-# five_hour_limit = 0   # 0 = auto-detect from subscription tier (Pro: 45, Max 5x: 225, Max 20x: 900)
-# seven_day_limit = 0   # 0 = auto-detect from subscription tier
-
-`, p.Name, p.Description))
+# five_hour_limit = 0   # 0 = learned from past limit hits, else subscription tier (Pro: 45, Max 5x: 225, Max 20x: 900)
+# seven_day_limit = 0   # 0 = learned from past limit hits, else subscription tier
+# cache_ttl = 120       # Seconds before cached API limits are refreshed/marked stale
+# credential_source = "auto"  # auto, file, keychain, secret_service, encrypted_file
+# limit_sources = ["oauth", "headers", "local"]  # Tried in order; also "file", "command"
+# limits_file = ""      # JSON file for the "file" source
+# limits_command = ""   # Command printing limits JSON for the "command" source
+
+# [auth]
+# disable_refresh = false  # Don't refresh expired OAuth tokens
+# write_back = false       # Write refreshed tokens to Claude Code's credential file; required for refresh
+
+`, p.Name, p.Description, ConfigVersion))
 
 	// Generate widget configuration for each line
 	for lineIdx, widgets := range p.Lines {
@@ -3,6 +3,7 @@ package config
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -21,12 +22,9 @@ func Save(cfg *Config, path string) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Encode to TOML
 	var buf bytes.Buffer
-	encoder := toml.NewEncoder(&buf)
-	encoder.Indent = "  "
-	if err := encoder.Encode(cfg); err != nil {
-		return fmt.Errorf("failed to encode config: %w", err)
+	if err := Encode(&buf, cfg); err != nil {
+		return err
 	}
 
 	// Write to file
@@ -37,6 +35,16 @@ func Save(cfg *Config, path string) error {
 	return nil
 }
 
+// Encode writes the configuration as TOML, as Save does.
+func Encode(w io.Writer, cfg *Config) error {
+	encoder := toml.NewEncoder(w)
+	encoder.Indent = "  "
+	if err := encoder.Encode(cfg); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	return nil
+}
+
 // DeepCopy creates a deep copy of the configuration
 func DeepCopy(cfg *Config) *Config {
 	if cfg == nil {
@@ -44,18 +52,25 @@ func DeepCopy(cfg *Config) *Config {
 	}
 
 	newCfg := &Config{
-		General: GeneralConfig{
-			Separator: cfg.General.Separator,
-		},
+		Version: cfg.Version,
+		General: cfg.General,
 		Theme: ThemeConfig{
 			Name:      cfg.Theme.Name,
 			Powerline: cfg.Theme.Powerline,
 		},
 		Usage: UsageConfig{
-			Enabled:     cfg.Usage.Enabled,
-			Provider:    cfg.Usage.Provider,
-			ProjectsDir: cfg.Usage.ProjectsDir,
+			Enabled:          cfg.Usage.Enabled,
+			Provider:         cfg.Usage.Provider,
+			ProjectsDir:      cfg.Usage.ProjectsDir,
+			FiveHourLimit:    cfg.Usage.FiveHourLimit,
+			SevenDayLimit:    cfg.Usage.SevenDayLimit,
+			CacheTTL:         cfg.Usage.CacheTTL,
+			CredentialSource: cfg.Usage.CredentialSource,
+			LimitSources:     append([]string(nil), cfg.Usage.LimitSources...),
+			LimitsFile:       cfg.Usage.LimitsFile,
+			LimitsCommand:    cfg.Usage.LimitsCommand,
 		},
+		Auth:  cfg.Auth,
 		Lines: make([]Line, len(cfg.Lines)),
 	}
 
@@ -126,11 +141,7 @@ func deepCopyWidgetConfig(w WidgetConfig) WidgetConfig {
 	newW := WidgetConfig{
 		Name:   w.Name,
 		Format: w.Format,
-		Style: StyleConfig{
-			Fg:   w.Style.Fg,
-			Bg:   w.Style.Bg,
-			Bold: w.Style.Bold,
-		},
+		Style:  w.Style,
 	}
 
 	if w.Extra != nil {
@@ -112,6 +112,7 @@ func TestSave_SplitLayout(t *testing.T) {
 
 func TestDeepCopy_PreservesValues(t *testing.T) {
 	original := &Config{
+		Version: ConfigVersion,
 		General: GeneralConfig{
 			Separator: " :: ",
 		},
@@ -131,6 +132,10 @@ func TestDeepCopy_PreservesValues(t *testing.T) {
 	copy := DeepCopy(original)
 
 	// Verify values are preserved
+	if copy.Version != original.Version {
+		t.Errorf("Expected version %d, got %d", original.Version, copy.Version)
+	}
+
 	if copy.General.Separator != original.General.Separator {
 		t.Errorf("Expected separator '%s', got '%s'", original.General.Separator, copy.General.Separator)
 	}
@@ -289,11 +294,16 @@ func TestDeepCopy_UsageConfig(t *testing.T) {
 			Enabled:     true,
 			Provider:    "claude_pro",
 			ProjectsDir: "/custom/path",
+			CacheTTL:    300,
 		},
 	}
 
 	copy := DeepCopy(original)
 
+	if copy.Usage.CacheTTL != 300 {
+		t.Errorf("Expected cache_ttl 300, got %d", copy.Usage.CacheTTL)
+	}
+
 	if !copy.Usage.Enabled {
 		t.Error("Expected usage enabled to be true")
 	}
@@ -2,9 +2,14 @@ package config
 
 // Config represents the visor configuration.
 type Config struct {
+	// Version is the config schema version (see ConfigVersion).
+	// Older files are upgraded when loaded.
+	Version int `toml:"version,omitempty"`
+
 	General GeneralConfig `toml:"general"`
 	Theme   ThemeConfig   `toml:"theme"`
 	Usage   UsageConfig   `toml:"usage"`
+	Auth    AuthConfig    `toml:"auth"`
 	Lines   []Line        `toml:"line"`
 }
 
@@ -12,6 +17,35 @@ type Config struct {
 type GeneralConfig struct {
 	Separator string `toml:"separator"`
 	Debug     bool   `toml:"debug"`
+
+	// LineMerge controls how this file's [[line]] tables combine with
+	// lower config layers: "replace" (default), "append" or "prepend".
+	LineMerge string `toml:"line_merge,omitempty"`
+
+	// Width is the terminal width in columns. 0 detects it (COLUMNS,
+	// /dev/tty, a parent process's terminal, tmux), falling back to 80.
+	Width int `toml:"width,omitempty"`
+
+	// WidthMargin is the number of columns reserved for Claude Code's own
+	// UI around the statusline; split layouts align to width - margin.
+	WidthMargin int `toml:"width_margin,omitempty"`
+
+	// Color is the color mode: "auto" (default; honors NO_COLOR,
+	// FORCE_COLOR, COLORTERM and TERM), "truecolor", "256", "16" or "none".
+	// Hex colors are downsampled to the nearest palette color.
+	Color string `toml:"color,omitempty"`
+
+	// Hyperlinks makes widgets clickable with OSC 8 links (git branch page,
+	// cwd and transcript files) in terminals that support them.
+	Hyperlinks bool `toml:"hyperlinks,omitempty"`
+
+	// AmbiguousWidth is the cell width (1 or 2) of East Asian Ambiguous
+	// characters such as "→" and "…". 0 means 1; set 2 for CJK terminals.
+	AmbiguousWidth int `toml:"ambiguous_width,omitempty"`
+
+	// PUAWidth is the cell width (1 or 2) of Private Use Area glyphs such
+	// as Nerd Font icons. 0 means 1.
+	PUAWidth int `toml:"pua_width,omitempty"`
 }
 
 // ThemeConfig contains theme settings.
@@ -74,6 +108,47 @@ type UsageConfig struct {
 	// SevenDayLimit is the message limit for the 7-day window.
 	// 0 = auto-detect from subscription tier.
 	SevenDayLimit int `toml:"seven_day_limit"`
+
+	// CacheTTL is how long (seconds) fetched usage limits stay fresh.
+	// Cached limits are served immediately and refreshed in the background.
+	// 0 = default (120 seconds).
+	CacheTTL int `toml:"cache_ttl"`
+
+	// CredentialSource selects where OAuth credentials are read from:
+	// "auto", "file", "keychain" (macOS), "secret_service" (Linux keyring),
+	// "encrypted_file". Empty means "auto".
+	CredentialSource string `toml:"credential_source"`
+
+	// LimitSources is the order in which usage limit sources are tried:
+	// "oauth", "headers", "file", "command", "local".
+	// Empty means ["oauth", "headers", "local"].
+	LimitSources []string `toml:"limit_sources"`
+
+	// LimitsFile is a JSON file with limits, used by the "file" source.
+	LimitsFile string `toml:"limits_file"`
+
+	// LimitsCommand is a shell command printing limits JSON, used by the "command" source.
+	LimitsCommand string `toml:"limits_command"`
+}
+
+// AuthConfig contains OAuth token handling settings.
+type AuthConfig struct {
+	// DisableRefresh turns off refresh-token exchange for expired tokens.
+	DisableRefresh bool `toml:"disable_refresh"`
+
+	// TokenURL overrides the OAuth token endpoint.
+	// Defaults to Claude Code's endpoint if empty.
+	TokenURL string `toml:"token_url"`
+
+	// ClientID overrides the OAuth client ID.
+	// Defaults to Claude Code's client ID if empty.
+	ClientID string `toml:"client_id"`
+
+	// WriteBack writes refreshed tokens back to Claude Code's credential file.
+	// Refresh tokens rotate on use, so expired tokens are refreshed only when
+	// this is on; otherwise visor would log Claude Code out. Keyring stores
+	// (macOS Keychain, Secret Service) are never modified or refreshed.
+	WriteBack bool `toml:"write_back"`
 }
 
 // Line represents a single line in the statusline.
@@ -94,9 +169,14 @@ type WidgetConfig struct {
 	Extra  map[string]string `toml:"extra"`
 }
 
-// StyleConfig contains ANSI styling options.
+// StyleConfig contains ANSI styling options. Colors are names, hex colors
+// or 256-color palette indices ("208"). A foreground color replaces the
+// widget's own colors.
 type StyleConfig struct {
-	Fg   string `toml:"fg"`
-	Bg   string `toml:"bg"`
-	Bold bool   `toml:"bold"`
+	Fg        string `toml:"fg"`
+	Bg        string `toml:"bg"`
+	Bold      bool   `toml:"bold"`
+	Dim       bool   `toml:"dim,omitempty"`
+	Italic    bool   `toml:"italic,omitempty"`
+	Underline bool   `toml:"underline,omitempty"`
 }
@@ -8,6 +8,12 @@ import (
 // BlockDuration is the length of a Claude Pro rate limit block.
 const BlockDuration = 5 * time.Hour
 
+// Windows for recent usage rates, used to forecast limit hits.
+const (
+	RecentWindow = 30 * time.Minute // Recent rate for the 5-hour limit
+	DayWindow    = 24 * time.Hour   // Recent rate for the 7-day limit
+)
+
 // CostData holds aggregated cost information.
 type CostData struct {
 	Today         float64 // Cost in current calendar day
@@ -26,6 +32,15 @@ type CostData struct {
 	TodayMessages         int // Messages in current calendar day
 	WeekMessages          int // Messages in current week (Monday-Sunday)
 	FiveHourBlockMessages int // Messages in current 5-hour block
+
+	// Weighted usage (Sonnet-input-token equivalents, see WeightedTokens)
+	WeekWeighted          float64 // Weighted usage in current week
+	FiveHourBlockWeighted float64 // Weighted usage in current 5-hour block
+	RecentWeighted        float64 // Weighted usage in the last RecentWindow
+	LastDayWeighted       float64 // Weighted usage in the last DayWindow
+
+	// Usage limit hits found in transcripts, for learning per-user limits
+	LimitHits []LimitHit
 }
 
 // Aggregate computes aggregated costs from entries.
@@ -51,6 +66,8 @@ func Aggregate(entries []Entry, blockStart time.Time) *CostData {
 	weekStart := StartOfWeek(now)
 	monthStart := startOfMonth(now)
 	blockEnd := blockStart.Add(BlockDuration)
+	recentStart := now.Add(-RecentWindow)
+	dayStart := now.Add(-DayWindow)
 
 	for _, e := range entries {
 		// User turns only count toward message limits, not cost
@@ -66,6 +83,9 @@ func Aggregate(entries []Entry, blockStart time.Time) *CostData {
 			}
 			continue
 		}
+		if e.LimitHit != "" {
+			continue
+		}
 
 		// Cost entries
 		if !e.Timestamp.Before(todayStart) {
@@ -73,15 +93,25 @@ func Aggregate(entries []Entry, blockStart time.Time) *CostData {
 		}
 		if !e.Timestamp.Before(weekStart) {
 			data.Week += e.CostUSD
+			data.WeekWeighted += WeightedTokens(e)
 		}
 		if !e.Timestamp.Before(monthStart) {
 			data.Month += e.CostUSD
 		}
 		if !blockStart.IsZero() && !e.Timestamp.Before(blockStart) && e.Timestamp.Before(blockEnd) {
 			data.FiveHourBlock += e.CostUSD
+			data.FiveHourBlockWeighted += WeightedTokens(e)
+		}
+		if !e.Timestamp.Before(recentStart) {
+			data.RecentWeighted += WeightedTokens(e)
+		}
+		if !e.Timestamp.Before(dayStart) {
+			data.LastDayWeighted += WeightedTokens(e)
 		}
 	}
 
+	data.LimitHits = detectLimitHits(entries, DetectBlocks(entries))
+
 	return data
 }
 
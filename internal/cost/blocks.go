@@ -0,0 +1,61 @@
+package cost
+
+import (
+	"sort"
+	"time"
+)
+
+// Block is a 5-hour usage block reconstructed from transcript activity.
+type Block struct {
+	Start        time.Time // First activity, floored to the hour
+	End          time.Time // Start + BlockDuration
+	LastActivity time.Time
+}
+
+// DetectBlocks reconstructs 5-hour blocks from entry timestamps.
+//
+// Like the service, a block starts at the first activity after the previous
+// block ended, floored to the hour, and lasts BlockDuration regardless of
+// activity inside it. Limit hit entries are not activity. Entries are sorted
+// by timestamp in place.
+func DetectBlocks(entries []Entry) []Block {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	var blocks []Block
+	for _, e := range entries {
+		if e.Timestamp.IsZero() || e.LimitHit != "" {
+			continue
+		}
+		if n := len(blocks); n > 0 && e.Timestamp.Before(blocks[n-1].End) {
+			blocks[n-1].LastActivity = e.Timestamp
+			continue
+		}
+		start := e.Timestamp.Truncate(time.Hour)
+		blocks = append(blocks, Block{
+			Start:        start,
+			End:          start.Add(BlockDuration),
+			LastActivity: e.Timestamp,
+		})
+	}
+	return blocks
+}
+
+// CurrentBlockStart returns the start of the block active at now,
+// or zero if there is no activity in the last BlockDuration.
+func CurrentBlockStart(blocks []Block, now time.Time) time.Time {
+	if b := blockAt(blocks, now); b != nil {
+		return b.Start
+	}
+	return time.Time{}
+}
+
+// blockAt returns the block containing t, or nil.
+func blockAt(blocks []Block, t time.Time) *Block {
+	i := sort.Search(len(blocks), func(i int) bool { return blocks[i].End.After(t) })
+	if i < len(blocks) && !t.Before(blocks[i].Start) {
+		return &blocks[i]
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package cost
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectBlocks(t *testing.T) {
+	base := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return base.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+
+	entries := []Entry{
+		{Timestamp: at(5, 10)}, // Out of order: sorted in place
+		{Timestamp: at(0, 42), IsUserTurn: true},
+		{Timestamp: at(3, 0)},
+		{Timestamp: at(4, 30), LimitHit: LimitWindowFiveHour}, // Not activity
+		{Timestamp: at(12, 5)},
+	}
+
+	blocks := DetectBlocks(entries)
+	if len(blocks) != 3 {
+		t.Fatalf("DetectBlocks() returned %d blocks, want 3", len(blocks))
+	}
+
+	tests := []struct {
+		start, last time.Time
+	}{
+		{at(0, 0), at(3, 0)},   // 09:42 floored to 09:00
+		{at(5, 0), at(5, 10)},  // First activity after 14:00
+		{at(12, 0), at(12, 5)}, // After a long gap
+	}
+	for i, tt := range tests {
+		if !blocks[i].Start.Equal(tt.start) {
+			t.Errorf("blocks[%d].Start = %v, want %v", i, blocks[i].Start, tt.start)
+		}
+		if !blocks[i].End.Equal(tt.start.Add(BlockDuration)) {
+			t.Errorf("blocks[%d].End = %v, want %v", i, blocks[i].End, tt.start.Add(BlockDuration))
+		}
+		if !blocks[i].LastActivity.Equal(tt.last) {
+			t.Errorf("blocks[%d].LastActivity = %v, want %v", i, blocks[i].LastActivity, tt.last)
+		}
+	}
+}
+
+func TestCurrentBlockStart(t *testing.T) {
+	base := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	blocks := DetectBlocks([]Entry{
+		{Timestamp: base.Add(20 * time.Minute)},
+		{Timestamp: base.Add(6 * time.Hour)},
+	})
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"inside first block", base.Add(2 * time.Hour), base},
+		{"between blocks", base.Add(5*time.Hour + 30*time.Minute), time.Time{}},
+		{"inside second block", base.Add(7 * time.Hour), base.Add(6 * time.Hour)},
+		{"after last block", base.Add(12 * time.Hour), time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CurrentBlockStart(blocks, tt.now); !got.Equal(tt.want) {
+				t.Errorf("CurrentBlockStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := CurrentBlockStart(nil, base); !got.IsZero() {
+		t.Errorf("CurrentBlockStart(nil) = %v, want zero", got)
+	}
+}
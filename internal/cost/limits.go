@@ -0,0 +1,118 @@
+package cost
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Usage limit windows reported by Claude Code.
+const (
+	LimitWindowFiveHour = "5h"
+	LimitWindowSevenDay = "7d"
+)
+
+// weightUnitPrice is the price per token that one weighted unit represents
+// (default/Sonnet input pricing).
+var weightUnitPrice = defaultPricing.InputPer1M / 1_000_000
+
+// LimitHit records usage at the moment a usage limit was reported.
+type LimitHit struct {
+	At       time.Time
+	Window   string  // LimitWindowFiveHour or LimitWindowSevenDay
+	Messages int     // User turns in the window up to the hit
+	Weighted float64 // Weighted usage in the window up to the hit (see WeightedTokens)
+}
+
+// WeightedTokens converts an entry's usage into Sonnet-input-token equivalents.
+// Output, cache writes and larger models weigh more, in proportion to API
+// pricing, which tracks how subscription limits are consumed far better than
+// counting every user turn equally.
+func WeightedTokens(e Entry) float64 {
+	if e.IsUserTurn || e.LimitHit != "" {
+		return 0
+	}
+	return e.CostUSD / weightUnitPrice
+}
+
+// limitHitWindow returns the limit window if msg is a usage limit error
+// (e.g. "Claude AI usage limit reached|1735689600", "5-hour limit reached"),
+// or "" otherwise.
+func limitHitWindow(msg *jsonlMessage) string {
+	if !msg.IsAPIError && msg.Message.Model != "<synthetic>" {
+		return ""
+	}
+	text := strings.ToLower(messageText(msg.Message.Content))
+	if !strings.Contains(text, "limit reached") && !strings.Contains(text, "rate_limit_error") {
+		return ""
+	}
+	if strings.Contains(text, "weekly") || strings.Contains(text, "7-day") {
+		return LimitWindowSevenDay
+	}
+	return LimitWindowFiveHour
+}
+
+// messageText extracts the text of a message content (a string or a list of blocks).
+func messageText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return ""
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// detectLimitHits finds usage limit hits in time-sorted entries and measures
+// usage in the window leading up to each: the 5-hour block containing the hit
+// or the calendar week. Repeated errors within one window (every retry after
+// the limit is hit) are reported once.
+func detectLimitHits(entries []Entry, blocks []Block) []LimitHit {
+	var hits []LimitHit
+	lastHit := make(map[string]time.Time)
+
+	for i, e := range entries {
+		if e.LimitHit == "" {
+			continue
+		}
+
+		var windowStart time.Time
+		switch e.LimitHit {
+		case LimitWindowSevenDay:
+			windowStart = StartOfWeek(e.Timestamp)
+		default:
+			windowStart = e.Timestamp.Add(-BlockDuration)
+			if b := blockAt(blocks, e.Timestamp); b != nil {
+				windowStart = b.Start
+			}
+		}
+		if last, ok := lastHit[e.LimitHit]; ok && !last.Before(windowStart) {
+			continue
+		}
+		lastHit[e.LimitHit] = e.Timestamp
+
+		hit := LimitHit{At: e.Timestamp, Window: e.LimitHit}
+		for j := i - 1; j >= 0 && !entries[j].Timestamp.Before(windowStart); j-- {
+			if entries[j].IsUserTurn {
+				hit.Messages++
+			}
+			hit.Weighted += WeightedTokens(entries[j])
+		}
+		hits = append(hits, hit)
+	}
+	return hits
+}
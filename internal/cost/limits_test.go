@@ -0,0 +1,88 @@
+package cost
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeightedTokens(t *testing.T) {
+	sonnet := Entry{ModelID: "unknown-model", InputTokens: 1000}
+	sonnet.CostUSD = CalculateCost(sonnet.ModelID, sonnet.InputTokens, 0, 0, 0)
+	if got := WeightedTokens(sonnet); !floatEqual(got, 1000) {
+		t.Errorf("WeightedTokens(default input) = %v, want 1000", got)
+	}
+
+	output := Entry{ModelID: "unknown-model", OutputTokens: 1000}
+	output.CostUSD = CalculateCost(output.ModelID, 0, output.OutputTokens, 0, 0)
+	if got := WeightedTokens(output); !floatEqual(got, 5000) {
+		t.Errorf("WeightedTokens(default output) = %v, want 5000", got)
+	}
+
+	if got := WeightedTokens(Entry{IsUserTurn: true, CostUSD: 1}); got != 0 {
+		t.Errorf("WeightedTokens(user turn) = %v, want 0", got)
+	}
+}
+
+func TestDetectLimitHits(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return base.Add(d) }
+
+	entries := []Entry{
+		// Outside the 5-hour window before the first hit
+		{Timestamp: at(-6 * time.Hour), IsUserTurn: true},
+		{Timestamp: at(-6 * time.Hour), CostUSD: 3.0},
+
+		{Timestamp: at(0), IsUserTurn: true},
+		{Timestamp: at(time.Minute), CostUSD: 0.003}, // 1000 weighted
+		{Timestamp: at(time.Hour), IsUserTurn: true},
+		{Timestamp: at(time.Hour + time.Minute), CostUSD: 0.006}, // 2000 weighted
+		{Timestamp: at(2 * time.Hour), LimitHit: LimitWindowFiveHour},
+		// Retries after the hit are the same hit
+		{Timestamp: at(2*time.Hour + 10*time.Minute), IsUserTurn: true},
+		{Timestamp: at(2*time.Hour + 10*time.Minute), LimitHit: LimitWindowFiveHour},
+		// A later window hits again
+		{Timestamp: at(8 * time.Hour), IsUserTurn: true},
+		{Timestamp: at(9 * time.Hour), LimitHit: LimitWindowFiveHour},
+	}
+
+	hits := detectLimitHits(entries, DetectBlocks(entries))
+	if len(hits) != 2 {
+		t.Fatalf("detectLimitHits() returned %d hits, want 2", len(hits))
+	}
+
+	first := hits[0]
+	if !first.At.Equal(at(2*time.Hour)) || first.Window != LimitWindowFiveHour {
+		t.Errorf("first hit = %+v, want 5h hit at +2h", first)
+	}
+	if first.Messages != 2 {
+		t.Errorf("first hit Messages = %d, want 2", first.Messages)
+	}
+	if !floatEqual(first.Weighted, 3000) {
+		t.Errorf("first hit Weighted = %v, want 3000", first.Weighted)
+	}
+
+	// The block starting at +8h excludes the retry turn at +2h10m
+	if hits[1].Messages != 1 {
+		t.Errorf("second hit Messages = %d, want 1", hits[1].Messages)
+	}
+}
+
+func TestAggregateWeightedAndHits(t *testing.T) {
+	blockStart := time.Now().Add(-time.Hour)
+	entries := []Entry{
+		{Timestamp: blockStart.Add(time.Minute), IsUserTurn: true},
+		{Timestamp: blockStart.Add(2 * time.Minute), CostUSD: 0.003},
+		{Timestamp: blockStart.Add(3 * time.Minute), LimitHit: LimitWindowFiveHour},
+	}
+
+	data := Aggregate(entries, blockStart)
+	if !floatEqual(data.FiveHourBlockWeighted, 1000) {
+		t.Errorf("FiveHourBlockWeighted = %v, want 1000", data.FiveHourBlockWeighted)
+	}
+	if !floatEqual(data.FiveHourBlock, 0.003) {
+		t.Errorf("FiveHourBlock = %v, want 0.003", data.FiveHourBlock)
+	}
+	if len(data.LimitHits) != 1 || data.LimitHits[0].Messages != 1 {
+		t.Errorf("LimitHits = %+v, want one hit with 1 message", data.LimitHits)
+	}
+}
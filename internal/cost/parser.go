@@ -19,7 +19,8 @@ type Entry struct {
 	CacheWrite   int
 	CostUSD      float64
 	SessionID    string
-	IsUserTurn   bool // true if this entry represents a user-initiated turn
+	IsUserTurn   bool   // true if this entry represents a user-initiated turn
+	LimitHit     string // LimitWindowFiveHour or LimitWindowSevenDay if Claude Code reported a usage limit hit
 }
 
 // jsonlMessage represents a message from the Claude transcript JSONL.
@@ -31,11 +32,13 @@ type jsonlMessage struct {
 	DurationMs   int64            `json:"durationMs,omitempty"`
 	SessionID    string           `json:"sessionId,omitempty"`
 	IsMeta       bool             `json:"isMeta,omitempty"`
+	IsAPIError   bool             `json:"isApiErrorMessage,omitempty"`
 }
 
 type assistantMessage struct {
-	Model string `json:"model"`
-	Usage *usage `json:"usage,omitempty"`
+	Model   string          `json:"model"`
+	Usage   *usage          `json:"usage,omitempty"`
+	Content json.RawMessage `json:"content,omitempty"`
 }
 
 type usage struct {
@@ -97,6 +100,17 @@ func parseJSONLLine(line string) (Entry, bool) {
 		return Entry{IsUserTurn: true, SessionID: msg.SessionID, Timestamp: t}, true
 	}
 
+	// Usage limit hit: Claude Code writes a synthetic assistant error message
+	if msg.Type == "assistant" && msg.Message != nil {
+		if window := limitHitWindow(&msg); window != "" {
+			t, err := time.Parse(time.RFC3339, msg.Timestamp)
+			if err != nil {
+				return Entry{}, false
+			}
+			return Entry{LimitHit: window, SessionID: msg.SessionID, Timestamp: t}, true
+		}
+	}
+
 	// Only process assistant messages with usage data
 	if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
 		return Entry{}, false
@@ -194,3 +194,41 @@ func TestParseJSONLEmptyFile(t *testing.T) {
 		t.Errorf("ParseJSONL() returned %d entries for empty file, want 0", len(entries))
 	}
 }
+
+func TestParseJSONLLine_LimitHit(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "5-hour limit",
+			line: `{"type":"assistant","timestamp":"2024-01-15T10:30:00Z","isApiErrorMessage":true,"message":{"model":"<synthetic>","usage":{"input_tokens":0,"output_tokens":0},"content":[{"type":"text","text":"Claude AI usage limit reached|1705320000"}]}}`,
+			want: LimitWindowFiveHour,
+		},
+		{
+			name: "weekly limit",
+			line: `{"type":"assistant","timestamp":"2024-01-15T10:30:00Z","message":{"model":"<synthetic>","content":[{"type":"text","text":"Weekly limit reached ∙ resets Mon 9am"}]}}`,
+			want: LimitWindowSevenDay,
+		},
+		{
+			name: "other api error",
+			line: `{"type":"assistant","timestamp":"2024-01-15T10:30:00Z","isApiErrorMessage":true,"message":{"model":"<synthetic>","content":"API Error: 500 overloaded"}}`,
+			want: "",
+		},
+		{
+			name: "normal reply mentioning limits",
+			line: `{"type":"assistant","timestamp":"2024-01-15T10:30:00Z","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"output_tokens":5},"content":[{"type":"text","text":"The usage limit reached 100%"}]}}`,
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, _ := parseJSONLLine(tt.line)
+			if entry.LimitHit != tt.want {
+				t.Errorf("LimitHit = %q, want %q", entry.LimitHit, tt.want)
+			}
+		})
+	}
+}
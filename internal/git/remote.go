@@ -0,0 +1,83 @@
+package git
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// RemoteURL returns the URL of the remote the current branch tracks,
+// falling back to origin. Returns "" without a remote.
+func RemoteURL(branch string) string {
+	remote := "origin"
+	if branch != "" {
+		if out, err := gitCommand("config", "--get", "branch."+branch+".remote"); err == nil {
+			if name := strings.TrimSpace(string(out)); name != "" && name != "." {
+				remote = name
+			}
+		}
+	}
+	out, err := gitCommand("remote", "get-url", remote)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// scpLikeRegex matches scp-like remotes: git@github.com:owner/repo.git.
+var scpLikeRegex = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// WebURL converts a remote URL (https, ssh or scp-like) to the URL of
+// the repository page. Returns "" for local paths and other schemes.
+func WebURL(remote string) string {
+	scheme, host, path := "https", "", ""
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return ""
+		}
+		switch u.Scheme {
+		case "http", "https":
+			scheme, host = u.Scheme, u.Host // Keeps the port of self-hosted servers
+		case "ssh", "git", "git+ssh", "ssh+git":
+			host = u.Hostname() // The ssh port isn't the web port
+		default:
+			return ""
+		}
+		path = u.Path
+	} else if m := scpLikeRegex.FindStringSubmatch(remote); m != nil {
+		host, path = m[1], m[2]
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || path == "" {
+		return ""
+	}
+	return scheme + "://" + host + "/" + path
+}
+
+// BranchURL returns the web page of a branch: the compare page when the
+// branch has unpushed commits on GitHub, else the branch's file tree.
+// Paths follow GitHub, GitLab and Bitbucket; other hosts get the GitHub
+// layout.
+func BranchURL(webURL, branch string, ahead int) string {
+	if webURL == "" || branch == "" {
+		return webURL
+	}
+	escaped := url.PathEscape(branch)
+	escaped = strings.ReplaceAll(escaped, "%2F", "/")
+
+	host := ""
+	if u, err := url.Parse(webURL); err == nil {
+		host = u.Hostname()
+	}
+	switch {
+	case strings.Contains(host, "gitlab"):
+		return webURL + "/-/tree/" + escaped
+	case strings.Contains(host, "bitbucket"):
+		return webURL + "/branch/" + escaped
+	case ahead > 0:
+		return webURL + "/compare/" + escaped
+	}
+	return webURL + "/tree/" + escaped
+}
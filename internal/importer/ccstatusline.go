@@ -0,0 +1,150 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/render"
+)
+
+// ccstatuslineSettings is the subset of ccstatusline's settings.json
+// (~/.config/ccstatusline/settings.json) that maps to visor.
+type ccstatuslineSettings struct {
+	Lines            [][]ccstatuslineItem `json:"lines"`
+	DefaultSeparator string               `json:"defaultSeparator"`
+	DefaultPadding   *string              `json:"defaultPadding"`
+	Powerline        struct {
+		Enabled bool `json:"enabled"`
+	} `json:"powerline"`
+}
+
+type ccstatuslineItem struct {
+	Type            string `json:"type"`
+	Color           string `json:"color"`
+	BackgroundColor string `json:"backgroundColor"`
+	Bold            bool   `json:"bold"`
+	RawValue        bool   `json:"rawValue"`
+}
+
+// ccstatuslineWidgets maps ccstatusline item types to visor widgets.
+var ccstatuslineWidgets = map[string]string{
+	"model":                     "model",
+	"output-style":              "output_style",
+	"git-branch":                "git",
+	"current-working-dir":       "cwd",
+	"context-percentage":        "context",
+	"context-percentage-usable": "context",
+	"context-length":            "context",
+	"session-clock":             "duration",
+	"session-cost":              "cost",
+	"block-timer":               "block_timer",
+	"version":                   "cc_version",
+	"git-changes":               "code_changes",
+}
+
+// ccstatuslineApproximate explains mappings that show something different.
+var ccstatuslineApproximate = map[string]string{
+	"context-percentage-usable": "visor shows the percentage of the full window",
+	"context-length":            "visor shows the percentage instead of the token count",
+	"git-changes":               "visor shows lines changed in the session, not uncommitted changes",
+}
+
+func translateCCStatusline(data []byte) (*Result, error) {
+	var s ccstatuslineSettings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+
+	r := &Result{Powerline: s.Powerline.Enabled}
+	if sep := strings.TrimSpace(s.DefaultSeparator); sep != "" {
+		pad := " "
+		if s.DefaultPadding != nil {
+			pad = *s.DefaultPadding
+		}
+		r.Separator = pad + sep + pad
+	}
+
+	for i, items := range s.Lines {
+		var left, right []config.WidgetConfig
+		split := false
+		for _, item := range items {
+			where := fmt.Sprintf("%s (line %d)", item.Type, i+1)
+			switch item.Type {
+			case "separator":
+				continue // visor puts the separator between widgets
+			case "flex-separator":
+				if split {
+					r.skip("%s: only one flex separator per line is supported", where)
+				}
+				split = true
+				continue
+			}
+
+			name, ok := ccstatuslineWidgets[item.Type]
+			if !ok {
+				r.skip("%s: no visor widget", where)
+				continue
+			}
+			if why, ok := ccstatuslineApproximate[item.Type]; ok {
+				r.approximate("%s → %s: %s", where, name, why)
+			}
+
+			wc := config.WidgetConfig{Name: name}
+			wc.Style.Bold = item.Bold
+			if item.Color != "" {
+				if c, ok := ccstatuslineColor(item.Color, false); ok {
+					wc.Style.Fg = c
+				} else {
+					r.skip("%s: color %q", where, item.Color)
+				}
+			}
+			if item.BackgroundColor != "" {
+				if c, ok := ccstatuslineColor(item.BackgroundColor, true); ok {
+					wc.Style.Bg = c
+				} else {
+					r.skip("%s: background color %q", where, item.BackgroundColor)
+				}
+			}
+			// ccstatusline labels values ("Model: ...") unless rawValue is set
+			setOption(&wc, "show_label", strconv.FormatBool(!item.RawValue))
+
+			if split {
+				right = append(right, wc)
+			} else {
+				left = append(left, wc)
+			}
+		}
+
+		switch {
+		case len(left) == 0 && len(right) == 0:
+		case split:
+			r.Lines = append(r.Lines, config.Line{Left: left, Right: right})
+		default:
+			r.Lines = append(r.Lines, config.Line{Widgets: left})
+		}
+	}
+	return r, nil
+}
+
+// ccstatuslineColor converts a ccstatusline color: a chalk name ("cyan",
+// "brightBlack", "bgBlue"), "hex:RRGGBB" or "ansi256:N".
+func ccstatuslineColor(color string, bg bool) (string, bool) {
+	if hex, ok := strings.CutPrefix(color, "hex:"); ok {
+		hex = "#" + strings.TrimPrefix(hex, "#")
+		return hex, render.IsHexColor(hex)
+	}
+	if code, ok := strings.CutPrefix(color, "ansi256:"); ok {
+		n, err := strconv.Atoi(code)
+		if err != nil {
+			return "", false
+		}
+		return color256(n, bg)
+	}
+	if rest, ok := strings.CutPrefix(color, "bg"); ok && bg {
+		color = rest
+	}
+	return namedColor(color, bg)
+}
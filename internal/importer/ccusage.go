@@ -0,0 +1,135 @@
+package importer
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/namyoungkim/visor/internal/config"
+)
+
+// ccusageStatusline holds the `ccusage statusline` options, from the
+// commands.statusline section of ccusage.json or the command flags in
+// Claude Code's settings.json.
+type ccusageStatusline struct {
+	VisualBurnRate         string   `json:"visualBurnRate"`
+	CostSource             string   `json:"costSource"`
+	ContextLowThreshold    *float64 `json:"contextLowThreshold"`
+	ContextMediumThreshold *float64 `json:"contextMediumThreshold"`
+}
+
+// ccusageFlags maps command-line flags to ccusageStatusline fields.
+var ccusageFlags = map[string]string{
+	"--visual-burn-rate":         "visualBurnRate",
+	"--cost-source":              "costSource",
+	"--context-low-threshold":    "contextLowThreshold",
+	"--context-medium-threshold": "contextMediumThreshold",
+}
+
+// isCCUsage reports whether a JSON document is a ccusage config or a
+// Claude Code settings file running `ccusage statusline`.
+func isCCUsage(doc map[string]json.RawMessage) bool {
+	if _, ok := doc["commands"]; ok {
+		return true
+	}
+	return ccusageCommand(doc) != ""
+}
+
+// ccusageCommand returns the statusLine command of a Claude Code settings
+// file if it runs ccusage.
+func ccusageCommand(doc map[string]json.RawMessage) string {
+	var statusLine struct {
+		Command string `json:"command"`
+	}
+	if err := json.Unmarshal(doc["statusLine"], &statusLine); err != nil {
+		return ""
+	}
+	if strings.Contains(statusLine.Command, "ccusage") && strings.Contains(statusLine.Command, "statusline") {
+		return statusLine.Command
+	}
+	return ""
+}
+
+func translateCCUsage(data []byte) (*Result, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var opts ccusageStatusline
+	if command := ccusageCommand(doc); command != "" {
+		opts = parseCCUsageFlags(command)
+	} else {
+		var cfg struct {
+			Commands struct {
+				Statusline ccusageStatusline `json:"statusline"`
+			} `json:"commands"`
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, err
+		}
+		opts = cfg.Commands.Statusline
+	}
+
+	// 🤖 Opus | 💰 $0.23 session / $1.23 today / $0.45 block (2h 45m left) | 🔥 $0.12/hr | 🧠 25,000 (12%)
+	r := &Result{}
+	line := []config.WidgetConfig{
+		{Name: "model"},
+		{Name: "cost"},
+		{Name: "daily_cost"},
+		{Name: "block_cost"},
+		{Name: "block_timer"},
+		{Name: "burn_rate"},
+		{Name: "context"},
+	}
+	context := &line[len(line)-1]
+	// ccusage colors context usage below the low threshold green, up to
+	// the medium threshold yellow and red above
+	if t := opts.ContextLowThreshold; t != nil {
+		setOption(context, "warn_threshold", strconv.FormatFloat(*t, 'f', -1, 64))
+	}
+	if t := opts.ContextMediumThreshold; t != nil {
+		setOption(context, "critical_threshold", strconv.FormatFloat(*t, 'f', -1, 64))
+	}
+	r.approximate("burn rate → burn_rate: visor shows cost per minute instead of per hour")
+
+	if v := opts.VisualBurnRate; v != "" && v != "off" {
+		r.skip("visualBurnRate %q: burn_rate has no emoji indicator", v)
+	}
+	if v := opts.CostSource; v != "" && v != "auto" && v != "cc" {
+		r.skip("costSource %q: visor uses Claude Code's session cost", v)
+	}
+
+	r.Lines = []config.Line{{Widgets: line}}
+	return r, nil
+}
+
+// parseCCUsageFlags reads statusline options from a ccusage command line,
+// accepting both "--flag value" and "--flag=value".
+func parseCCUsageFlags(command string) ccusageStatusline {
+	values := make(map[string]string)
+	args := strings.Fields(command)
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		field, ok := ccusageFlags[name]
+		if !ok {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		values[field] = strings.Trim(value, `"'`)
+	}
+
+	opts := ccusageStatusline{VisualBurnRate: values["visualBurnRate"], CostSource: values["costSource"]}
+	for field, dst := range map[string]**float64{
+		"contextLowThreshold":    &opts.ContextLowThreshold,
+		"contextMediumThreshold": &opts.ContextMediumThreshold,
+	} {
+		if f, err := strconv.ParseFloat(values[field], 64); err == nil {
+			*dst = &f
+		}
+	}
+	return opts
+}
@@ -0,0 +1,211 @@
+// Package importer translates statusline configs of other tools
+// (ccstatusline, ccusage statusline, powerlevel10k) into visor lines
+// using the nearest built-in widgets (visor import).
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/render"
+	"github.com/namyoungkim/visor/internal/widgets"
+)
+
+// Format is a supported source config format.
+type Format string
+
+const (
+	FormatCCStatusline Format = "ccstatusline"
+	FormatCCUsage      Format = "ccusage"
+	FormatP10k         Format = "p10k"
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{FormatCCStatusline, FormatCCUsage, FormatP10k}
+
+// Result is a translated config.
+type Result struct {
+	Format    Format
+	Lines     []config.Line
+	Separator string // Widget separator; "" keeps the current one
+	Powerline bool
+
+	// Approximate lists items translated to a widget that shows
+	// something different.
+	Approximate []string
+
+	// Skipped lists items with no visor equivalent.
+	Skipped []string
+}
+
+// Widgets returns the number of translated widgets.
+func (r *Result) Widgets() int {
+	n := 0
+	for _, line := range r.Lines {
+		n += len(line.Widgets) + len(line.Left) + len(line.Right)
+	}
+	return n
+}
+
+// Apply replaces the lines of cfg with the translated ones, along with
+// the separator and powerline setting when the source defines them.
+func (r *Result) Apply(cfg *config.Config) {
+	cfg.Lines = r.Lines
+	if r.Separator != "" {
+		cfg.General.Separator = r.Separator
+	}
+	if r.Powerline {
+		cfg.Theme.Powerline = true
+	}
+}
+
+func (r *Result) approximate(format string, args ...any) {
+	r.Approximate = append(r.Approximate, fmt.Sprintf(format, args...))
+}
+
+func (r *Result) skip(format string, args ...any) {
+	r.Skipped = append(r.Skipped, fmt.Sprintf(format, args...))
+}
+
+// ImportFile reads and translates the config at path. An empty format is
+// detected from the content.
+func ImportFile(path string, format Format) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		if format, err = Detect(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	result, err := Translate(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return result, nil
+}
+
+// Detect guesses the format of a config from its content.
+func Detect(data []byte) (Format, error) {
+	trimmed := bytes.TrimSpace(data)
+	if !bytes.HasPrefix(trimmed, []byte("{")) {
+		if bytes.Contains(data, []byte("POWERLEVEL9K_")) {
+			return FormatP10k, nil
+		}
+		return "", fmt.Errorf("unrecognized config format (want %s)", formatList())
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &doc); err != nil {
+		return "", err
+	}
+	if _, ok := doc["lines"]; ok {
+		return FormatCCStatusline, nil
+	}
+	if isCCUsage(doc) {
+		return FormatCCUsage, nil
+	}
+	return "", fmt.Errorf("unrecognized JSON config (want %s)", formatList())
+}
+
+// Translate converts a config in the given format.
+func Translate(format Format, data []byte) (*Result, error) {
+	var (
+		result *Result
+		err    error
+	)
+	switch format {
+	case FormatCCStatusline:
+		result, err = translateCCStatusline(data)
+	case FormatCCUsage:
+		result, err = translateCCUsage(data)
+	case FormatP10k:
+		result, err = translateP10k(data)
+	default:
+		return nil, fmt.Errorf("unknown format %q (want %s)", format, formatList())
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Format = format
+	if len(result.Lines) == 0 {
+		return nil, fmt.Errorf("no translatable widgets in %s config", format)
+	}
+	return result, nil
+}
+
+func formatList() string {
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+// setOption sets an extra option if the widget declares it.
+func setOption(wc *config.WidgetConfig, key, value string) {
+	w, ok := widgets.Get(wc.Name)
+	if !ok {
+		return
+	}
+	if _, ok := w.Options().Lookup(key); !ok {
+		return
+	}
+	if wc.Extra == nil {
+		wc.Extra = make(map[string]string)
+	}
+	wc.Extra[key] = value
+}
+
+// ansiPalette is the standard palette of the 16 basic colors, used where a
+// visor color name is not available (bright backgrounds).
+var ansiPalette = [16]string{
+	"#000000", "#800000", "#008000", "#808000", "#000080", "#800080", "#008080", "#c0c0c0",
+	"#808080", "#ff0000", "#00ff00", "#ffff00", "#0000ff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// ansiNames are the visor names of the 16 basic colors.
+var ansiNames = [16]string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"bright_black", "bright_red", "bright_green", "bright_yellow",
+	"bright_blue", "bright_magenta", "bright_cyan", "bright_white",
+}
+
+// color256 converts an xterm 256-color index to a visor color.
+func color256(n int, bg bool) (string, bool) {
+	switch {
+	case n < 0 || n > 255:
+		return "", false
+	case n < 16:
+		if name := ansiNames[n]; !bg && render.IsColor(name) || bg && render.IsBgColor(name) {
+			return name, true
+		}
+		return ansiPalette[n], true
+	case n < 232:
+		levels := [6]int{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[n/6%6], levels[n%6]), true
+	}
+	gray := 8 + (n-232)*10
+	return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray), true
+}
+
+// namedColor converts a color name in any case, with or without an
+// underscore after "bright" ("brightBlack"), to a visor color.
+func namedColor(name string, bg bool) (string, bool) {
+	name = strings.ToLower(strings.ReplaceAll(name, "_", ""))
+	for i, visor := range ansiNames {
+		if strings.ReplaceAll(visor, "_", "") == name {
+			return color256(i, bg)
+		}
+	}
+	if name == "gray" || name == "grey" {
+		return color256(8, bg)
+	}
+	return "", false
+}
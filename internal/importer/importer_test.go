@@ -0,0 +1,209 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/namyoungkim/visor/internal/config"
+)
+
+const ccstatuslineSettingsJSON = `{
+  "version": 3,
+  "lines": [
+    [
+      {"id": "1", "type": "model", "color": "cyan", "bold": true},
+      {"id": "2", "type": "separator"},
+      {"id": "3", "type": "context-length", "color": "brightBlack", "rawValue": true},
+      {"id": "4", "type": "flex-separator"},
+      {"id": "5", "type": "session-cost", "color": "hex:FF8800", "backgroundColor": "bgBrightBlue"},
+      {"id": "6", "type": "custom-command", "commandPath": "date"}
+    ],
+    [{"id": "7", "type": "git-branch", "color": "ansi256:31"}],
+    []
+  ],
+  "defaultSeparator": "|",
+  "powerline": {"enabled": true}
+}`
+
+func TestTranslate_CCStatusline(t *testing.T) {
+	r, err := Translate(FormatCCStatusline, []byte(ccstatuslineSettingsJSON))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	if len(r.Lines) != 2 {
+		t.Fatalf("Lines = %+v, want 2 (empty line dropped)", r.Lines)
+	}
+	split := r.Lines[0]
+	if names(split.Left) != "model,context" || names(split.Right) != "cost" {
+		t.Errorf("line 1 = %s | %s, want flex separator split", names(split.Left), names(split.Right))
+	}
+	if s := split.Left[0].Style; s.Fg != "cyan" || !s.Bold {
+		t.Errorf("model style = %+v", s)
+	}
+	if got := split.Left[1]; got.Style.Fg != "bright_black" || got.Extra["show_label"] != "false" {
+		t.Errorf("context = %+v, want bright_black without label", got)
+	}
+	if s := split.Right[0].Style; s.Fg != "#FF8800" || s.Bg != "#0000ff" {
+		t.Errorf("cost style = %+v", s)
+	}
+	if got := r.Lines[1].Widgets[0]; got.Name != "git" || got.Style.Fg != "#0087af" {
+		t.Errorf("line 2 = %+v, want git in #0087af", got)
+	}
+
+	if r.Separator != " | " || !r.Powerline {
+		t.Errorf("Separator = %q, Powerline = %v", r.Separator, r.Powerline)
+	}
+	if len(r.Skipped) != 1 || !strings.HasPrefix(r.Skipped[0], "custom-command") {
+		t.Errorf("Skipped = %v, want custom-command", r.Skipped)
+	}
+	if len(r.Approximate) != 1 || !strings.HasPrefix(r.Approximate[0], "context-length") {
+		t.Errorf("Approximate = %v, want context-length", r.Approximate)
+	}
+}
+
+func TestTranslate_CCUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"config", `{"commands": {"statusline": {"visualBurnRate": "emoji", "contextLowThreshold": 40, "contextMediumThreshold": 70}}}`},
+		{"settings", `{"statusLine": {"type": "command", "command": "bun x ccusage statusline --visual-burn-rate emoji --context-low-threshold=40 --context-medium-threshold 70"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := Detect([]byte(tt.data))
+			if err != nil || format != FormatCCUsage {
+				t.Fatalf("Detect() = %q, %v, want ccusage", format, err)
+			}
+			r, err := Translate(format, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("Translate() error = %v", err)
+			}
+
+			line := r.Lines[0].Widgets
+			if got := names(line); got != "model,cost,daily_cost,block_cost,block_timer,burn_rate,context" {
+				t.Errorf("widgets = %s", got)
+			}
+			extra := line[len(line)-1].Extra
+			if extra["warn_threshold"] != "40" || extra["critical_threshold"] != "70" {
+				t.Errorf("context extra = %v, want thresholds 40/70", extra)
+			}
+			if len(r.Skipped) != 1 || !strings.Contains(r.Skipped[0], "visualBurnRate") {
+				t.Errorf("Skipped = %v, want visualBurnRate", r.Skipped)
+			}
+		})
+	}
+}
+
+const p10kConfig = `
+  typeset -g POWERLEVEL9K_LEFT_PROMPT_ELEMENTS=(
+    # =========================[ Line #1 ]=========================
+    os_icon                 # os identifier
+    dir                     # current directory
+    vcs                     # git status
+    # =========================[ Line #2 ]=========================
+    newline                 # \n
+    prompt_char             # prompt symbol
+  )
+  typeset -g POWERLEVEL9K_RIGHT_PROMPT_ELEMENTS=(
+    status
+    command_execution_time
+  )
+  typeset -g POWERLEVEL9K_DIR_FOREGROUND=31
+  typeset -g POWERLEVEL9K_DIR_BACKGROUND=12
+  typeset -g POWERLEVEL9K_VCS_CLEAN_FOREGROUND=green
+`
+
+func TestTranslate_P10k(t *testing.T) {
+	format, err := Detect([]byte(p10kConfig))
+	if err != nil || format != FormatP10k {
+		t.Fatalf("Detect() = %q, %v, want p10k", format, err)
+	}
+	r, err := Translate(format, []byte(p10kConfig))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	if len(r.Lines) != 1 {
+		t.Fatalf("Lines = %+v, want 1 (line 2 has nothing translatable)", r.Lines)
+	}
+	line := r.Lines[0]
+	if names(line.Left) != "cwd,git" || names(line.Right) != "duration" {
+		t.Errorf("line = %s | %s", names(line.Left), names(line.Right))
+	}
+	if s := line.Left[0].Style; s.Fg != "#0087af" || s.Bg != "#0000ff" {
+		t.Errorf("dir style = %+v", s)
+	}
+	if s := line.Left[1].Style; s.Fg != "green" {
+		t.Errorf("vcs style = %+v, want the clean color", s)
+	}
+	if got := strings.Join(r.Skipped, "; "); !strings.Contains(got, "os_icon") || !strings.Contains(got, "prompt_char") {
+		t.Errorf("Skipped = %v", r.Skipped)
+	}
+}
+
+func TestDetect_Unknown(t *testing.T) {
+	for _, data := range []string{`{"foo": 1}`, "just text"} {
+		if _, err := Detect([]byte(data)); err == nil {
+			t.Errorf("Detect(%q) should fail", data)
+		}
+	}
+}
+
+func TestTranslate_NothingTranslatable(t *testing.T) {
+	data := `{"lines": [[{"type": "custom-text", "customText": "hi"}]]}`
+	if _, err := Translate(FormatCCStatusline, []byte(data)); err == nil {
+		t.Error("Translate() should fail without translatable widgets")
+	}
+}
+
+func TestResult_Apply(t *testing.T) {
+	r, err := Translate(FormatCCStatusline, []byte(ccstatuslineSettingsJSON))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Usage.Enabled = false
+	r.Apply(cfg)
+	if len(cfg.Lines) != 2 || cfg.General.Separator != " | " || !cfg.Theme.Powerline {
+		t.Errorf("Apply() config = %+v", cfg)
+	}
+	if cfg.Usage.Enabled {
+		t.Error("Apply() should keep other settings")
+	}
+}
+
+func TestColor256(t *testing.T) {
+	tests := []struct {
+		n    int
+		bg   bool
+		want string
+	}{
+		{1, false, "red"},
+		{9, false, "bright_red"},
+		{9, true, "#ff0000"},
+		{16, false, "#000000"},
+		{31, false, "#0087af"},
+		{231, false, "#ffffff"},
+		{244, false, "#808080"},
+	}
+	for _, tt := range tests {
+		if got, ok := color256(tt.n, tt.bg); !ok || got != tt.want {
+			t.Errorf("color256(%d, %v) = %q, want %q", tt.n, tt.bg, got, tt.want)
+		}
+	}
+	if _, ok := color256(256, false); ok {
+		t.Error("color256(256) should fail")
+	}
+}
+
+func names(widgets []config.WidgetConfig) string {
+	var out []string
+	for _, w := range widgets {
+		out = append(out, w.Name)
+	}
+	return strings.Join(out, ",")
+}
@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/namyoungkim/visor/internal/config"
+)
+
+// p10kWidgets maps powerlevel10k prompt segments to visor widgets.
+var p10kWidgets = map[string]string{
+	"dir":                    "cwd",
+	"vcs":                    "git",
+	"command_execution_time": "duration",
+}
+
+// p10kApproximate explains mappings that show something different.
+var p10kApproximate = map[string]string{
+	"command_execution_time": "visor shows the session duration",
+}
+
+var (
+	// p10kElementsRegex matches POWERLEVEL9K_{LEFT,RIGHT}_PROMPT_ELEMENTS=( ... ).
+	p10kElementsRegex = regexp.MustCompile(`(?s)POWERLEVEL9K_(LEFT|RIGHT)_PROMPT_ELEMENTS=\((.*?)\)`)
+
+	// p10kColorRegex matches segment colors, e.g. POWERLEVEL9K_DIR_FOREGROUND=31.
+	p10kColorRegex = regexp.MustCompile(`POWERLEVEL9K_([A-Z0-9_]+?)_(FOREGROUND|BACKGROUND)=['"]?(\w+)`)
+
+	p10kCommentRegex = regexp.MustCompile(`#[^\n]*`)
+)
+
+func translateP10k(data []byte) (*Result, error) {
+	src := string(data)
+	elements := make(map[string][][]string) // Side → lines → segments
+	for _, m := range p10kElementsRegex.FindAllStringSubmatch(src, -1) {
+		lines := [][]string{nil}
+		for _, seg := range strings.Fields(p10kCommentRegex.ReplaceAllString(m[2], "")) {
+			if seg == "newline" {
+				lines = append(lines, nil)
+				continue
+			}
+			lines[len(lines)-1] = append(lines[len(lines)-1], seg)
+		}
+		elements[strings.ToLower(m[1])] = lines
+	}
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("no POWERLEVEL9K_LEFT_PROMPT_ELEMENTS or POWERLEVEL9K_RIGHT_PROMPT_ELEMENTS")
+	}
+
+	colors := make(map[string]string) // "DIR_FOREGROUND" → value; later settings win
+	for _, m := range p10kColorRegex.FindAllStringSubmatch(src, -1) {
+		colors[m[1]+"_"+m[2]] = m[3]
+	}
+
+	r := &Result{}
+	left, right := elements["left"], elements["right"]
+	for i := 0; i < max(len(left), len(right)); i++ {
+		var line config.Line
+		if i < len(left) {
+			line.Left = r.p10kSegments(left[i], i, colors)
+		}
+		if i < len(right) {
+			line.Right = r.p10kSegments(right[i], i, colors)
+		}
+		switch {
+		case len(line.Left) == 0 && len(line.Right) == 0:
+		case len(line.Right) == 0:
+			r.Lines = append(r.Lines, config.Line{Widgets: line.Left})
+		default:
+			r.Lines = append(r.Lines, line)
+		}
+	}
+	return r, nil
+}
+
+// p10kSegments translates the segments of one prompt line.
+func (r *Result) p10kSegments(segments []string, line int, colors map[string]string) []config.WidgetConfig {
+	var out []config.WidgetConfig
+	for _, seg := range segments {
+		where := fmt.Sprintf("%s (line %d)", seg, line+1)
+		name, ok := p10kWidgets[seg]
+		if !ok {
+			r.skip("%s: no visor widget", where)
+			continue
+		}
+		if why, ok := p10kApproximate[seg]; ok {
+			r.approximate("%s → %s: %s", where, name, why)
+		}
+
+		wc := config.WidgetConfig{Name: name}
+		if v, ok := p10kSegmentColor(colors, seg, "FOREGROUND"); ok {
+			if c, ok := p10kColor(v, false); ok {
+				wc.Style.Fg = c
+			} else {
+				r.skip("%s: color %q", where, v)
+			}
+		}
+		if v, ok := p10kSegmentColor(colors, seg, "BACKGROUND"); ok {
+			if c, ok := p10kColor(v, true); ok {
+				wc.Style.Bg = c
+			} else {
+				r.skip("%s: background color %q", where, v)
+			}
+		}
+		out = append(out, wc)
+	}
+	return out
+}
+
+// p10kSegmentColor returns the color of a segment, falling back to its
+// clean state for segments colored by state (VCS_CLEAN_FOREGROUND).
+func p10kSegmentColor(colors map[string]string, seg, kind string) (string, bool) {
+	prefix := strings.ToUpper(seg)
+	for _, key := range []string{prefix + "_" + kind, prefix + "_CLEAN_" + kind} {
+		if v, ok := colors[key]; ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// p10kColor converts a p10k color: a 256-color index or a basic name.
+func p10kColor(v string, bg bool) (string, bool) {
+	if n, err := strconv.Atoi(v); err == nil {
+		return color256(n, bg)
+	}
+	return namedColor(v, bg)
+}
@@ -2,16 +2,245 @@ package input
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 )
 
+// Statusline JSON schema versions detected by ParseWithDiagnostics.
+const (
+	SchemaUnknown = "unknown" // Neither model nor session_id: probably not statusline JSON
+	SchemaV1      = "v1"      // Token usage in top-level current_usage
+	SchemaV2      = "v2"      // context_window object (with current_usage inside)
+)
+
+// expectedFields are the fields visor relies on, as dotted paths.
+// Fields under context_window are only expected from SchemaV2.
+var expectedFields = []string{
+	"session_id",
+	"transcript_path",
+	"cwd",
+	"model.id",
+	"model.display_name",
+	"cost.total_cost_usd",
+	"cost.total_duration_ms",
+	"context_window.used_percentage",
+	"context_window.current_usage",
+}
+
+// Diagnostics describes how the stdin JSON matched the expected schema.
+type Diagnostics struct {
+	SchemaVersion string
+	Err           error    // Decode error; the session is empty
+	Missing       []string // Expected fields absent from the input
+	Unknown       []string // Input fields visor does not know (kept in Session.Raw)
+	Coerced       []string // Fields decoded from a variant type, e.g. "42" for a number
+	Invalid       []string // Known fields with an unusable type (left at zero)
+}
+
 // Parse reads JSON from stdin and returns a Session.
 // Returns an empty Session on any error (graceful fallback).
 func Parse(r io.Reader) *Session {
-	var session Session
+	session, _ := ParseWithDiagnostics(r)
+	return session
+}
+
+// ParseWithDiagnostics reads JSON from stdin and returns a Session along
+// with a report of schema drift.
+//
+// Decoding is lenient: numbers given as strings (and vice versa) are
+// converted, and a field with an unusable type is left at zero instead of
+// failing the whole document. Unknown fields are kept in Session.Raw.
+func ParseWithDiagnostics(r io.Reader) (*Session, *Diagnostics) {
+	diag := &Diagnostics{SchemaVersion: SchemaUnknown}
+
+	var raw map[string]any
 	decoder := json.NewDecoder(r)
-	if err := decoder.Decode(&session); err != nil {
-		return &Session{}
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		diag.Err = err
+		return &Session{}, diag
+	}
+
+	session := &Session{Raw: raw}
+	decodeStruct(reflect.ValueOf(session).Elem(), raw, "", diag)
+
+	diag.SchemaVersion = detectSchema(raw)
+	for _, path := range expectedFields {
+		if diag.SchemaVersion != SchemaV2 && strings.HasPrefix(path, "context_window.") {
+			continue
+		}
+		if _, ok := lookupPath(raw, path); !ok {
+			diag.Missing = append(diag.Missing, path)
+		}
+	}
+
+	// Map iteration order is random; keep reports stable
+	sort.Strings(diag.Unknown)
+	sort.Strings(diag.Coerced)
+	sort.Strings(diag.Invalid)
+
+	return session, diag
+}
+
+// detectSchema infers the statusline schema version from its shape.
+func detectSchema(raw map[string]any) string {
+	if _, ok := raw["context_window"].(map[string]any); ok {
+		return SchemaV2
+	}
+	_, hasModel := raw["model"]
+	_, hasSession := raw["session_id"]
+	if hasModel || hasSession {
+		return SchemaV1
+	}
+	return SchemaUnknown
+}
+
+// decodeStruct fills struct v from m by json tag, recording diagnostics
+// under prefix.
+func decodeStruct(v reflect.Value, m map[string]any, prefix string, diag *Diagnostics) {
+	fields := make(map[string]int)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = i
+		}
+	}
+
+	for key, val := range m {
+		path := prefix + key
+		i, ok := fields[key]
+		if !ok {
+			diag.Unknown = append(diag.Unknown, path)
+			continue
+		}
+		decodeValue(v.Field(i), val, path, diag)
+	}
+}
+
+// decodeValue assigns val to field f, converting between compatible types.
+func decodeValue(f reflect.Value, val any, path string, diag *Diagnostics) {
+	if val == nil {
+		return
+	}
+
+	switch f.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(f.Type().Elem())
+		invalid := len(diag.Invalid)
+		decodeValue(elem.Elem(), val, path, diag)
+		if len(diag.Invalid) > invalid && diag.Invalid[len(diag.Invalid)-1] == path {
+			return // Leave nil rather than pointing at an empty value
+		}
+		f.Set(elem)
+		return
+
+	case reflect.Struct:
+		if m, ok := val.(map[string]any); ok {
+			decodeStruct(f, m, path+".", diag)
+			return
+		}
+
+	case reflect.String:
+		switch x := val.(type) {
+		case string:
+			f.SetString(x)
+			return
+		case json.Number:
+			f.SetString(x.String())
+			diag.Coerced = append(diag.Coerced, path)
+			return
+		case bool:
+			f.SetString(strconv.FormatBool(x))
+			diag.Coerced = append(diag.Coerced, path)
+			return
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, exact, ok := toFloat(val); ok {
+			f.SetInt(int64(n))
+			if !exact {
+				diag.Coerced = append(diag.Coerced, path)
+			}
+			return
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if n, exact, ok := toFloat(val); ok {
+			f.SetFloat(n)
+			if !exact {
+				diag.Coerced = append(diag.Coerced, path)
+			}
+			return
+		}
+
+	case reflect.Bool:
+		switch x := val.(type) {
+		case bool:
+			f.SetBool(x)
+			return
+		case string:
+			if b, err := strconv.ParseBool(x); err == nil {
+				f.SetBool(b)
+				diag.Coerced = append(diag.Coerced, path)
+				return
+			}
+		case json.Number:
+			if n, err := x.Float64(); err == nil {
+				f.SetBool(n != 0)
+				diag.Coerced = append(diag.Coerced, path)
+				return
+			}
+		}
+
+	case reflect.Slice:
+		if items, ok := val.([]any); ok {
+			s := reflect.MakeSlice(f.Type(), len(items), len(items))
+			for i, item := range items {
+				decodeValue(s.Index(i), item, fmt.Sprintf("%s[%d]", path, i), diag)
+			}
+			f.Set(s)
+			return
+		}
+	}
+
+	diag.Invalid = append(diag.Invalid, path)
+}
+
+// toFloat converts a JSON number, numeric string or bool to float64.
+// exact is false when the value was not a JSON number.
+func toFloat(val any) (n float64, exact, ok bool) {
+	switch x := val.(type) {
+	case json.Number:
+		f, err := x.Float64()
+		return f, true, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		return f, false, err == nil
+	case bool:
+		if x {
+			return 1, false, true
+		}
+		return 0, false, true
+	}
+	return 0, false, false
+}
+
+// lookupPath returns the raw value at a dotted path.
+func lookupPath(raw map[string]any, path string) (any, bool) {
+	var cur any = raw
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[key]; !ok {
+			return nil, false
+		}
 	}
-	return &session
+	return cur, true
 }
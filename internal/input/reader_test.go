@@ -115,3 +115,187 @@ func TestGetCacheReadTokens_FallbackToOldField(t *testing.T) {
 		t.Errorf("Expected fallback to cache_read_tokens, got %d", cu.GetCacheReadTokens())
 	}
 }
+
+func TestParseWithDiagnostics_SchemaVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"context_window", `{"model": {"id": "x"}, "context_window": {"used_percentage": 10}}`, SchemaV2},
+		{"top-level current_usage", `{"model": {"id": "x"}, "current_usage": {"input_tokens": 1}}`, SchemaV1},
+		{"not statusline JSON", `{"foo": 1}`, SchemaUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, diag := ParseWithDiagnostics(strings.NewReader(tt.json))
+			if diag.SchemaVersion != tt.want {
+				t.Errorf("SchemaVersion = %q, want %q", diag.SchemaVersion, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWithDiagnostics_LenientTypes(t *testing.T) {
+	jsonInput := `{
+		"session_id": 12345,
+		"model": {"display_name": "Opus"},
+		"cost": {"total_cost_usd": "0.05", "total_duration_ms": 1500.0, "total_api_calls": "x"},
+		"context_window": {"used_percentage": "42.5", "current_usage": "bogus"}
+	}`
+
+	session, diag := ParseWithDiagnostics(strings.NewReader(jsonInput))
+	if diag.Err != nil {
+		t.Fatalf("Err = %v", diag.Err)
+	}
+
+	if session.SessionID != "12345" {
+		t.Errorf("SessionID = %q, want 12345", session.SessionID)
+	}
+	if session.Cost.TotalCostUSD != 0.05 {
+		t.Errorf("TotalCostUSD = %v, want 0.05", session.Cost.TotalCostUSD)
+	}
+	if session.Cost.TotalDurationMs != 1500 {
+		t.Errorf("TotalDurationMs = %d, want 1500", session.Cost.TotalDurationMs)
+	}
+	if session.ContextWindow.UsedPercentage != 42.5 {
+		t.Errorf("UsedPercentage = %v, want 42.5", session.ContextWindow.UsedPercentage)
+	}
+	// A bad field does not discard the rest of the document
+	if session.Model.DisplayName != "Opus" {
+		t.Errorf("DisplayName = %q, want Opus", session.Model.DisplayName)
+	}
+	if session.ContextWindow.CurrentUsage != nil {
+		t.Error("CurrentUsage should stay nil for a non-object value")
+	}
+
+	wantCoerced := "context_window.used_percentage,cost.total_cost_usd,session_id"
+	if got := strings.Join(diag.Coerced, ","); got != wantCoerced {
+		t.Errorf("Coerced = %q, want %q", got, wantCoerced)
+	}
+	wantInvalid := "context_window.current_usage,cost.total_api_calls"
+	if got := strings.Join(diag.Invalid, ","); got != wantInvalid {
+		t.Errorf("Invalid = %q, want %q", got, wantInvalid)
+	}
+}
+
+func TestParseWithDiagnostics_MissingAndUnknown(t *testing.T) {
+	jsonInput := `{
+		"session_id": "s1",
+		"model": {"id": "claude-opus-4", "display_name": "Opus", "family": "opus"},
+		"cost": {"total_cost_usd": 0.1},
+		"agent_version": "2.1.3",
+		"theme": {"name": "default"}
+	}`
+
+	session, diag := ParseWithDiagnostics(strings.NewReader(jsonInput))
+
+	wantMissing := "transcript_path,cwd,cost.total_duration_ms"
+	if got := strings.Join(diag.Missing, ","); got != wantMissing {
+		t.Errorf("Missing = %q, want %q", got, wantMissing)
+	}
+	wantUnknown := "agent_version,model.family,theme"
+	if got := strings.Join(diag.Unknown, ","); got != wantUnknown {
+		t.Errorf("Unknown = %q, want %q", got, wantUnknown)
+	}
+
+	// Unknown fields stay reachable through Raw
+	if got := session.FieldString("agent_version"); got != "2.1.3" {
+		t.Errorf("FieldString(agent_version) = %q, want 2.1.3", got)
+	}
+	if got := session.FieldString("theme.name"); got != "default" {
+		t.Errorf("FieldString(theme.name) = %q, want default", got)
+	}
+	if got := session.FieldString("cost.total_cost_usd"); got != "0.1" {
+		t.Errorf("FieldString(cost.total_cost_usd) = %q, want 0.1", got)
+	}
+	if got := session.FieldString("theme"); got != `{"name":"default"}` {
+		t.Errorf("FieldString(theme) = %q", got)
+	}
+	if _, ok := session.Field("nope.deeper"); ok {
+		t.Error("Field(nope.deeper) should be absent")
+	}
+}
+
+func TestParseWithDiagnostics_InvalidJSON(t *testing.T) {
+	session, diag := ParseWithDiagnostics(strings.NewReader("not json"))
+	if diag.Err == nil {
+		t.Error("Err should be set for invalid JSON")
+	}
+	if session == nil || session.Raw != nil {
+		t.Error("Expected empty session without Raw")
+	}
+}
+
+func TestParse_StatuslineFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		json        string
+		version     string
+		outputStyle string
+		currentDir  string
+		projectDir  string
+		exceeds     bool
+		hookEvent   string
+	}{
+		{
+			name: "old payload (v1)",
+			json: `{
+				"session_id": "s1",
+				"cwd": "/home/user/visor",
+				"model": {"id": "claude-opus-4", "display_name": "Opus"},
+				"current_usage": {"input_tokens": 100}
+			}`,
+			currentDir: "/home/user/visor",
+		},
+		{
+			name: "new payload (v2)",
+			json: `{
+				"hook_event_name": "Status",
+				"session_id": "s1",
+				"cwd": "/home/user/visor/internal",
+				"version": "2.1.3",
+				"output_style": {"name": "Explanatory"},
+				"model": {"id": "claude-opus-4", "display_name": "Opus"},
+				"workspace": {"current_dir": "/home/user/visor/internal/input", "project_dir": "/home/user/visor"},
+				"exceeds_200k_tokens": true,
+				"context_window": {"used_percentage": 91}
+			}`,
+			version:     "2.1.3",
+			outputStyle: "Explanatory",
+			currentDir:  "/home/user/visor/internal/input",
+			projectDir:  "/home/user/visor",
+			exceeds:     true,
+			hookEvent:   "Status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, diag := ParseWithDiagnostics(strings.NewReader(tt.json))
+
+			if len(diag.Unknown) != 0 {
+				t.Errorf("Unknown = %v, want none", diag.Unknown)
+			}
+			if session.Version != tt.version {
+				t.Errorf("Version = %q, want %q", session.Version, tt.version)
+			}
+			if session.OutputStyle.Name != tt.outputStyle {
+				t.Errorf("OutputStyle.Name = %q, want %q", session.OutputStyle.Name, tt.outputStyle)
+			}
+			if got := session.GetCurrentDir(); got != tt.currentDir {
+				t.Errorf("GetCurrentDir() = %q, want %q", got, tt.currentDir)
+			}
+			if session.Workspace.ProjectDir != tt.projectDir {
+				t.Errorf("ProjectDir = %q, want %q", session.Workspace.ProjectDir, tt.projectDir)
+			}
+			if session.ExceedsTokens != tt.exceeds {
+				t.Errorf("ExceedsTokens = %v, want %v", session.ExceedsTokens, tt.exceeds)
+			}
+			if session.HookEventName != tt.hookEvent {
+				t.Errorf("HookEventName = %q, want %q", session.HookEventName, tt.hookEvent)
+			}
+		})
+	}
+}
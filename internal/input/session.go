@@ -1,5 +1,10 @@
 package input
 
+import (
+	"encoding/json"
+	"strconv"
+)
+
 // Session represents the parsed stdin JSON from Claude Code.
 type Session struct {
 	SessionID      string        `json:"session_id"`
@@ -10,6 +15,14 @@ type Session struct {
 	CurrentUsage   *CurrentUsage `json:"current_usage"`
 	TranscriptPath string        `json:"transcript_path"`
 	CWD            string        `json:"cwd"`
+	Version        string        `json:"version"` // Claude Code version
+	OutputStyle    OutputStyle   `json:"output_style"`
+	ExceedsTokens  bool          `json:"exceeds_200k_tokens"` // Context is past the 200k long-context boundary
+	HookEventName  string        `json:"hook_event_name"`     // "Status" for statusline invocations
+
+	// Raw is the decoded stdin JSON, including fields visor does not model.
+	// Numbers are json.Number.
+	Raw map[string]any `json:"-"`
 }
 
 // Model contains model information.
@@ -40,11 +53,18 @@ type ContextWindow struct {
 	CurrentUsage      *CurrentUsage `json:"current_usage"`
 }
 
-// Workspace contains information about code changes.
+// OutputStyle is the active Claude Code output style.
+type OutputStyle struct {
+	Name string `json:"name"`
+}
+
+// Workspace contains directory information and code changes.
 type Workspace struct {
-	LinesAdded   int `json:"lines_added"`
-	LinesRemoved int `json:"lines_removed"`
-	FilesChanged int `json:"files_changed"`
+	CurrentDir   string `json:"current_dir"` // Current working directory
+	ProjectDir   string `json:"project_dir"` // Directory Claude Code was started in
+	LinesAdded   int    `json:"lines_added"`
+	LinesRemoved int    `json:"lines_removed"`
+	FilesChanged int    `json:"files_changed"`
 }
 
 // CurrentUsage contains token usage for the current request.
@@ -72,6 +92,15 @@ func (s *Session) GetCurrentUsage() *CurrentUsage {
 	return s.CurrentUsage
 }
 
+// GetCurrentDir returns the working directory, preferring workspace.current_dir
+// over the top-level cwd.
+func (s *Session) GetCurrentDir() string {
+	if s.Workspace.CurrentDir != "" {
+		return s.Workspace.CurrentDir
+	}
+	return s.CWD
+}
+
 // GetTotalOutputTokens returns total output tokens, checking ContextWindow first,
 // then falling back to Cost for backward compatibility.
 func (s *Session) GetTotalOutputTokens() int {
@@ -80,3 +109,33 @@ func (s *Session) GetTotalOutputTokens() int {
 	}
 	return s.Cost.TotalOutputTokens
 }
+
+// Field returns the raw stdin value at a dotted path (e.g. "workspace.project_dir").
+func (s *Session) Field(path string) (any, bool) {
+	if s.Raw == nil {
+		return nil, false
+	}
+	return lookupPath(s.Raw, path)
+}
+
+// FieldString returns the raw stdin value at a dotted path formatted as text,
+// or "" if absent. Objects and arrays are formatted as JSON.
+func (s *Session) FieldString(path string) string {
+	v, ok := s.Field(path)
+	if !ok || v == nil {
+		return ""
+	}
+	switch x := v.(type) {
+	case string:
+		return x
+	case json.Number:
+		return x.String()
+	case bool:
+		return strconv.FormatBool(x)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
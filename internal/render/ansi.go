@@ -1,12 +1,16 @@
 package render
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ANSI color codes
 const (
 	Reset     = "\033[0m"
 	Bold      = "\033[1m"
 	Dim       = "\033[2m"
+	Italic    = "\033[3m"
 	Underline = "\033[4m"
 )
 
@@ -84,32 +88,77 @@ var BgColorMap = map[string]string{
 
 // Colorize applies ANSI color to text.
 func Colorize(text, fg string) string {
-	if code, ok := ColorMap[fg]; ok {
-		return code + text + Reset
-	}
-	return text
+	return TextStyle{Fg: fg}.Apply(text)
 }
 
 // Style applies multiple style options to text.
 func Style(text string, fg, bg string, bold bool) string {
-	var codes string
+	return TextStyle{Fg: fg, Bg: bg, Bold: bold}.Apply(text)
+}
 
-	if bold {
-		codes += Bold
-	}
-	if code, ok := BgColorMap[bg]; ok {
-		codes += code
-	}
-	if code, ok := ColorMap[fg]; ok {
-		codes += code
+// TextStyle is a set of colors and attributes. Colors are ColorMap names
+// (BgColorMap for Bg), hex colors or 256-color palette indices ("208").
+type TextStyle struct {
+	Fg, Bg                       string
+	Bold, Dim, Italic, Underline bool
+}
+
+// Codes returns the escape codes that turn the style on in the active
+// color mode. Unknown colors are ignored; with ColorNone there are none.
+func (s TextStyle) Codes() string {
+	if colorMode == ColorNone {
+		return ""
 	}
+	var codes string
+	for _, attr := range []struct {
+		on   bool
+		code string
+	}{{s.Bold, Bold}, {s.Dim, Dim}, {s.Italic, Italic}, {s.Underline, Underline}} {
+		if attr.on {
+			codes += attr.code
+		}
+	}
+	return codes + colorCode(s.Bg, true) + colorCode(s.Fg, false)
+}
 
+// Apply styles text.
+func (s TextStyle) Apply(text string) string {
+	codes := s.Codes()
 	if codes == "" {
 		return text
 	}
 	return codes + text + Reset
 }
 
+// Restyle applies the style to already-rendered text. A foreground color
+// replaces the text's own colors and attributes; otherwise they are kept
+// and the style is restored after each reset so it spans the whole text.
+func (s TextStyle) Restyle(text string) string {
+	codes := s.Codes()
+	if codes == "" {
+		return text
+	}
+	if s.Fg != "" {
+		text = StripSGR(text)
+	}
+	text = strings.TrimSuffix(text, Reset)
+	return codes + strings.ReplaceAll(text, Reset, Reset+codes) + Reset
+}
+
+// StripSGR removes color and attribute codes from s, keeping other escape
+// sequences such as hyperlinks.
+func StripSGR(s string) string {
+	var sb strings.Builder
+	for s != "" {
+		segment, escape, rest := nextText(s)
+		if !escape || !strings.HasPrefix(segment, "\033[") || !strings.HasSuffix(segment, "m") {
+			sb.WriteString(segment)
+		}
+		s = rest
+	}
+	return sb.String()
+}
+
 // RGB returns an ANSI 256-color or true color code.
 func RGB(r, g, b int) string {
 	return fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b)
@@ -120,88 +169,72 @@ func RGBBg(r, g, b int) string {
 	return fmt.Sprintf("\033[48;2;%d;%d;%dm", r, g, b)
 }
 
-// HexToRGB converts a hex color string (#RRGGBB) to RGB values.
+// HexToRGB converts a hex color string (#RGB, #RRGGBB or #RRGGBBAA) to RGB
+// values. The alpha channel is ignored. Invalid input yields black.
 func HexToRGB(hex string) (r, g, b int) {
-	if len(hex) == 0 {
+	if !IsHexColor(hex) {
 		return 0, 0, 0
 	}
-	if hex[0] == '#' {
-		hex = hex[1:]
-	}
-	if len(hex) != 6 {
-		return 0, 0, 0
+	hex = hex[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
 	}
-	fmt.Sscanf(hex, "%02x%02x%02x", &r, &g, &b)
+	fmt.Sscanf(hex[:6], "%02x%02x%02x", &r, &g, &b)
 	return r, g, b
 }
 
-// ColorizeHex applies a hex color to text.
-func ColorizeHex(text, hex string) string {
-	if hex == "" {
-		return text
-	}
-	r, g, b := HexToRGB(hex)
-	return RGB(r, g, b) + text + Reset
-}
-
-// StyleHex applies hex colors and styling to text.
-func StyleHex(text, fgHex, bgHex string, bold bool) string {
-	var codes string
-
-	if bold {
-		codes += Bold
+// IsHexColor reports whether s is a hex color: #RGB, #RRGGBB or #RRGGBBAA.
+func IsHexColor(s string) bool {
+	if len(s) == 0 || s[0] != '#' {
+		return false
 	}
-	if bgHex != "" {
-		r, g, b := HexToRGB(bgHex)
-		codes += RGBBg(r, g, b)
+	switch len(s) - 1 {
+	case 3, 6, 8:
+	default:
+		return false
 	}
-	if fgHex != "" {
-		r, g, b := HexToRGB(fgHex)
-		codes += RGB(r, g, b)
+	for _, c := range s[1:] {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
 	}
+	return true
+}
 
-	if codes == "" {
-		return text
-	}
-	return codes + text + Reset
+// IsColor reports whether color can be rendered as a foreground color
+// (a ColorMap name, a hex color or a 256-color palette index).
+func IsColor(color string) bool {
+	_, ok := ColorMap[color]
+	_, isIndex := colorIndex(color)
+	return ok || isIndex || IsHexColor(color)
 }
 
-// ResolveColor returns the ANSI code for a color (name or hex).
-func ResolveColor(color string) string {
-	if color == "" {
-		return ""
-	}
+// IsBgColor reports whether color can be rendered as a background color
+// (a BgColorMap name, a hex color or a 256-color palette index).
+func IsBgColor(color string) bool {
+	_, ok := BgColorMap[color]
+	_, isIndex := colorIndex(color)
+	return ok || isIndex || IsHexColor(color)
+}
 
-	// Check if it's a hex color
-	if color[0] == '#' {
-		r, g, b := HexToRGB(color)
-		return RGB(r, g, b)
-	}
+// ColorizeHex applies a hex color to text.
+func ColorizeHex(text, hex string) string {
+	return TextStyle{Fg: hex}.Apply(text)
+}
 
-	// Check named colors
-	if code, ok := ColorMap[color]; ok {
-		return code
-	}
+// StyleHex applies hex colors and styling to text.
+func StyleHex(text, fgHex, bgHex string, bold bool) string {
+	return TextStyle{Fg: fgHex, Bg: bgHex, Bold: bold}.Apply(text)
+}
 
-	return ""
+// ResolveColor returns the ANSI code for a color (name, hex or palette
+// index) in the active color mode.
+func ResolveColor(color string) string {
+	return colorCode(color, false)
 }
 
-// ResolveBgColor returns the ANSI background code for a color (name or hex).
+// ResolveBgColor returns the ANSI background code for a color (name, hex
+// or palette index) in the active color mode.
 func ResolveBgColor(color string) string {
-	if color == "" {
-		return ""
-	}
-
-	// Check if it's a hex color
-	if color[0] == '#' {
-		r, g, b := HexToRGB(color)
-		return RGBBg(r, g, b)
-	}
-
-	// Check named colors
-	if code, ok := BgColorMap[color]; ok {
-		return code
-	}
-
-	return ""
+	return colorCode(color, true)
 }
@@ -147,3 +147,50 @@ func TestBgColorMap_AllColors(t *testing.T) {
 		}
 	}
 }
+
+func TestHexToRGB(t *testing.T) {
+	tests := []struct {
+		hex     string
+		r, g, b int
+	}{
+		{"#ff8000", 255, 128, 0},
+		{"#f80", 255, 136, 0},
+		{"#ff800080", 255, 128, 0},
+		{"#zzzzzz", 0, 0, 0},
+		{"ff8000", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		r, g, b := HexToRGB(tt.hex)
+		if r != tt.r || g != tt.g || b != tt.b {
+			t.Errorf("HexToRGB(%q) = %d,%d,%d, want %d,%d,%d", tt.hex, r, g, b, tt.r, tt.g, tt.b)
+		}
+	}
+}
+
+func TestIsColor(t *testing.T) {
+	tests := []struct {
+		color  string
+		fg, bg bool
+	}{
+		{"red", true, true},
+		{"bright_red", true, false},
+		{"gray", true, false},
+		{"brightred", false, false},
+		{"Red", false, false},
+		{"#abc", true, true},
+		{"#abcd", false, false},
+		{"208", true, true},
+		{"0", true, true},
+		{"256", false, false},
+		{"-1", false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		if got := IsColor(tt.color); got != tt.fg {
+			t.Errorf("IsColor(%q) = %v, want %v", tt.color, got, tt.fg)
+		}
+		if got := IsBgColor(tt.color); got != tt.bg {
+			t.Errorf("IsBgColor(%q) = %v, want %v", tt.color, got, tt.bg)
+		}
+	}
+}
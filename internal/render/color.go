@@ -0,0 +1,255 @@
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ColorMode is the color capability of the terminal.
+type ColorMode int
+
+const (
+	ColorNone ColorMode = iota // No escape codes at all
+	Color16                    // Basic and bright ANSI colors
+	Color256                   // xterm 256-color palette
+	ColorTrue                  // 24-bit color
+)
+
+// ColorModeAuto is the config value that detects the mode.
+const ColorModeAuto = "auto"
+
+// ColorModeNames lists the accepted color mode values.
+var ColorModeNames = []string{ColorModeAuto, "truecolor", "256", "16", "none"}
+
+func (m ColorMode) String() string {
+	switch m {
+	case ColorNone:
+		return "none"
+	case Color16:
+		return "16"
+	case Color256:
+		return "256"
+	}
+	return "truecolor"
+}
+
+// ParseColorMode parses "truecolor" (or "24bit"), "256", "16" or "none".
+func ParseColorMode(s string) (ColorMode, bool) {
+	switch strings.ToLower(s) {
+	case "truecolor", "24bit":
+		return ColorTrue, true
+	case "256":
+		return Color256, true
+	case "16":
+		return Color16, true
+	case "none":
+		return ColorNone, true
+	}
+	return 0, false
+}
+
+// colorMode is the active mode. Truecolor unless SetColorMode says
+// otherwise, so hex colors render exactly by default.
+var colorMode = ColorTrue
+
+// SetColorMode sets the mode colors are rendered in.
+func SetColorMode(m ColorMode) {
+	colorMode = m
+}
+
+// ActiveColorMode returns the mode colors are rendered in.
+func ActiveColorMode() ColorMode {
+	return colorMode
+}
+
+// ResolveColorMode returns the color mode from the first source that
+// sets it: the --color flag, [general] color, then DetectColorMode.
+// Empty, "auto" and invalid values fall through. The second result names
+// the source for --debug.
+func ResolveColorMode(flagMode, configMode string, lookupEnv func(string) (string, bool)) (ColorMode, string) {
+	if m, ok := ParseColorMode(flagMode); ok {
+		return m, "--color"
+	}
+	if m, ok := ParseColorMode(configMode); ok {
+		return m, "config"
+	}
+	return DetectColorMode(lookupEnv)
+}
+
+// DetectColorMode guesses the color mode from the environment: NO_COLOR
+// and FORCE_COLOR (https://no-color.org, https://force-color.org), then
+// COLORTERM and TERM. Without TERM (no terminal info at all) it keeps
+// truecolor.
+func DetectColorMode(lookupEnv func(string) (string, bool)) (ColorMode, string) {
+	if v, ok := lookupEnv("NO_COLOR"); ok && v != "" {
+		return ColorNone, "NO_COLOR"
+	}
+
+	terminal, source := terminalColorMode(lookupEnv)
+	if v, ok := lookupEnv("FORCE_COLOR"); ok {
+		// Levels as in chalk/supports-color: 0 off, 1 basic, 2 256, 3 truecolor
+		switch strings.ToLower(v) {
+		case "0", "false":
+			return ColorNone, "FORCE_COLOR"
+		case "2":
+			return Color256, "FORCE_COLOR"
+		case "3":
+			return ColorTrue, "FORCE_COLOR"
+		}
+		if terminal == ColorNone {
+			return Color16, "FORCE_COLOR"
+		}
+	}
+	return terminal, source
+}
+
+// terminalColorMode reads the capability the terminal advertises.
+func terminalColorMode(lookupEnv func(string) (string, bool)) (ColorMode, string) {
+	if v, _ := lookupEnv("COLORTERM"); v == "truecolor" || v == "24bit" {
+		return ColorTrue, "COLORTERM"
+	}
+	term, _ := lookupEnv("TERM")
+	switch {
+	case term == "":
+		return ColorTrue, "default"
+	case term == "dumb":
+		return ColorNone, "TERM"
+	case strings.Contains(term, "truecolor") || strings.Contains(term, "24bit") || strings.Contains(term, "direct"):
+		return ColorTrue, "TERM"
+	case strings.Contains(term, "256"):
+		return Color256, "TERM"
+	}
+	return Color16, "TERM"
+}
+
+// xtermPalette holds the xterm default RGB values of the 16 basic colors.
+var xtermPalette = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the channel values of the 6x6x6 color cube (16-231).
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// PaletteRGB returns the RGB value of an xterm 256-color palette index,
+// using the xterm defaults for the 16 basic colors.
+func PaletteRGB(n int) (r, g, b int) {
+	switch {
+	case n < 0 || n > 255:
+		return 0, 0, 0
+	case n < 16:
+		c := xtermPalette[n]
+		return c[0], c[1], c[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]
+	}
+	gray := 8 + (n-232)*10
+	return gray, gray, gray
+}
+
+// Nearest256 returns the palette index (16-255) closest to an RGB value.
+// The 16 basic colors are skipped because terminal themes redefine them.
+func Nearest256(r, g, b int) int {
+	return nearest(r, g, b, 16, 256)
+}
+
+// Nearest16 returns the basic color index (0-15) closest to an RGB value.
+func Nearest16(r, g, b int) int {
+	return nearest(r, g, b, 0, 16)
+}
+
+func nearest(r, g, b, from, to int) int {
+	best, bestDist := from, -1
+	for n := from; n < to; n++ {
+		pr, pg, pb := PaletteRGB(n)
+		dr, dg, db := r-pr, g-pg, b-pb
+		// Weighted by the eye's sensitivity to each channel
+		dist := 2*dr*dr + 4*dg*dg + 3*db*db
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = n, dist
+		}
+	}
+	return best
+}
+
+// colorIndex parses a 256-color palette index ("0" to "255").
+func colorIndex(color string) (int, bool) {
+	if color == "" || len(color) > 3 || color[0] == '+' || color[0] == '-' {
+		return 0, false
+	}
+	n, err := strconv.Atoi(color)
+	return n, err == nil && n <= 255
+}
+
+// colorCode returns the escape code for a color name, hex color or
+// palette index in the active mode, or "" if the color is unknown.
+func colorCode(color string, bg bool) string {
+	if color == "" || colorMode == ColorNone {
+		return ""
+	}
+	if color[0] == '#' {
+		r, g, b := HexToRGB(color)
+		return rgbCode(r, g, b, bg)
+	}
+	if n, ok := colorIndex(color); ok {
+		return indexCode(n, bg)
+	}
+	if bg {
+		return BgColorMap[color]
+	}
+	return ColorMap[color]
+}
+
+// rgbCode downsamples an RGB color to the active mode.
+func rgbCode(r, g, b int, bg bool) string {
+	switch colorMode {
+	case ColorTrue:
+		if bg {
+			return RGBBg(r, g, b)
+		}
+		return RGB(r, g, b)
+	case Color256:
+		return indexCode(Nearest256(r, g, b), bg)
+	}
+	return basicCode(Nearest16(r, g, b), bg)
+}
+
+// indexCode returns the code of a palette index, mapped to the nearest
+// basic color in 16-color mode.
+func indexCode(n int, bg bool) string {
+	if colorMode == Color16 {
+		if n >= 16 {
+			n = Nearest16(PaletteRGB(n))
+		}
+		return basicCode(n, bg)
+	}
+	if bg {
+		return fmt.Sprintf("\033[48;5;%dm", n)
+	}
+	return fmt.Sprintf("\033[38;5;%dm", n)
+}
+
+// basicCode returns the code of one of the 16 basic colors.
+func basicCode(n int, bg bool) string {
+	code := 30
+	if bg {
+		code = 40
+	}
+	if n >= 8 {
+		code += 60 // Bright variants: 90-97, 100-107
+		n -= 8
+	}
+	return fmt.Sprintf("\033[%dm", code+n)
+}
+
+// resetCode returns Reset, or "" when colors are off.
+func resetCode() string {
+	if colorMode == ColorNone {
+		return ""
+	}
+	return Reset
+}
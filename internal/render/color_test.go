@@ -0,0 +1,185 @@
+package render
+
+import "testing"
+
+func useColorMode(t *testing.T, m ColorMode) {
+	t.Helper()
+	orig := colorMode
+	SetColorMode(m)
+	t.Cleanup(func() { SetColorMode(orig) })
+}
+
+func envLookup(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestDetectColorMode(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		mode   ColorMode
+		source string
+	}{
+		{"no terminal info", nil, ColorTrue, "default"},
+		{"NO_COLOR", map[string]string{"NO_COLOR": "1", "COLORTERM": "truecolor"}, ColorNone, "NO_COLOR"},
+		{"empty NO_COLOR is ignored", map[string]string{"NO_COLOR": "", "TERM": "xterm-256color"}, Color256, "TERM"},
+		{"COLORTERM", map[string]string{"COLORTERM": "truecolor", "TERM": "xterm"}, ColorTrue, "COLORTERM"},
+		{"TERM 256", map[string]string{"TERM": "screen-256color"}, Color256, "TERM"},
+		{"TERM direct", map[string]string{"TERM": "xterm-direct"}, ColorTrue, "TERM"},
+		{"TERM basic", map[string]string{"TERM": "xterm"}, Color16, "TERM"},
+		{"dumb", map[string]string{"TERM": "dumb"}, ColorNone, "TERM"},
+		{"FORCE_COLOR on dumb", map[string]string{"TERM": "dumb", "FORCE_COLOR": "1"}, Color16, "FORCE_COLOR"},
+		{"FORCE_COLOR keeps better terminal", map[string]string{"TERM": "xterm-256color", "FORCE_COLOR": ""}, Color256, "TERM"},
+		{"FORCE_COLOR level", map[string]string{"TERM": "xterm", "FORCE_COLOR": "3"}, ColorTrue, "FORCE_COLOR"},
+		{"FORCE_COLOR off", map[string]string{"COLORTERM": "truecolor", "FORCE_COLOR": "0"}, ColorNone, "FORCE_COLOR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, source := DetectColorMode(envLookup(tt.env))
+			if mode != tt.mode || source != tt.source {
+				t.Errorf("DetectColorMode() = %s from %s, want %s from %s", mode, source, tt.mode, tt.source)
+			}
+		})
+	}
+}
+
+func TestResolveColorMode(t *testing.T) {
+	env := envLookup(map[string]string{"NO_COLOR": "1"})
+	tests := []struct {
+		flagMode, configMode string
+		mode                 ColorMode
+		source               string
+	}{
+		{"256", "16", Color256, "--color"},
+		{"", "16", Color16, "config"},
+		{"auto", "24bit", ColorTrue, "config"},
+		{"", "auto", ColorNone, "NO_COLOR"},
+		{"", "bogus", ColorNone, "NO_COLOR"},
+	}
+	for _, tt := range tests {
+		mode, source := ResolveColorMode(tt.flagMode, tt.configMode, env)
+		if mode != tt.mode || source != tt.source {
+			t.Errorf("ResolveColorMode(%q, %q) = %s from %s, want %s from %s",
+				tt.flagMode, tt.configMode, mode, source, tt.mode, tt.source)
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	tests := []struct {
+		hex     string
+		want256 int
+		want16  int
+	}{
+		{"#000000", 16, 0},
+		{"#ffffff", 231, 15},
+		{"#ff0000", 196, 9},
+		{"#ff8700", 208, 3},
+		{"#808080", 244, 8},
+		{"#3c3836", 237, 0},
+	}
+	for _, tt := range tests {
+		r, g, b := HexToRGB(tt.hex)
+		if got := Nearest256(r, g, b); got != tt.want256 {
+			t.Errorf("Nearest256(%s) = %d, want %d", tt.hex, got, tt.want256)
+		}
+		if got := Nearest16(r, g, b); got != tt.want16 {
+			t.Errorf("Nearest16(%s) = %d, want %d", tt.hex, got, tt.want16)
+		}
+	}
+}
+
+func TestPaletteRGB(t *testing.T) {
+	tests := []struct {
+		n       int
+		r, g, b int
+	}{
+		{1, 205, 0, 0},
+		{16, 0, 0, 0},
+		{208, 255, 135, 0},
+		{231, 255, 255, 255},
+		{232, 8, 8, 8},
+		{255, 238, 238, 238},
+	}
+	for _, tt := range tests {
+		if r, g, b := PaletteRGB(tt.n); r != tt.r || g != tt.g || b != tt.b {
+			t.Errorf("PaletteRGB(%d) = %d,%d,%d, want %d,%d,%d", tt.n, r, g, b, tt.r, tt.g, tt.b)
+		}
+	}
+}
+
+func TestResolveColor_Modes(t *testing.T) {
+	tests := []struct {
+		mode   ColorMode
+		color  string
+		fg, bg string
+	}{
+		{ColorTrue, "#ff8700", "\033[38;2;255;135;0m", "\033[48;2;255;135;0m"},
+		{Color256, "#ff8700", "\033[38;5;208m", "\033[48;5;208m"},
+		{Color16, "#ff8700", FgYellow, BgYellow},
+		{ColorNone, "#ff8700", "", ""},
+		{ColorTrue, "208", "\033[38;5;208m", "\033[48;5;208m"},
+		{Color16, "208", FgYellow, BgYellow},
+		{Color16, "#5c5cff", FgBrightBlue, "\033[104m"},
+		{Color16, "4", FgBlue, BgBlue},
+		{Color16, "red", FgRed, BgRed},
+		{ColorNone, "red", "", ""},
+	}
+	for _, tt := range tests {
+		useColorMode(t, tt.mode)
+		if got := ResolveColor(tt.color); got != tt.fg {
+			t.Errorf("%s: ResolveColor(%q) = %q, want %q", tt.mode, tt.color, got, tt.fg)
+		}
+		if got := ResolveBgColor(tt.color); got != tt.bg {
+			t.Errorf("%s: ResolveBgColor(%q) = %q, want %q", tt.mode, tt.color, got, tt.bg)
+		}
+	}
+}
+
+func TestTextStyle(t *testing.T) {
+	style := TextStyle{Fg: "red", Bg: "#000000", Bold: true, Dim: true, Italic: true, Underline: true}
+
+	useColorMode(t, Color256)
+	want := Bold + Dim + Italic + Underline + "\033[48;5;16m" + FgRed + "x" + Reset
+	if got := style.Apply("x"); got != want {
+		t.Errorf("Apply() = %q, want %q", got, want)
+	}
+
+	useColorMode(t, ColorNone)
+	if got := style.Apply("x"); got != "x" {
+		t.Errorf("Apply() with ColorNone = %q, want plain text", got)
+	}
+	if got := Colorize("x", "red"); got != "x" {
+		t.Errorf("Colorize() with ColorNone = %q, want plain text", got)
+	}
+}
+
+func TestTextStyle_Restyle(t *testing.T) {
+	rendered := FgGreen + "ok" + Reset + " " + FgRed + "bad" + Reset
+
+	got := TextStyle{Bold: true}.Restyle(rendered)
+	want := Bold + FgGreen + "ok" + Reset + Bold + " " + FgRed + "bad" + Reset
+	if got != want {
+		t.Errorf("Restyle(bold) = %q, want %q", got, want)
+	}
+
+	got = TextStyle{Fg: "cyan"}.Restyle(rendered)
+	if want := FgCyan + "ok bad" + Reset; got != want {
+		t.Errorf("Restyle(fg) = %q, want %q", got, want)
+	}
+
+	if got := (TextStyle{}).Restyle(rendered); got != rendered {
+		t.Errorf("Restyle(empty) = %q, want unchanged", got)
+	}
+}
+
+func TestStripSGR(t *testing.T) {
+	input := "\033[1;31mred\033[0m \033]8;;https://example.com\033\\link\033]8;;\033\\"
+	want := "red \033]8;;https://example.com\033\\link\033]8;;\033\\"
+	if got := StripSGR(input); got != want {
+		t.Errorf("StripSGR() = %q, want %q", got, want)
+	}
+}
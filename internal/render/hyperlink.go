@@ -0,0 +1,67 @@
+package render
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OSC 8 hyperlink escapes: "\033]8;;URL\033\\" + text + "\033]8;;\033\\".
+// Width math skips them (see escapeLen), and Truncate keeps the closing
+// escape when it cuts the link text.
+const (
+	hyperlinkStart = "\033]8;;"
+	hyperlinkEnd   = "\033\\"
+)
+
+// hyperlinks enables OSC 8 links. Off by default: terminals without
+// support may print the escapes.
+var hyperlinks bool
+
+// SetHyperlinks turns OSC 8 hyperlinks on or off.
+func SetHyperlinks(on bool) {
+	hyperlinks = on
+}
+
+// HyperlinksEnabled reports whether Hyperlink emits links, so widgets can
+// skip work (such as git commands) needed only to build a URL.
+func HyperlinksEnabled() bool {
+	return hyperlinks
+}
+
+// Hyperlink makes text a clickable link to target when hyperlinks are
+// enabled. Returns text unchanged otherwise or when target is empty.
+func Hyperlink(text, target string) string {
+	if !hyperlinks || text == "" {
+		return text
+	}
+	target = sanitizeURL(target)
+	if target == "" {
+		return text
+	}
+	return hyperlinkStart + target + hyperlinkEnd + text + hyperlinkStart + hyperlinkEnd
+}
+
+// FileURL returns the file:// URL of a local path, with the hostname so
+// terminals can tell local files from remote ones. Returns "" for
+// relative paths.
+func FileURL(path string) string {
+	if !filepath.IsAbs(path) {
+		return ""
+	}
+	host, _ := os.Hostname()
+	u := url.URL{Scheme: "file", Host: host, Path: filepath.ToSlash(path)}
+	return u.String()
+}
+
+// sanitizeURL drops control characters, which could end the escape
+// sequence early.
+func sanitizeURL(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,70 @@
+package render
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func useHyperlinks(t *testing.T) {
+	t.Helper()
+	SetHyperlinks(true)
+	t.Cleanup(func() { SetHyperlinks(false) })
+}
+
+func TestHyperlink_Disabled(t *testing.T) {
+	if got := Hyperlink("main", "https://example.com"); got != "main" {
+		t.Errorf("Hyperlink() = %q, want plain text when disabled", got)
+	}
+}
+
+func TestHyperlink(t *testing.T) {
+	useHyperlinks(t)
+
+	got := Hyperlink("main", "https://example.com/tree/main")
+	want := "\033]8;;https://example.com/tree/main\033\\main\033]8;;\033\\"
+	if got != want {
+		t.Errorf("Hyperlink() = %q, want %q", got, want)
+	}
+	if w := VisibleLength(got); w != 4 {
+		t.Errorf("VisibleLength() = %d, want 4", w)
+	}
+
+	if got := Hyperlink("main", ""); got != "main" {
+		t.Errorf("Hyperlink() without target = %q, want plain text", got)
+	}
+	if got := Hyperlink("x", "https://evil\033\\\a.example"); strings.Count(got, "\033") != 4 || strings.Contains(got, "\a") {
+		t.Errorf("Hyperlink() kept control characters: %q", got)
+	}
+}
+
+func TestHyperlink_Truncate(t *testing.T) {
+	useHyperlinks(t)
+
+	link := Colorize(Hyperlink("feature/long-branch", "https://example.com"), "magenta")
+	got := Truncate(link, 7)
+	if w := VisibleLength(got); w != 7 {
+		t.Errorf("VisibleLength(Truncate()) = %d, want 7", w)
+	}
+	if !strings.HasSuffix(got, "\033]8;;\033\\"+Reset) {
+		t.Errorf("Truncate() = %q, want the link and color closed", got)
+	}
+}
+
+func TestFileURL(t *testing.T) {
+	host, _ := os.Hostname()
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/home/me/my project", "file://" + host + "/home/me/my%20project"},
+		{"/tmp/t.jsonl", "file://" + host + "/tmp/t.jsonl"},
+		{"relative/path", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := FileURL(tt.path); got != tt.want {
+			t.Errorf("FileURL(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -146,14 +146,14 @@ func PowerlineLayout(segments []PowerlineSegment, separator string) string {
 			if arrowFg == "" {
 				arrowFg = "black"
 			}
-			result.WriteString(Reset)
+			result.WriteString(resetCode())
 			result.WriteString(nextBg)
 			result.WriteString(ResolveColor(arrowFg))
 			result.WriteString(separator)
 		}
 	}
 
-	result.WriteString(Reset)
+	result.WriteString(resetCode())
 
 	// Add final arrow to default background
 	if len(nonEmpty) > 0 {
@@ -161,7 +161,7 @@ func PowerlineLayout(segments []PowerlineSegment, separator string) string {
 		if lastBg != "" {
 			result.WriteString(ResolveColor(lastBg))
 			result.WriteString(separator)
-			result.WriteString(Reset)
+			result.WriteString(resetCode())
 		}
 	}
 
@@ -256,13 +256,13 @@ func powerlineLayoutReverse(segments []PowerlineSegment, separator string) strin
 			if arrowFg == "" {
 				arrowFg = "black"
 			}
-			result.WriteString(Reset)
+			result.WriteString(resetCode())
 			result.WriteString(bg)
 			result.WriteString(ResolveColor(arrowFg))
 			result.WriteString(separator)
 		}
 	}
 
-	result.WriteString(Reset)
+	result.WriteString(resetCode())
 	return result.String()
 }
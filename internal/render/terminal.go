@@ -0,0 +1,151 @@
+package render
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultWidth is used when no source knows the terminal width.
+const DefaultWidth = 80
+
+// Width sources, in resolution order.
+const (
+	WidthSourceFlag    = "--width"
+	WidthSourceConfig  = "config"
+	WidthSourceColumns = "COLUMNS"
+	WidthSourceTTY     = "/dev/tty"
+	WidthSourceParent  = "parent tty"
+	WidthSourceTmux    = "tmux"
+	WidthSourceDefault = "default"
+)
+
+// commandTimeout is the maximum time allowed for helper commands (tmux, ps).
+const commandTimeout = 200 * time.Millisecond
+
+// Width is a resolved terminal width.
+type Width struct {
+	Columns int    // Terminal width
+	Margin  int    // Columns reserved for Claude Code's own UI
+	Source  string // Where Columns came from (WidthSource*)
+	Detail  string // Device or pane the width was read from, if any
+}
+
+// Budget returns the columns available to the statusline.
+func (w Width) Budget() int {
+	return max(w.Columns-w.Margin, 1)
+}
+
+// String describes the width for --debug, e.g.
+// "118 (120 from parent tty /dev/pts/3, margin 2)".
+func (w Width) String() string {
+	from := w.Source
+	if w.Detail != "" {
+		from += " " + w.Detail
+	}
+	if w.Margin == 0 {
+		return fmt.Sprintf("%d (from %s)", w.Budget(), from)
+	}
+	return fmt.Sprintf("%d (%d from %s, margin %d)", w.Budget(), w.Columns, from, w.Margin)
+}
+
+// widthDetector reads the terminal width from one source.
+type widthDetector struct {
+	source string
+	detect func() (columns int, detail string, ok bool)
+}
+
+// widthDetectors is the automatic resolution chain. Claude Code runs the
+// statusline command without a TTY on stdio, so after COLUMNS we ask the
+// controlling terminal, then the terminal of an ancestor process.
+var widthDetectors = []widthDetector{
+	{WidthSourceColumns, columnsWidth},
+	{WidthSourceTTY, func() (int, string, bool) {
+		n, ok := ttyWidth("/dev/tty")
+		return n, "", ok
+	}},
+	{WidthSourceParent, parentTTYWidth},
+	{WidthSourceTmux, tmuxWidth},
+}
+
+// ResolveWidth returns the terminal width from the first source that
+// knows it: the --width flag, [general] width, then DetectWidth.
+func ResolveWidth(flagWidth, configWidth int) Width {
+	if flagWidth > 0 {
+		return Width{Columns: flagWidth, Source: WidthSourceFlag}
+	}
+	if configWidth > 0 {
+		return Width{Columns: configWidth, Source: WidthSourceConfig}
+	}
+	return DetectWidth()
+}
+
+// DetectWidth asks COLUMNS, an ioctl on /dev/tty, the TTY of a parent
+// process and the tmux pane width in turn, falling back to DefaultWidth.
+func DetectWidth() Width {
+	for _, d := range widthDetectors {
+		if n, detail, ok := d.detect(); ok && n > 0 {
+			return Width{Columns: n, Source: d.source, Detail: detail}
+		}
+	}
+	return Width{Columns: DefaultWidth, Source: WidthSourceDefault}
+}
+
+// widthBudget is the width set by SetTerminalWidth.
+var widthBudget int
+
+// SetTerminalWidth sets the width layouts fit into, usually a resolved
+// Width's Budget. 0 restores the COLUMNS-or-80 default.
+func SetTerminalWidth(columns int) {
+	widthBudget = columns
+}
+
+// TerminalWidth returns the width layouts fit into: the value from
+// SetTerminalWidth, else COLUMNS, else DefaultWidth.
+func TerminalWidth() int {
+	if widthBudget > 0 {
+		return widthBudget
+	}
+	if n, _, ok := columnsWidth(); ok {
+		return n
+	}
+	return DefaultWidth
+}
+
+func columnsWidth() (int, string, bool) {
+	cols := os.Getenv("COLUMNS")
+	if cols == "" {
+		return 0, "", false
+	}
+	n, err := strconv.Atoi(cols)
+	return n, "", err == nil && n > 0
+}
+
+// tmuxWidth returns the width of the tmux pane visor runs in.
+func tmuxWidth() (int, string, bool) {
+	if os.Getenv("TMUX") == "" {
+		return 0, "", false
+	}
+	args := []string{"display-message", "-p"}
+	pane := os.Getenv("TMUX_PANE")
+	if pane != "" {
+		args = append(args, "-t", pane)
+	}
+	out, err := runCommand("tmux", append(args, "#{pane_width}")...)
+	if err != nil {
+		return 0, "", false
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	return n, pane, err == nil && n > 0
+}
+
+// runCommand runs a command with commandTimeout and returns its output.
+func runCommand(name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, name, args...).Output()
+}
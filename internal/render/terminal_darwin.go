@@ -0,0 +1,28 @@
+//go:build darwin
+
+package render
+
+import (
+	"strconv"
+	"strings"
+)
+
+// processTTY returns the controlling terminal of pid, if any, and its
+// parent pid.
+func processTTY(pid int) (tty string, ppid int, ok bool) {
+	out, err := runCommand("ps", "-o", "ppid=,tty=", "-p", strconv.Itoa(pid))
+	if err != nil {
+		return "", 0, false
+	}
+	fields := strings.Fields(string(out)) // "123 ttys003" or "123 ??"
+	if len(fields) < 2 {
+		return "", 0, false
+	}
+	if ppid, err = strconv.Atoi(fields[0]); err != nil {
+		return "", 0, false
+	}
+	if fields[1] == "??" {
+		return "", ppid, true
+	}
+	return "/dev/" + fields[1], ppid, true
+}
@@ -0,0 +1,39 @@
+//go:build linux
+
+package render
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// processTTY returns the terminal device on a standard stream of pid, if
+// any, and its parent pid.
+func processTTY(pid int) (tty string, ppid int, ok bool) {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return "", 0, false
+	}
+	// "pid (comm) state ppid ..."; comm may contain spaces and parentheses
+	end := strings.LastIndexByte(string(stat), ')')
+	if end < 0 {
+		return "", 0, false
+	}
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 2 {
+		return "", 0, false
+	}
+	if ppid, err = strconv.Atoi(fields[1]); err != nil {
+		return "", 0, false
+	}
+
+	for fd := 0; fd <= 2; fd++ {
+		target, err := os.Readlink(fmt.Sprintf("/proc/%d/fd/%d", pid, fd))
+		if err == nil && (strings.HasPrefix(target, "/dev/pts/") || strings.HasPrefix(target, "/dev/tty")) {
+			return target, ppid, true
+		}
+	}
+	return "", ppid, true
+}
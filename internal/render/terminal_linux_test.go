@@ -0,0 +1,28 @@
+//go:build linux
+
+package render
+
+import (
+	"os"
+	"testing"
+)
+
+func TestProcessTTY(t *testing.T) {
+	_, ppid, ok := processTTY(os.Getpid())
+	if !ok {
+		t.Fatal("processTTY(self) failed")
+	}
+	if ppid != os.Getppid() {
+		t.Errorf("ppid = %d, want %d", ppid, os.Getppid())
+	}
+
+	if _, _, ok := processTTY(-1); ok {
+		t.Error("processTTY(-1) should fail")
+	}
+}
+
+func TestTTYWidth_NotATerminal(t *testing.T) {
+	if _, ok := ttyWidth(os.DevNull); ok {
+		t.Error("ttyWidth(/dev/null) should fail")
+	}
+}
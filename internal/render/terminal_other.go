@@ -0,0 +1,13 @@
+//go:build !darwin && !linux
+
+package render
+
+// ttyWidth is not supported on this platform.
+func ttyWidth(path string) (int, bool) {
+	return 0, false
+}
+
+// parentTTYWidth is not supported on this platform.
+func parentTTYWidth() (int, string, bool) {
+	return 0, "", false
+}
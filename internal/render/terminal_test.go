@@ -0,0 +1,102 @@
+package render
+
+import "testing"
+
+func stubDetectors(t *testing.T, detectors ...widthDetector) {
+	t.Helper()
+	orig := widthDetectors
+	widthDetectors = detectors
+	t.Cleanup(func() { widthDetectors = orig })
+}
+
+func fixedWidth(n int, detail string) func() (int, string, bool) {
+	return func() (int, string, bool) { return n, detail, n > 0 }
+}
+
+func TestResolveWidth(t *testing.T) {
+	stubDetectors(t,
+		widthDetector{WidthSourceColumns, fixedWidth(0, "")},
+		widthDetector{WidthSourceTTY, fixedWidth(0, "")},
+		widthDetector{WidthSourceParent, fixedWidth(132, "/dev/pts/3")},
+		widthDetector{WidthSourceTmux, fixedWidth(100, "%1")},
+	)
+
+	tests := []struct {
+		name                string
+		flagWidth, cfgWidth int
+		want                Width
+	}{
+		{"flag wins", 120, 90, Width{Columns: 120, Source: WidthSourceFlag}},
+		{"config", 0, 90, Width{Columns: 90, Source: WidthSourceConfig}},
+		{"first detector that knows", 0, 0, Width{Columns: 132, Source: WidthSourceParent, Detail: "/dev/pts/3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveWidth(tt.flagWidth, tt.cfgWidth); got != tt.want {
+				t.Errorf("ResolveWidth(%d, %d) = %+v, want %+v", tt.flagWidth, tt.cfgWidth, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectWidth_Default(t *testing.T) {
+	stubDetectors(t, widthDetector{WidthSourceTTY, fixedWidth(0, "")})
+
+	want := Width{Columns: DefaultWidth, Source: WidthSourceDefault}
+	if got := DetectWidth(); got != want {
+		t.Errorf("DetectWidth() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDetectWidth_Columns(t *testing.T) {
+	t.Setenv("COLUMNS", "150")
+
+	if got := DetectWidth(); got.Columns != 150 || got.Source != WidthSourceColumns {
+		t.Errorf("DetectWidth() = %+v, want 150 from COLUMNS", got)
+	}
+
+	t.Setenv("COLUMNS", "wide")
+	stubDetectors(t, widthDetector{WidthSourceColumns, columnsWidth})
+	if got := DetectWidth(); got.Source != WidthSourceDefault {
+		t.Errorf("DetectWidth() with invalid COLUMNS = %+v, want default", got)
+	}
+}
+
+func TestWidth_Budget(t *testing.T) {
+	tests := []struct {
+		width  Width
+		budget int
+		text   string
+	}{
+		{Width{Columns: 120, Source: WidthSourceColumns}, 120, "120 (from COLUMNS)"},
+		{Width{Columns: 120, Margin: 4, Source: WidthSourceParent, Detail: "/dev/pts/3"}, 116,
+			"116 (120 from parent tty /dev/pts/3, margin 4)"},
+		{Width{Columns: 3, Margin: 10, Source: WidthSourceFlag}, 1, "1 (3 from --width, margin 10)"},
+	}
+	for _, tt := range tests {
+		if got := tt.width.Budget(); got != tt.budget {
+			t.Errorf("%+v.Budget() = %d, want %d", tt.width, got, tt.budget)
+		}
+		if got := tt.width.String(); got != tt.text {
+			t.Errorf("%+v.String() = %q, want %q", tt.width, got, tt.text)
+		}
+	}
+}
+
+func TestSetTerminalWidth(t *testing.T) {
+	t.Cleanup(func() { SetTerminalWidth(0) })
+	t.Setenv("COLUMNS", "100")
+
+	if got := TerminalWidth(); got != 100 {
+		t.Errorf("TerminalWidth() = %d, want COLUMNS", got)
+	}
+	SetTerminalWidth(60)
+	if got := TerminalWidth(); got != 60 {
+		t.Errorf("TerminalWidth() = %d, want 60", got)
+	}
+
+	left := SplitLayout([]string{"left"}, []string{"right"}, " | ")
+	if got := VisibleLength(left); got != 60 {
+		t.Errorf("SplitLayout() width = %d, want 60", got)
+	}
+}
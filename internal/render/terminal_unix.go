@@ -0,0 +1,48 @@
+//go:build darwin || linux
+
+package render
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// maxParentDepth limits how far up the process tree parentTTYWidth looks.
+// Claude Code runs the statusline command through a shell, so its TTY is
+// usually two levels up.
+const maxParentDepth = 6
+
+// ttyWidth returns the column count of the terminal device at path.
+func ttyWidth(path string) (int, bool) {
+	f, err := os.OpenFile(path, os.O_RDONLY|unix.O_NOCTTY, 0)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 {
+		return 0, false
+	}
+	return int(ws.Col), true
+}
+
+// parentTTYWidth returns the width of the first terminal attached to an
+// ancestor process.
+func parentTTYWidth() (int, string, bool) {
+	pid := os.Getppid()
+	for i := 0; i < maxParentDepth && pid > 1; i++ {
+		tty, ppid, ok := processTTY(pid)
+		if !ok {
+			return 0, "", false
+		}
+		if tty != "" {
+			if n, ok := ttyWidth(tty); ok {
+				return n, tty, true
+			}
+		}
+		pid = ppid
+	}
+	return 0, "", false
+}
@@ -0,0 +1,147 @@
+package render
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// Text is measured per grapheme cluster (user-perceived character), so
+// emoji ZWJ sequences, flags, skin tones and combining marks count once.
+// Cluster widths come from the Unicode East Asian Width and emoji
+// presentation tables.
+
+// puaWidth is the width of Private Use Area glyphs (Nerd Font icons).
+var puaWidth = 1
+
+// SetWidths sets the cell width of East Asian Ambiguous characters
+// ("→", "…", "±") and of Private Use Area glyphs such as Nerd Font icons.
+// Terminals disagree on both; values other than 2 mean 1.
+func SetWidths(ambiguous, pua int) {
+	uniseg.EastAsianAmbiguousWidth = normalizeWidth(ambiguous)
+	puaWidth = normalizeWidth(pua)
+}
+
+func normalizeWidth(w int) int {
+	if w == 2 {
+		return 2
+	}
+	return 1
+}
+
+// StringWidth returns the display width of plain text (no ANSI codes).
+func StringWidth(s string) int {
+	width := 0
+	forEachCluster(s, func(_ string, w int) bool {
+		width += w
+		return true
+	})
+	return width
+}
+
+// Head returns the longest prefix of plain text that fits in maxWidth,
+// cut at a grapheme cluster boundary.
+func Head(s string, maxWidth int) string {
+	width, end := 0, 0
+	forEachCluster(s, func(cluster string, w int) bool {
+		if width+w > maxWidth {
+			return false
+		}
+		width += w
+		end += len(cluster)
+		return true
+	})
+	return s[:end]
+}
+
+// Tail returns the longest suffix of plain text that fits in maxWidth,
+// cut at a grapheme cluster boundary.
+func Tail(s string, maxWidth int) string {
+	var clusters []string
+	var widths []int
+	forEachCluster(s, func(cluster string, w int) bool {
+		clusters = append(clusters, cluster)
+		widths = append(widths, w)
+		return true
+	})
+
+	width, start := 0, len(s)
+	for i := len(clusters) - 1; i >= 0; i-- {
+		if width+widths[i] > maxWidth {
+			break
+		}
+		width += widths[i]
+		start -= len(clusters[i])
+	}
+	return s[start:]
+}
+
+// forEachCluster calls fn with each grapheme cluster of s and its width
+// until fn returns false.
+func forEachCluster(s string, fn func(cluster string, width int) bool) {
+	state := -1
+	for s != "" {
+		var cluster string
+		var width int
+		cluster, s, width, state = uniseg.FirstGraphemeClusterInString(s, state)
+		if r, _ := utf8.DecodeRuneInString(cluster); isPrivateUse(r) {
+			width = puaWidth
+		}
+		if !fn(cluster, width) {
+			return
+		}
+	}
+}
+
+// isPrivateUse reports whether r is in a Private Use Area, where Nerd
+// Font and Powerline symbols live.
+func isPrivateUse(r rune) bool {
+	return r >= 0xE000 && r <= 0xF8FF ||
+		r >= 0xF0000 && r <= 0xFFFFD ||
+		r >= 0x100000 && r <= 0x10FFFD
+}
+
+// escapeLen returns the length of the ANSI escape sequence at the start
+// of s, or 0 if s doesn't start with one. Handles CSI ("\033[31m") and
+// OSC ("\033]8;;url\033\\") sequences.
+func escapeLen(s string) int {
+	if s == "" || s[0] != '\033' {
+		return 0
+	}
+	if len(s) < 2 {
+		return 1
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7E {
+				return i + 1
+			}
+		}
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		return 2
+	}
+	return len(s)
+}
+
+// nextText splits s into the leading escape sequence or run of text and
+// the rest.
+func nextText(s string) (segment string, escape bool, rest string) {
+	if n := escapeLen(s); n > 0 {
+		return s[:n], true, s[n:]
+	}
+	if i := strings.IndexByte(s, '\033'); i >= 0 {
+		return s[:i], false, s[i:]
+	}
+	return s, false, ""
+}
@@ -0,0 +1,131 @@
+package render
+
+import "testing"
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{"ascii", "hello", 5},
+		{"hangul", "한글", 4},
+		{"fullwidth", "ＡＢ", 4},
+		{"emoji", "🔥", 2},
+		{"zwj sequence", "👨‍👩‍👧‍👦", 2},
+		{"skin tone", "👍🏽", 2},
+		{"flag", "🇰🇷", 2},
+		{"variation selector 16", "\u26a0\ufe0f", 2},
+		{"text presentation", "\u26a0", 1},
+		{"combining mark", "e\u0301", 1},
+		{"decomposed hangul", "\u1112\u1161\u11ab", 2},
+		{"zero width space", "a\u200bb", 2},
+		{"ambiguous", "→…", 2},
+		{"nerd font icon", "\ue0a0", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StringWidth(tt.input); got != tt.want {
+				t.Errorf("StringWidth(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetWidths(t *testing.T) {
+	t.Cleanup(func() { SetWidths(1, 1) })
+
+	SetWidths(2, 2)
+	if got := StringWidth("→…"); got != 4 {
+		t.Errorf("ambiguous width = %d, want 4", got)
+	}
+	if got := StringWidth("\ue0a0 main"); got != 7 {
+		t.Errorf("PUA width = %d, want 7", got)
+	}
+	if got := StringWidth("한→"); got != 4 {
+		t.Errorf("mixed width = %d, want 4", got)
+	}
+
+	SetWidths(0, 3)
+	if got := StringWidth("→"); got != 2 {
+		t.Errorf("width after reset = %d, want 2", got)
+	}
+}
+
+func TestHeadTail(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		head  string
+		tail  string
+	}{
+		{"hello", 10, "hello", "hello"},
+		{"hello", 3, "hel", "llo"},
+		{"한글abc", 3, "한", "abc"},
+		{"abc한글", 3, "abc", "글"},
+		{"a👨‍👩‍👧b", 2, "a", "b"},
+		{"a👨‍👩‍👧b", 3, "a👨‍👩‍👧", "👨‍👩‍👧b"},
+		{"🇰🇷🇯🇵", 3, "🇰🇷", "🇯🇵"},
+		{"éé", 1, "é", "é"},
+		{"abc", 0, "", ""},
+	}
+	for _, tt := range tests {
+		if got := Head(tt.input, tt.width); got != tt.head {
+			t.Errorf("Head(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.head)
+		}
+		if got := Tail(tt.input, tt.width); got != tt.tail {
+			t.Errorf("Tail(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.tail)
+		}
+	}
+}
+
+func TestTruncate_GraphemeClusters(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  string
+	}{
+		{"ab👨‍👩‍👧cd", 4, "ab👨‍👩‍👧"},
+		{"ab👨‍👩‍👧cd", 3, "ab."},
+		{"\033[31m🇰🇷🇯🇵\033[0m", 3, "\033[31m🇰🇷.\033[0m"},
+		{"한글", 3, "한."},
+	}
+	for _, tt := range tests {
+		got := Truncate(tt.input, tt.width)
+		if got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+		}
+		if w := VisibleLength(got); w > tt.width {
+			t.Errorf("Truncate(%q, %d) width = %d", tt.input, tt.width, w)
+		}
+	}
+}
+
+func TestVisibleLength_Escapes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{"sgr", "\033[1;38;2;255;0;0mred\033[0m", 3},
+		{"osc 8 with ST", "\033]8;;https://example.com\033\\link\033]8;;\033\\", 4},
+		{"osc 8 with BEL", "\033]8;;https://example.com\alink\033]8;;\a", 4},
+		{"emoji in color", "\033[31m👍🏽\033[0m ok", 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VisibleLength(tt.input); got != tt.want {
+				t.Errorf("VisibleLength(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate_KeepsOSC(t *testing.T) {
+	input := "\033]8;;https://example.com\033\\long link text\033]8;;\033\\"
+	got := Truncate(input, 4)
+	want := "\033]8;;https://example.com\033\\long\033]8;;\033\\"
+	if got != want {
+		t.Errorf("Truncate() = %q, want %q", got, want)
+	}
+}
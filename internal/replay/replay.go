@@ -0,0 +1,159 @@
+// Package replay records statusline payloads and loads them back so a
+// session can be re-rendered offline (visor --record, visor replay).
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FileExt is the extension of recording files.
+const FileExt = ".jsonl"
+
+// maxLineSize bounds a single recorded frame (payload plus output).
+const maxLineSize = 4 * 1024 * 1024
+
+// Frame is one recorded statusline invocation.
+type Frame struct {
+	Time   time.Time       `json:"time"`
+	Input  json.RawMessage `json:"input"`
+	Output string          `json:"output"`
+
+	// Source is the recording file the frame was loaded from.
+	Source string `json:"-"`
+}
+
+// NewFrame builds a frame from the raw stdin payload and rendered output.
+// A payload that is not valid JSON is kept verbatim as a JSON string, so
+// replaying it reproduces the same parse failure.
+func NewFrame(at time.Time, stdin []byte, output string) Frame {
+	var compact bytes.Buffer
+	trimmed := bytes.TrimSpace(stdin)
+	if bytes.HasPrefix(trimmed, []byte("{")) && json.Compact(&compact, trimmed) == nil {
+		return Frame{Time: at, Input: compact.Bytes(), Output: output}
+	}
+	quoted, _ := json.Marshal(string(stdin))
+	return Frame{Time: at, Input: quoted, Output: output}
+}
+
+// Stdin returns the payload as it was read from stdin.
+func (f Frame) Stdin() []byte {
+	var s string
+	if len(f.Input) > 0 && f.Input[0] == '"' && json.Unmarshal(f.Input, &s) == nil {
+		return []byte(s)
+	}
+	return f.Input
+}
+
+// FileName returns the recording file name for a session.
+// Characters that are unsafe in file names are replaced.
+func FileName(sessionID string) string {
+	if sessionID == "" {
+		sessionID = "unknown"
+	}
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, sessionID)
+	return strings.TrimLeft(name, ".") + FileExt
+}
+
+// Append adds a frame to the session's recording file in dir.
+// Recordings may contain prompts and paths, so files are private (0600).
+func Append(dir, sessionID string, frame Frame) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(frame)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, FileName(sessionID))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load reads frames from a recording file, or from every recording file
+// in a directory. Frames are returned in time order.
+func Load(path string) ([]Frame, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*"+FileExt))
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no %s recordings in %s", FileExt, path)
+		}
+	}
+
+	var frames []Frame
+	for _, file := range files {
+		fileFrames, err := loadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, fileFrames...)
+	}
+
+	sort.SliceStable(frames, func(i, j int) bool {
+		return frames[i].Time.Before(frames[j].Time)
+	})
+	return frames, nil
+}
+
+// loadFile reads one recording file. Malformed lines are reported with
+// their line number rather than skipped, since a replay should be exact.
+func loadFile(path string) ([]Frame, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var frames []Frame
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var frame Frame
+		if err := json.Unmarshal([]byte(line), &frame); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNum, err)
+		}
+		frame.Source = path
+		frames = append(frames, frame)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return frames, nil
+}
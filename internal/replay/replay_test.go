@@ -0,0 +1,111 @@
+package replay
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewFrame_CompactsJSON(t *testing.T) {
+	frame := NewFrame(time.Now(), []byte("{\n  \"session_id\": \"s1\"\n}\n"), "out")
+	if string(frame.Input) != `{"session_id":"s1"}` {
+		t.Errorf("Input = %s, want compact JSON", frame.Input)
+	}
+	if string(frame.Stdin()) != `{"session_id":"s1"}` {
+		t.Errorf("Stdin() = %q", frame.Stdin())
+	}
+}
+
+func TestNewFrame_KeepsInvalidPayload(t *testing.T) {
+	frame := NewFrame(time.Now(), []byte("not json\n"), "")
+	if got := string(frame.Stdin()); got != "not json\n" {
+		t.Errorf("Stdin() = %q, want original payload", got)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		sessionID string
+		expected  string
+	}{
+		{"abc-123", "abc-123.jsonl"},
+		{"", "unknown.jsonl"},
+		{"../../etc/passwd", "_.._etc_passwd.jsonl"},
+	}
+
+	for _, tt := range tests {
+		if got := FileName(tt.sessionID); got != tt.expected {
+			t.Errorf("FileName(%q) = %q, want %q", tt.sessionID, got, tt.expected)
+		}
+	}
+}
+
+func TestAppendAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	frames := []struct {
+		session string
+		at      time.Time
+	}{
+		{"b", base.Add(2 * time.Second)},
+		{"a", base},
+		{"a", base.Add(time.Second)},
+	}
+	for _, f := range frames {
+		stdin := `{"session_id":"` + f.session + `"}`
+		if err := Append(dir, f.session, NewFrame(f.at, []byte(stdin), "line "+f.session)); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "a.jsonl"))
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("recording mode = %o, want 600", perm)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load(dir): %v", err)
+	}
+	if len(loaded) != 3 {
+		t.Fatalf("Load(dir) returned %d frames, want 3", len(loaded))
+	}
+	for i, want := range []time.Time{base, base.Add(time.Second), base.Add(2 * time.Second)} {
+		if !loaded[i].Time.Equal(want) {
+			t.Errorf("frame %d time = %v, want %v", i, loaded[i].Time, want)
+		}
+	}
+	if loaded[2].Output != "line b" || filepath.Base(loaded[2].Source) != "b.jsonl" {
+		t.Errorf("frame 2 = %+v", loaded[2])
+	}
+
+	single, err := Load(filepath.Join(dir, "a.jsonl"))
+	if err != nil {
+		t.Fatalf("Load(file): %v", err)
+	}
+	if len(single) != 2 {
+		t.Errorf("Load(file) returned %d frames, want 2", len(single))
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Load(dir); err == nil {
+		t.Error("Load of empty directory should fail")
+	}
+
+	path := filepath.Join(dir, "bad.jsonl")
+	if err := os.WriteFile(path, []byte("{\"time\":\"2026-01-02T10:00:00Z\",\"input\":{}}\n{oops\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "bad.jsonl:2") {
+		t.Errorf("Load error = %v, want line number", err)
+	}
+}
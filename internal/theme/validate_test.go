@@ -48,15 +48,15 @@ func TestValidateColor(t *testing.T) {
 		{"named gray", "gray", true},
 		{"named grey", "grey", true},
 
-		// Named colors - case insensitive
-		{"named uppercase", "RED", true},
-		{"named mixed case", "Blue", true},
-		{"named CYAN", "CYAN", true},
+		// Named colors are case-sensitive, like render.ColorMap
+		{"named uppercase", "RED", false},
+		{"named mixed case", "Blue", false},
 
 		// Bright named colors
-		{"bright black", "brightblack", true},
-		{"bright red", "brightred", true},
-		{"bright white", "brightwhite", true},
+		{"bright black", "bright_black", true},
+		{"bright red", "bright_red", true},
+		{"bright white", "bright_white", true},
+		{"bright without underscore", "brightred", false},
 
 		// Invalid named colors
 		{"invalid name", "orange", false},
@@ -6,6 +6,8 @@ import (
 	"os"
 	"strconv"
 	"time"
+
+	"github.com/namyoungkim/visor/internal/cost"
 )
 
 // defaultMaxLines is the default number of transcript lines to parse.
@@ -30,12 +32,26 @@ type transcriptEntry struct {
 	Type      string          `json:"type"`
 	Timestamp json.RawMessage `json:"timestamp"` // Can be int64 (millis) or string (ISO 8601)
 	Message   struct {
+		ID      string          `json:"id"`
+		Model   string          `json:"model"`
 		Content json.RawMessage `json:"content"` // Can be string or []contentBlock
+		Usage   *messageUsage   `json:"usage"`
 	} `json:"message"`
 	Data struct {
 		AgentID string `json:"agentId"`
 	} `json:"data"`
-	ToolUseID string `json:"toolUseID"`
+	ToolUseID     string          `json:"toolUseID"`
+	ToolUseResult json.RawMessage `json:"toolUseResult"` // Object for Task results (carries agentId), string on errors
+	IsSidechain   bool            `json:"isSidechain"`   // Sub-agent entry in the main transcript (older Claude Code)
+	IsMeta        bool            `json:"isMeta"`        // Injected by Claude Code, not a user turn
+}
+
+// messageUsage is the token usage reported on assistant messages.
+type messageUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
 }
 
 // parseTimestamp attempts to parse timestamp from various formats.
@@ -86,18 +102,29 @@ func parseContentBlocks(raw json.RawMessage) []contentBlock {
 
 // contentBlock represents a content block in the message.
 type contentBlock struct {
-	Type      string `json:"type"`
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	ToolUseID string `json:"tool_use_id"`
-	IsError   *bool  `json:"is_error"`
+	Type      string          `json:"type"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	ToolUseID string          `json:"tool_use_id"`
+	IsError   *bool           `json:"is_error"`
+	Content   json.RawMessage `json:"content"` // tool_result: string or []{"type":"text","text":...}
 	Input     struct {
 		SubagentType string `json:"subagent_type"`
 		Description  string `json:"description"` // Task description (also used by TaskCreate)
 		// TaskCreate/TaskUpdate fields
-		Subject string `json:"subject"`
-		TaskID  string `json:"taskId"`
-		Status  string `json:"status"`
+		Subject    string `json:"subject"`
+		TaskID     string `json:"taskId"`
+		Status     string `json:"status"`
+		ActiveForm string `json:"activeForm"`
+		// TodoWrite carries the whole list at once
+		Todos []todoWriteItem `json:"todos"`
+		// Fields used to describe what an in-flight tool is working on
+		Command      string `json:"command"`       // Bash
+		FilePath     string `json:"file_path"`     // Read, Edit, MultiEdit, Write
+		NotebookPath string `json:"notebook_path"` // NotebookEdit
+		Pattern      string `json:"pattern"`       // Grep, Glob
+		URL          string `json:"url"`           // WebFetch
+		Query        string `json:"query"`         // WebSearch
 	} `json:"input"`
 }
 
@@ -126,7 +153,15 @@ func ParseWithDebug(path string, debug bool) *Data {
 		fmt.Fprintf(os.Stderr, "[transcript] parsed %d lines (max: %d)\n", len(lines), maxLines)
 	}
 
-	return parseLinesWithDebug(lines, debug)
+	data := parseLinesWithDebug(lines, debug)
+	resolveSubagents(data, path, debug)
+	return data
+}
+
+// Tail returns the last lines of a transcript, as many as Parse reads
+// (VISOR_TRANSCRIPT_MAX_LINES, default 500).
+func Tail(path string) ([]string, error) {
+	return tailLines(path, getMaxLines())
 }
 
 // tailLines reads the last n lines from a file efficiently.
@@ -237,20 +272,7 @@ func parseLines(lines []string) *Data {
 
 // parseLinesWithDebug processes JSONL lines.
 func parseLinesWithDebug(lines []string, debug bool) *Data {
-	data := &Data{
-		Tools:  make([]Tool, 0),
-		Agents: make([]Agent, 0),
-		Todos:  make([]Todo, 0),
-	}
-
-	// toolMap groups tools by Name (not ID) to count invocations
-	toolMap := make(map[string]*Tool)    // key: tool Name
-	toolIDMap := make(map[string]string) // key: tool ID -> tool Name (for result lookup)
-	agentMap := make(map[string]*Agent)  // key: tool ID
-	todoMap := make(map[string]*Todo)    // key: task ID
-	var toolOrder []string               // Maintain insertion order by Name
-	var agentOrder []string              // Maintain insertion order by ID
-	var todoOrder []string               // Maintain insertion order by task ID
+	p := newParser()
 
 	parseErrors := 0
 	for _, line := range lines {
@@ -262,49 +284,126 @@ func parseLinesWithDebug(lines []string, debug bool) *Data {
 
 		switch entry.Type {
 		case "assistant":
-			processAssistant(&entry, toolMap, toolIDMap, agentMap, todoMap, &toolOrder, &agentOrder, &todoOrder)
+			p.closeInterruptedCalls(&entry)
+			p.processAssistant(&entry)
+			p.processUsage(&entry)
 		case "user":
-			processToolResult(&entry, toolMap, toolIDMap, agentMap)
+			p.processToolResult(&entry)
+			p.closeInterruptedCalls(&entry)
+		case "progress":
+			p.processProgress(&entry)
 		}
 	}
 
-	// Convert maps to slices in insertion order
-	for _, name := range toolOrder {
-		if tool, ok := toolMap[name]; ok {
+	data := p.data()
+
+	if debug {
+		fmt.Fprintf(os.Stderr, "[transcript] tools=%d, calls=%d, agents=%d, parseErrors=%d\n",
+			len(data.Tools), len(data.Calls), len(data.Agents), parseErrors)
+	}
+
+	return data
+}
+
+// parser holds the intermediate state while processing transcript lines.
+type parser struct {
+	toolMap      map[string]*Tool       // key: tool Name (groups invocations to count them)
+	toolIDMap    map[string]string      // key: tool ID -> tool Name (for result lookup)
+	callMap      map[string]*ToolCall   // key: tool ID
+	callMsgIDs   map[string]string      // key: tool ID -> ID of the assistant message that issued it
+	agentMap     map[string]*Agent      // key: tool ID
+	todoMap      map[string]*Todo       // key: task ID
+	pendingTasks map[string]*Todo       // key: TaskCreate tool ID awaiting its result (real task ID)
+	fileMap      map[string]*FileAccess // key: file path
+	pathIDMap    map[string]string      // key: tool ID -> file path (for result lookup)
+
+	toolOrder  []string // Maintain insertion order by Name
+	callOrder  []string // Maintain insertion order by ID
+	openCalls  []string // Call IDs that may still be waiting for a tool_result
+	agentOrder []string // Maintain insertion order by ID
+	todoOrder  []string // Maintain insertion order by task ID
+	fileOrder  []string // Maintain insertion order by path
+
+	lastModifiedFile string
+
+	usage      Usage
+	seenMsgIDs map[string]bool // Assistant message IDs already counted toward usage
+}
+
+func newParser() *parser {
+	return &parser{
+		toolMap:      make(map[string]*Tool),
+		toolIDMap:    make(map[string]string),
+		callMap:      make(map[string]*ToolCall),
+		callMsgIDs:   make(map[string]string),
+		agentMap:     make(map[string]*Agent),
+		todoMap:      make(map[string]*Todo),
+		pendingTasks: make(map[string]*Todo),
+		fileMap:      make(map[string]*FileAccess),
+		pathIDMap:    make(map[string]string),
+
+		seenMsgIDs: make(map[string]bool),
+	}
+}
+
+// data converts the parser maps to slices in insertion order.
+func (p *parser) data() *Data {
+	data := &Data{
+		Tools:  make([]Tool, 0, len(p.toolOrder)),
+		Calls:  make([]ToolCall, 0, len(p.callOrder)),
+		Agents: make([]Agent, 0, len(p.agentOrder)),
+		Todos:  make([]Todo, 0, len(p.todoOrder)),
+		Files:  make([]FileAccess, 0, len(p.fileOrder)),
+
+		LastModifiedFile: p.lastModifiedFile,
+		Usage:            p.usage,
+	}
+
+	for _, name := range p.toolOrder {
+		if tool, ok := p.toolMap[name]; ok {
 			data.Tools = append(data.Tools, *tool)
 		}
 	}
-	for _, id := range agentOrder {
-		if agent, ok := agentMap[id]; ok {
+	for _, id := range p.callOrder {
+		if call, ok := p.callMap[id]; ok {
+			data.Calls = append(data.Calls, *call)
+		}
+	}
+	for _, id := range p.agentOrder {
+		if agent, ok := p.agentMap[id]; ok {
 			data.Agents = append(data.Agents, *agent)
 		}
 	}
-	for _, id := range todoOrder {
-		if todo, ok := todoMap[id]; ok {
+	for _, id := range p.todoOrder {
+		if todo, ok := p.todoMap[id]; ok {
 			data.Todos = append(data.Todos, *todo)
 		}
 	}
 
-	if debug {
-		fmt.Fprintf(os.Stderr, "[transcript] tools=%d, agents=%d, parseErrors=%d\n",
-			len(data.Tools), len(data.Agents), parseErrors)
+	for _, path := range p.fileOrder {
+		if file, ok := p.fileMap[path]; ok {
+			data.Files = append(data.Files, *file)
+		}
 	}
 
+	data.ToolStats = computeToolStats(data.Calls)
+
 	return data
 }
 
 // processAssistant handles assistant messages containing tool_use.
-func processAssistant(entry *transcriptEntry, toolMap map[string]*Tool, toolIDMap map[string]string, agentMap map[string]*Agent, todoMap map[string]*Todo, toolOrder, agentOrder, todoOrder *[]string) {
+func (p *parser) processAssistant(entry *transcriptEntry) {
 	blocks := parseContentBlocks(entry.Message.Content)
+	ts := parseTimestamp(entry.Timestamp)
 	for _, block := range blocks {
 		if block.Type != "tool_use" {
 			continue
 		}
 
 		// Track tool by Name (group same tools together)
-		toolIDMap[block.ID] = block.Name // Map ID -> Name for result lookup
+		p.toolIDMap[block.ID] = block.Name // Map ID -> Name for result lookup
 
-		if existing, exists := toolMap[block.Name]; exists {
+		if existing, exists := p.toolMap[block.Name]; exists {
 			// Tool already seen: increment count, update status to running
 			existing.Count++
 			existing.Status = ToolRunning
@@ -317,90 +416,68 @@ func processAssistant(entry *transcriptEntry, toolMap map[string]*Tool, toolIDMa
 				Status: ToolRunning,
 				Count:  1,
 			}
-			toolMap[block.Name] = tool
-			*toolOrder = append(*toolOrder, block.Name)
+			p.toolMap[block.Name] = tool
+			p.toolOrder = append(p.toolOrder, block.Name)
+		}
+
+		// Track the individual invocation for latency/error stats
+		if _, exists := p.callMap[block.ID]; !exists {
+			p.callMap[block.ID] = &ToolCall{
+				ID:        block.ID,
+				Name:      block.Name,
+				Target:    toolTarget(&block),
+				StartTime: ts,
+			}
+			p.callOrder = append(p.callOrder, block.ID)
+			p.callMsgIDs[block.ID] = entry.Message.ID
+			p.openCalls = append(p.openCalls, block.ID)
+		}
+
+		// Remember file paths so the result can record a successful read/edit
+		if path := filePathInput(&block); path != "" {
+			p.pathIDMap[block.ID] = path
 		}
 
 		// Check if this is a Task tool (spawns agent)
 		if block.Name == "Task" && block.Input.SubagentType != "" {
-			if _, exists := agentMap[block.ID]; !exists {
+			if _, exists := p.agentMap[block.ID]; !exists {
 				agent := &Agent{
 					ID:          block.ID,
 					Type:        block.Input.SubagentType,
 					Status:      "running",
 					Description: block.Input.Description,
-					StartTime:   parseTimestamp(entry.Timestamp),
+					StartTime:   ts,
 				}
-				agentMap[block.ID] = agent
-				*agentOrder = append(*agentOrder, block.ID)
-			}
-		}
-
-		// Handle TaskCreate - creates a new todo
-		if block.Name == "TaskCreate" && block.Input.Subject != "" {
-			// TaskCreate uses tool_use ID as the todo ID (we'll get real ID from result)
-			// For now, track by tool_use ID and update later
-			todo := &Todo{
-				ID:      block.ID, // Temporary, will be updated from tool_result
-				Subject: block.Input.Subject,
-				Status:  TodoPending,
+				p.agentMap[block.ID] = agent
+				p.agentOrder = append(p.agentOrder, block.ID)
 			}
-			todoMap[block.ID] = todo
-			*todoOrder = append(*todoOrder, block.ID)
 		}
 
-		// Handle TaskUpdate - updates existing todo status
-		if block.Name == "TaskUpdate" && block.Input.TaskID != "" {
-			// Find the todo by its real task ID and update status
-			for _, todo := range todoMap {
-				if todo.ID == block.Input.TaskID {
-					if block.Input.Status != "" {
-						todo.Status = TodoStatus(block.Input.Status)
-					}
-					break
-				}
-			}
-		}
+		// Handle TaskCreate/TaskUpdate/TodoWrite
+		p.processTodoTool(&block, ts)
 	}
 }
 
-// toolResultBlock extends contentBlock with result content for parsing TaskCreate results.
-type toolResultBlock struct {
-	Type      string `json:"type"`
-	ToolUseID string `json:"tool_use_id"`
-	IsError   *bool  `json:"is_error"`
-	Content   string `json:"content"` // Contains the result text
-}
-
-// parseToolResultBlocks parses tool_result blocks with content.
-func parseToolResultBlocks(raw json.RawMessage) []toolResultBlock {
-	if len(raw) == 0 {
-		return nil
-	}
-	if raw[0] != '[' {
-		return nil
-	}
-	var blocks []toolResultBlock
-	if err := json.Unmarshal(raw, &blocks); err != nil {
-		return nil
-	}
-	return blocks
-}
-
 // processToolResult handles tool_result messages to update tool and agent status.
-func processToolResult(entry *transcriptEntry, toolMap map[string]*Tool, toolIDMap map[string]string, agentMap map[string]*Agent) {
+func (p *parser) processToolResult(entry *transcriptEntry) {
 	blocks := parseContentBlocks(entry.Message.Content)
+	ts := parseTimestamp(entry.Timestamp)
 	for _, block := range blocks {
 		if block.Type != "tool_result" {
 			continue
 		}
 
+		isError := block.IsError != nil && *block.IsError
+
 		// Update tool status (lookup by ID -> Name)
-		if toolName, ok := toolIDMap[block.ToolUseID]; ok {
-			if tool, ok := toolMap[toolName]; ok {
+		if toolName, ok := p.toolIDMap[block.ToolUseID]; ok {
+			if tool, ok := p.toolMap[toolName]; ok {
+				if isError {
+					tool.Errors++
+				}
 				// Only update status if this is the latest invocation
 				if tool.ID == block.ToolUseID {
-					if block.IsError != nil && *block.IsError {
+					if isError {
 						tool.Status = ToolError
 					} else {
 						tool.Status = ToolCompleted
@@ -409,10 +486,148 @@ func processToolResult(entry *transcriptEntry, toolMap map[string]*Tool, toolIDM
 			}
 		}
 
+		// Pair the result with its invocation
+		if call, ok := p.callMap[block.ToolUseID]; ok && !call.Done {
+			call.Done = true
+			call.EndTime = ts
+			call.IsError = isError
+		}
+
+		// Replace the temporary TaskCreate ID with the real task ID
+		if !isError {
+			p.processTodoResult(&block)
+		}
+
+		// Record the file access once the tool succeeded
+		if path, ok := p.pathIDMap[block.ToolUseID]; ok && !isError {
+			p.recordFileAccess(path, p.toolIDMap[block.ToolUseID], ts)
+			delete(p.pathIDMap, block.ToolUseID)
+		}
+
 		// Update agent status (Task tool completion)
-		if agent, ok := agentMap[block.ToolUseID]; ok {
+		if agent, ok := p.agentMap[block.ToolUseID]; ok {
 			agent.Status = "completed"
-			agent.EndTime = parseTimestamp(entry.Timestamp)
+			agent.EndTime = ts
+			if agent.AgentID == "" {
+				agent.AgentID = resultAgentID(entry.ToolUseResult)
+			}
+		}
+	}
+}
+
+// closeInterruptedCalls marks calls still waiting for a tool_result as
+// interrupted once a later turn begins: an assistant message other than the
+// one that issued them, or a user message that carries no tool_result.
+// Parallel calls share one message and are answered before the next one, so
+// neither case happens while a call is still running.
+func (p *parser) closeInterruptedCalls(entry *transcriptEntry) {
+	if entry.IsSidechain || entry.IsMeta {
+		return
+	}
+
+	msgID := ""
+	switch entry.Type {
+	case "assistant":
+		if msgID = entry.Message.ID; msgID == "" {
+			return // Can't tell a new message from another block of the same one
+		}
+	case "user":
+		for _, block := range parseContentBlocks(entry.Message.Content) {
+			if block.Type == "tool_result" {
+				return
+			}
+		}
+	}
+
+	open := p.openCalls[:0]
+	for _, id := range p.openCalls {
+		call := p.callMap[id]
+		switch {
+		case call.Done:
+		case msgID != "" && p.callMsgIDs[id] == msgID:
+			open = append(open, id)
+		default:
+			call.Interrupted = true
 		}
 	}
+	p.openCalls = open
+}
+
+// filePathInput returns the file path a file tool operates on, or "" for other tools.
+func filePathInput(block *contentBlock) string {
+	switch block.Name {
+	case "Read", "Edit", "MultiEdit", "Write":
+		return block.Input.FilePath
+	case "NotebookEdit":
+		return block.Input.NotebookPath
+	}
+	return ""
+}
+
+// recordFileAccess marks a path as read or modified depending on the tool.
+func (p *parser) recordFileAccess(path, toolName string, ts int64) {
+	file, ok := p.fileMap[path]
+	if !ok {
+		file = &FileAccess{Path: path}
+		p.fileMap[path] = file
+		p.fileOrder = append(p.fileOrder, path)
+	}
+
+	if toolName == "Read" {
+		file.Read = true
+		return
+	}
+
+	file.Modified = true
+	file.LastModified = ts
+	p.lastModifiedFile = path
+}
+
+// processUsage accumulates token usage from assistant messages.
+// Claude Code writes one entry per content block with the same message ID
+// and usage, so each message ID is counted once.
+func (p *parser) processUsage(entry *transcriptEntry) {
+	u := entry.Message.Usage
+	if u == nil {
+		return
+	}
+	if id := entry.Message.ID; id != "" {
+		if p.seenMsgIDs[id] {
+			return
+		}
+		p.seenMsgIDs[id] = true
+	}
+
+	p.usage.InputTokens += u.InputTokens
+	p.usage.OutputTokens += u.OutputTokens
+	p.usage.CacheReadTokens += u.CacheReadInputTokens
+	p.usage.CacheWriteTokens += u.CacheCreationInputTokens
+	p.usage.CostUSD += cost.CalculateCost(entry.Message.Model,
+		u.InputTokens, u.OutputTokens, u.CacheReadInputTokens, u.CacheCreationInputTokens)
+}
+
+// processProgress handles progress entries emitted while a sub-agent runs.
+// They link the parent Task tool_use ID to the sidechain agent ID.
+func (p *parser) processProgress(entry *transcriptEntry) {
+	if entry.Data.AgentID == "" || entry.ToolUseID == "" {
+		return
+	}
+	if agent, ok := p.agentMap[entry.ToolUseID]; ok && agent.AgentID == "" {
+		agent.AgentID = entry.Data.AgentID
+	}
+}
+
+// resultAgentID extracts the agentId from a Task tool result payload.
+// Returns "" if the payload is not an object or has no agentId.
+func resultAgentID(raw json.RawMessage) string {
+	if len(raw) == 0 || raw[0] != '{' {
+		return ""
+	}
+	var result struct {
+		AgentID string `json:"agentId"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return ""
+	}
+	return result.AgentID
 }
@@ -330,6 +330,80 @@ func TestParse_StringContent(t *testing.T) {
 	}
 }
 
+func TestParse_ToolCallPairing(t *testing.T) {
+	content := `{"type":"assistant","timestamp":1000,"message":{"content":[{"type":"tool_use","id":"toolu_001","name":"Bash"}]}}
+{"type":"user","timestamp":4000,"message":{"content":[{"type":"tool_result","tool_use_id":"toolu_001","is_error":true}]}}
+{"type":"assistant","timestamp":5000,"message":{"content":[{"type":"tool_use","id":"toolu_002","name":"Bash"}]}}
+{"type":"user","timestamp":5500,"message":{"content":[{"type":"tool_result","tool_use_id":"toolu_002"}]}}
+{"type":"assistant","timestamp":6000,"message":{"content":[{"type":"tool_use","id":"toolu_003","name":"Read"}]}}
+`
+	path := writeTempFile(t, content)
+	defer os.Remove(path)
+
+	data := Parse(path)
+	if len(data.Calls) != 3 {
+		t.Fatalf("expected 3 calls, got %d", len(data.Calls))
+	}
+
+	first := data.Calls[0]
+	if !first.Done || !first.IsError || first.DurationMs() != 3000 {
+		t.Errorf("unexpected first call: %+v (duration %d)", first, first.DurationMs())
+	}
+	if data.Calls[1].DurationMs() != 500 {
+		t.Errorf("expected 500ms for second call, got %d", data.Calls[1].DurationMs())
+	}
+	if data.Calls[2].Done || data.Calls[2].DurationMs() != 0 {
+		t.Errorf("expected running Read call with no duration, got %+v", data.Calls[2])
+	}
+
+	if data.Tools[0].Errors != 1 {
+		t.Errorf("expected Bash Errors=1, got %d", data.Tools[0].Errors)
+	}
+
+	bash := data.StatsFor("Bash")
+	if bash == nil {
+		t.Fatal("expected Bash stats")
+	}
+	if bash.Count != 2 || bash.Errors != 1 {
+		t.Errorf("expected Count=2 Errors=1, got Count=%d Errors=%d", bash.Count, bash.Errors)
+	}
+	if bash.Slowest.ID != "toolu_001" {
+		t.Errorf("expected slowest call toolu_001, got %s", bash.Slowest.ID)
+	}
+	if data.StatsFor("Read") != nil {
+		t.Error("expected no stats for running-only Read tool")
+	}
+}
+
+func TestParse_FilesTouched(t *testing.T) {
+	lines := []string{
+		`{"type":"assistant","timestamp":1000,"message":{"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/repo/a.go"}},{"type":"tool_use","id":"t2","name":"Read","input":{"file_path":"/repo/b.go"}}]}}`,
+		`{"type":"user","timestamp":2000,"message":{"content":[{"type":"tool_result","tool_use_id":"t1"},{"type":"tool_result","tool_use_id":"t2"}]}}`,
+		`{"type":"assistant","timestamp":3000,"message":{"content":[{"type":"tool_use","id":"t3","name":"Edit","input":{"file_path":"/repo/a.go"}}]}}`,
+		`{"type":"user","timestamp":4000,"message":{"content":[{"type":"tool_result","tool_use_id":"t3"}]}}`,
+		`{"type":"assistant","timestamp":5000,"message":{"content":[{"type":"tool_use","id":"t4","name":"Write","input":{"file_path":"/repo/c.go"}}]}}`,
+		`{"type":"user","timestamp":6000,"message":{"content":[{"type":"tool_result","tool_use_id":"t4","is_error":true}]}}`,
+		`{"type":"assistant","timestamp":7000,"message":{"content":[{"type":"tool_use","id":"t5","name":"NotebookEdit","input":{"notebook_path":"/repo/n.ipynb"}}]}}`,
+		`{"type":"user","timestamp":8000,"message":{"content":[{"type":"tool_result","tool_use_id":"t5"}]}}`,
+	}
+
+	data := parseLines(lines)
+
+	if len(data.Files) != 3 {
+		t.Fatalf("expected 3 files (failed Write excluded), got %d: %+v", len(data.Files), data.Files)
+	}
+	read, modified := data.FileCounts()
+	if read != 2 || modified != 2 {
+		t.Errorf("FileCounts() = (%d, %d), want (2, 2)", read, modified)
+	}
+	if data.LastModifiedFile != "/repo/n.ipynb" {
+		t.Errorf("LastModifiedFile = %q, want /repo/n.ipynb", data.LastModifiedFile)
+	}
+	if data.Files[0].LastModified != 4000 {
+		t.Errorf("a.go LastModified = %d, want 4000", data.Files[0].LastModified)
+	}
+}
+
 func writeTempFile(t *testing.T, content string) string {
 	t.Helper()
 	dir := t.TempDir()
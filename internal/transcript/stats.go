@@ -0,0 +1,67 @@
+package transcript
+
+import "sort"
+
+// computeToolStats aggregates completed calls per tool name.
+// Stats are returned in the order each tool was first invoked.
+func computeToolStats(calls []ToolCall) []ToolStats {
+	statsMap := make(map[string]*ToolStats)
+	durations := make(map[string][]int64)
+	var order []string
+
+	for _, call := range calls {
+		if !call.Done {
+			continue
+		}
+
+		s, ok := statsMap[call.Name]
+		if !ok {
+			s = &ToolStats{Name: call.Name}
+			statsMap[call.Name] = s
+			order = append(order, call.Name)
+		}
+
+		s.Count++
+		if call.IsError {
+			s.Errors++
+		}
+
+		d := call.DurationMs()
+		if d <= 0 {
+			continue
+		}
+		durations[call.Name] = append(durations[call.Name], d)
+		// ">=" so that ties resolve to the most recent call
+		if d >= s.Slowest.DurationMs() {
+			s.Slowest = call
+		}
+	}
+
+	result := make([]ToolStats, 0, len(order))
+	for _, name := range order {
+		s := statsMap[name]
+		if ds := durations[name]; len(ds) > 0 {
+			sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+			s.P50Ms = percentile(ds, 50)
+			s.P95Ms = percentile(ds, 95)
+		}
+		result = append(result, *s)
+	}
+	return result
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []int64, p int) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	// Nearest-rank: ceil(p/100 * n), 1-based
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
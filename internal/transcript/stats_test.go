@@ -0,0 +1,63 @@
+package transcript
+
+import "testing"
+
+func TestComputeToolStats_Percentiles(t *testing.T) {
+	var calls []ToolCall
+	// 20 Read calls with durations 100ms..2000ms
+	for i := 1; i <= 20; i++ {
+		calls = append(calls, ToolCall{
+			ID:        "r" + string(rune('a'+i)),
+			Name:      "Read",
+			StartTime: 1000,
+			EndTime:   1000 + int64(i*100),
+			Done:      true,
+		})
+	}
+
+	stats := computeToolStats(calls)
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 stats entry, got %d", len(stats))
+	}
+	s := stats[0]
+	if s.P50Ms != 1000 {
+		t.Errorf("P50Ms = %d, want 1000", s.P50Ms)
+	}
+	if s.P95Ms != 1900 {
+		t.Errorf("P95Ms = %d, want 1900", s.P95Ms)
+	}
+	if s.Slowest.DurationMs() != 2000 {
+		t.Errorf("Slowest = %d, want 2000", s.Slowest.DurationMs())
+	}
+}
+
+func TestComputeToolStats_ErrorRate(t *testing.T) {
+	calls := []ToolCall{
+		{ID: "1", Name: "Bash", Done: true, IsError: true},
+		{ID: "2", Name: "Bash", Done: true},
+		{ID: "3", Name: "Bash", Done: true},
+		{ID: "4", Name: "Bash", Done: true},
+		{ID: "5", Name: "Bash"}, // still running, excluded
+	}
+
+	stats := computeToolStats(calls)
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 stats entry, got %d", len(stats))
+	}
+	if stats[0].Count != 4 {
+		t.Errorf("Count = %d, want 4", stats[0].Count)
+	}
+	if stats[0].ErrorRate() != 25 {
+		t.Errorf("ErrorRate = %v, want 25", stats[0].ErrorRate())
+	}
+	// No timestamps: latency stays unknown
+	if stats[0].P50Ms != 0 || stats[0].P95Ms != 0 {
+		t.Errorf("expected zero latency without timestamps, got p50=%d p95=%d", stats[0].P50Ms, stats[0].P95Ms)
+	}
+}
+
+func TestPercentile_Empty(t *testing.T) {
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile(nil) = %d, want 0", got)
+	}
+}
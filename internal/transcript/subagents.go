@@ -0,0 +1,95 @@
+package transcript
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxAgentDepth bounds how deep nested sub-agents are resolved.
+// Guards against cycles and runaway recursion in malformed transcripts.
+const maxAgentDepth = 5
+
+// maxSidechainLineSize is the largest sidechain JSONL line we accept.
+const maxSidechainLineSize = 4 * 1024 * 1024
+
+// sidechainPath returns the transcript path for a sub-agent.
+// Claude Code stores sidechains next to the session transcript:
+// <session>.jsonl -> <session>/subagents/agent-<agentId>.jsonl
+func sidechainPath(transcriptPath, agentID string) string {
+	dir := strings.TrimSuffix(transcriptPath, filepath.Ext(transcriptPath))
+	return filepath.Join(dir, "subagents", "agent-"+agentID+".jsonl")
+}
+
+// resolveSubagents fills usage, tool counts and nested children for each
+// agent whose sidechain transcript can be found. Nested agents are appended
+// to data.Agents with ParentID set.
+func resolveSubagents(data *Data, transcriptPath string, debug bool) {
+	if transcriptPath == "" {
+		return
+	}
+
+	seen := make(map[string]bool)
+	// Iterate by index: nested agents are appended while we walk the slice.
+	queue := make([]int, 0, len(data.Agents))
+	depth := make(map[string]int)
+	for i := range data.Agents {
+		queue = append(queue, i)
+	}
+
+	for len(queue) > 0 {
+		idx := queue[0]
+		queue = queue[1:]
+
+		agent := &data.Agents[idx]
+		if agent.AgentID == "" || seen[agent.AgentID] || depth[agent.ID] >= maxAgentDepth {
+			continue
+		}
+		seen[agent.AgentID] = true
+
+		lines, err := readAllLines(sidechainPath(transcriptPath, agent.AgentID))
+		if err != nil {
+			if debug && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "[transcript] sidechain %s: %v\n", agent.AgentID, err)
+			}
+			continue
+		}
+
+		side := parseLinesWithDebug(lines, false)
+		agent.Usage = side.Usage
+		agent.ToolCount = len(side.Calls)
+
+		parentID := agent.ID
+		parentDepth := depth[agent.ID]
+		for _, child := range side.Agents {
+			child.ParentID = parentID
+			data.Agents = append(data.Agents, child)
+			// Re-fetch: append may have reallocated the slice.
+			data.Agents[idx].Children = append(data.Agents[idx].Children, child.ID)
+			depth[child.ID] = parentDepth + 1
+			queue = append(queue, len(data.Agents)-1)
+		}
+	}
+}
+
+// readAllLines reads every non-empty line of a file.
+// Sidechain transcripts are bounded by a single agent run, so they are read whole.
+func readAllLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSidechainLineSize)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,114 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSidechainPath(t *testing.T) {
+	got := sidechainPath("/p/proj/abc-123.jsonl", "a1b2")
+	want := filepath.Join("/p/proj/abc-123", "subagents", "agent-a1b2.jsonl")
+	if got != want {
+		t.Errorf("sidechainPath() = %s, want %s", got, want)
+	}
+}
+
+func TestParse_SubagentUsageAndNesting(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "session.jsonl")
+	subDir := filepath.Join(dir, "session", "subagents")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeLines := func(path string, lines ...string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Main session: one Task linked by a progress entry, one linked by toolUseResult.agentId
+	writeLines(mainPath,
+		`{"type":"assistant","timestamp":1000,"message":{"content":[{"type":"tool_use","id":"task_1","name":"Task","input":{"subagent_type":"Explore","description":"Scan repo"}}]}}`,
+		`{"type":"progress","toolUseID":"task_1","data":{"agentId":"aaa"}}`,
+		`{"type":"user","timestamp":9000,"message":{"content":[{"type":"tool_result","tool_use_id":"task_1"}]}}`,
+		`{"type":"assistant","timestamp":10000,"message":{"content":[{"type":"tool_use","id":"task_2","name":"Task","input":{"subagent_type":"Plan"}}]}}`,
+		`{"type":"user","timestamp":12000,"toolUseResult":{"agentId":"ccc"},"message":{"content":[{"type":"tool_result","tool_use_id":"task_2"}]}}`,
+	)
+
+	// Explore agent: two assistant entries sharing a message ID (usage counted once),
+	// two tool calls, and a nested Task.
+	writeLines(filepath.Join(subDir, "agent-aaa.jsonl"),
+		`{"type":"assistant","message":{"id":"msg_1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":1000,"output_tokens":500},"content":[{"type":"tool_use","id":"s1","name":"Grep"}]}}`,
+		`{"type":"assistant","message":{"id":"msg_1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":1000,"output_tokens":500},"content":[{"type":"tool_use","id":"s2","name":"Task","input":{"subagent_type":"general-purpose"}}]}}`,
+		`{"type":"progress","toolUseID":"s2","data":{"agentId":"bbb"}}`,
+	)
+	writeLines(filepath.Join(subDir, "agent-bbb.jsonl"),
+		`{"type":"assistant","message":{"id":"msg_9","model":"claude-sonnet-4-20250514","usage":{"input_tokens":200,"output_tokens":100},"content":[{"type":"tool_use","id":"n1","name":"Read"}]}}`,
+	)
+	writeLines(filepath.Join(subDir, "agent-ccc.jsonl"),
+		`{"type":"assistant","message":{"id":"msg_5","usage":{"input_tokens":10,"output_tokens":5},"content":"done"}}`,
+	)
+
+	data := Parse(mainPath)
+
+	if len(data.Agents) != 3 {
+		t.Fatalf("expected 3 agents (2 top-level + 1 nested), got %d", len(data.Agents))
+	}
+	if len(data.TopLevelAgents()) != 2 {
+		t.Errorf("expected 2 top-level agents, got %d", len(data.TopLevelAgents()))
+	}
+
+	explore := data.Agent("task_1")
+	if explore.AgentID != "aaa" {
+		t.Errorf("Explore AgentID = %q, want aaa", explore.AgentID)
+	}
+	if explore.Usage.InputTokens != 1000 || explore.Usage.OutputTokens != 500 {
+		t.Errorf("Explore usage = %+v, want 1000 in / 500 out", explore.Usage)
+	}
+	if explore.Usage.CostUSD <= 0 {
+		t.Errorf("expected positive Explore cost, got %v", explore.Usage.CostUSD)
+	}
+	if explore.ToolCount != 2 {
+		t.Errorf("Explore ToolCount = %d, want 2", explore.ToolCount)
+	}
+	if len(explore.Children) != 1 || explore.Children[0] != "s2" {
+		t.Fatalf("Explore children = %v, want [s2]", explore.Children)
+	}
+
+	nested := data.Agent("s2")
+	if nested.ParentID != "task_1" || nested.Type != "general-purpose" {
+		t.Errorf("unexpected nested agent: %+v", nested)
+	}
+	if nested.ToolCount != 1 {
+		t.Errorf("nested ToolCount = %d, want 1", nested.ToolCount)
+	}
+
+	tree := data.TreeUsage("task_1")
+	if tree.InputTokens != 1200 || tree.OutputTokens != 600 {
+		t.Errorf("TreeUsage = %+v, want 1200 in / 600 out", tree)
+	}
+
+	plan := data.Agent("task_2")
+	if plan.AgentID != "ccc" || plan.Usage.TotalTokens() != 15 {
+		t.Errorf("Plan agent = %+v, want AgentID ccc with 15 tokens", plan)
+	}
+}
+
+func TestParse_SubagentMissingSidechain(t *testing.T) {
+	content := `{"type":"assistant","message":{"content":[{"type":"tool_use","id":"task_1","name":"Task","input":{"subagent_type":"Explore"}}]}}
+{"type":"progress","toolUseID":"task_1","data":{"agentId":"missing"}}
+`
+	path := writeTempFile(t, content)
+
+	data := Parse(path)
+	if len(data.Agents) != 1 {
+		t.Fatalf("expected 1 agent, got %d", len(data.Agents))
+	}
+	if data.Agents[0].AgentID != "missing" || data.Agents[0].Usage.TotalTokens() != 0 {
+		t.Errorf("unexpected agent: %+v", data.Agents[0])
+	}
+}
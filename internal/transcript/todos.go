@@ -0,0 +1,162 @@
+package transcript
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// todoWriteItem is a single entry of the TodoWrite tool's "todos" input.
+type todoWriteItem struct {
+	Content    string `json:"content"`
+	Status     string `json:"status"`
+	ActiveForm string `json:"activeForm"`
+	ID         string `json:"id"`
+}
+
+// taskIDRegex extracts the task ID from a TaskCreate result ("Task #3 created successfully").
+var taskIDRegex = regexp.MustCompile(`#(\w+)`)
+
+// processTodoTool handles the tools that manage the todo list.
+//   - TaskCreate/TaskUpdate create and update one task at a time.
+//   - TodoWrite (older Claude Code versions) replaces the whole list.
+func (p *parser) processTodoTool(block *contentBlock, ts int64) {
+	switch block.Name {
+	case "TaskCreate":
+		if block.Input.Subject == "" {
+			return
+		}
+		// TaskCreate uses tool_use ID as the todo ID until the result carries the real ID
+		todo := &Todo{
+			ID:         block.ID,
+			Subject:    block.Input.Subject,
+			ActiveForm: block.Input.ActiveForm,
+			Status:     TodoPending,
+		}
+		p.todoMap[block.ID] = todo
+		p.todoOrder = append(p.todoOrder, block.ID)
+		p.pendingTasks[block.ID] = todo
+
+	case "TaskUpdate":
+		if block.Input.TaskID == "" {
+			return
+		}
+		// Find the todo by its real task ID and update status
+		for _, todo := range p.todoMap {
+			if todo.ID == block.Input.TaskID {
+				if block.Input.Status != "" {
+					setTodoStatus(todo, TodoStatus(block.Input.Status), ts)
+				}
+				if block.Input.Subject != "" {
+					todo.Subject = block.Input.Subject
+				}
+				if block.Input.ActiveForm != "" {
+					todo.ActiveForm = block.Input.ActiveForm
+				}
+				break
+			}
+		}
+
+	case "TodoWrite":
+		if block.Input.Todos == nil {
+			return
+		}
+		p.applyTodoWrite(block.Input.Todos, ts)
+	}
+}
+
+// applyTodoWrite replaces the todo list with a TodoWrite snapshot.
+// Tasks keep their StartedAt across snapshots when matched by subject,
+// so elapsed time reflects when the task first went in progress.
+func (p *parser) applyTodoWrite(items []todoWriteItem, ts int64) {
+	previous := make(map[string]*Todo, len(p.todoMap))
+	for _, todo := range p.todoMap {
+		previous[todo.Subject] = todo
+	}
+
+	p.todoMap = make(map[string]*Todo, len(items))
+	p.todoOrder = p.todoOrder[:0]
+
+	for i, item := range items {
+		if item.Content == "" {
+			continue
+		}
+		id := item.ID
+		if id == "" {
+			id = "todo-" + strconv.Itoa(i+1)
+		}
+		todo := &Todo{
+			ID:         id,
+			Subject:    item.Content,
+			ActiveForm: item.ActiveForm,
+			Status:     TodoPending,
+		}
+		if prev, ok := previous[item.Content]; ok {
+			todo.Status = prev.Status
+			todo.StartedAt = prev.StartedAt
+		}
+		setTodoStatus(todo, TodoStatus(item.Status), ts)
+
+		if _, dup := p.todoMap[id]; dup {
+			continue
+		}
+		p.todoMap[id] = todo
+		p.todoOrder = append(p.todoOrder, id)
+	}
+}
+
+// processTodoResult assigns the real task ID from a TaskCreate tool_result.
+func (p *parser) processTodoResult(block *contentBlock) {
+	todo, ok := p.pendingTasks[block.ToolUseID]
+	if !ok {
+		return
+	}
+	delete(p.pendingTasks, block.ToolUseID)
+
+	if m := taskIDRegex.FindStringSubmatch(resultText(block.Content)); m != nil {
+		todo.ID = m[1]
+	}
+}
+
+// setTodoStatus updates a todo's status, recording when it went in progress.
+func setTodoStatus(todo *Todo, status TodoStatus, ts int64) {
+	if status == "" {
+		return
+	}
+	if status == TodoInProgress && (todo.Status != TodoInProgress || todo.StartedAt == 0) {
+		todo.StartedAt = ts
+	}
+	if status != TodoInProgress {
+		todo.StartedAt = 0
+	}
+	todo.Status = status
+}
+
+// resultText returns the text of a tool_result content, which can be a plain
+// string or a list of {"type":"text","text":...} blocks.
+func resultText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return ""
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,78 @@
+package transcript
+
+import "testing"
+
+func TestParse_TaskCreateAndUpdate(t *testing.T) {
+	lines := []string{
+		`{"type":"assistant","timestamp":1000,"message":{"content":[{"type":"tool_use","id":"t1","name":"TaskCreate","input":{"subject":"Write parser","activeForm":"Writing parser"}}]}}`,
+		`{"type":"user","timestamp":1100,"message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"Task #1 created successfully: Write parser"}]}}`,
+		`{"type":"assistant","timestamp":2000,"message":{"content":[{"type":"tool_use","id":"t2","name":"TaskCreate","input":{"subject":"Add tests"}}]}}`,
+		`{"type":"user","timestamp":2100,"message":{"content":[{"type":"tool_result","tool_use_id":"t2","content":[{"type":"text","text":"Task #2 created successfully: Add tests"}]}]}}`,
+		`{"type":"assistant","timestamp":3000,"message":{"content":[{"type":"tool_use","id":"t3","name":"TaskUpdate","input":{"taskId":"1","status":"in_progress"}}]}}`,
+	}
+
+	data := parseLines(lines)
+
+	if len(data.Todos) != 2 {
+		t.Fatalf("expected 2 todos, got %d", len(data.Todos))
+	}
+	if data.Todos[0].ID != "1" || data.Todos[1].ID != "2" {
+		t.Errorf("expected real task IDs 1 and 2, got %q and %q", data.Todos[0].ID, data.Todos[1].ID)
+	}
+	if data.Todos[0].Status != TodoInProgress || data.Todos[0].StartedAt != 3000 {
+		t.Errorf("expected first todo in progress since 3000, got %+v", data.Todos[0])
+	}
+	if data.Todos[0].ActiveForm != "Writing parser" {
+		t.Errorf("ActiveForm = %q, want 'Writing parser'", data.Todos[0].ActiveForm)
+	}
+}
+
+func TestParse_TodoWrite(t *testing.T) {
+	lines := []string{
+		`{"type":"assistant","timestamp":1000,"message":{"content":[{"type":"tool_use","id":"w1","name":"TodoWrite","input":{"todos":[` +
+			`{"content":"Read code","status":"in_progress","activeForm":"Reading code"},` +
+			`{"content":"Fix bug","status":"pending","activeForm":"Fixing bug"},` +
+			`{"content":"Run tests","status":"pending","activeForm":"Running tests"}]}}]}}`,
+		`{"type":"assistant","timestamp":5000,"message":{"content":[{"type":"tool_use","id":"w2","name":"TodoWrite","input":{"todos":[` +
+			`{"content":"Read code","status":"completed","activeForm":"Reading code"},` +
+			`{"content":"Fix bug","status":"in_progress","activeForm":"Fixing bug"},` +
+			`{"content":"Run tests","status":"pending","activeForm":"Running tests"}]}}]}}`,
+		`{"type":"assistant","timestamp":9000,"message":{"content":[{"type":"tool_use","id":"w3","name":"TodoWrite","input":{"todos":[` +
+			`{"content":"Read code","status":"completed","activeForm":"Reading code"},` +
+			`{"content":"Fix bug","status":"in_progress","activeForm":"Fixing bug"},` +
+			`{"content":"Run tests","status":"pending","activeForm":"Running tests"},` +
+			`{"content":"Update docs","status":"pending","activeForm":"Updating docs"}]}}]}}`,
+	}
+
+	data := parseLines(lines)
+
+	if len(data.Todos) != 4 {
+		t.Fatalf("expected latest snapshot with 4 todos, got %d", len(data.Todos))
+	}
+	if data.Todos[0].Status != TodoCompleted || data.Todos[0].StartedAt != 0 {
+		t.Errorf("expected first todo completed, got %+v", data.Todos[0])
+	}
+	fix := data.Todos[1]
+	if fix.Status != TodoInProgress {
+		t.Errorf("expected 'Fix bug' in progress, got %s", fix.Status)
+	}
+	// Started when it first went in progress, not at the latest snapshot
+	if fix.StartedAt != 5000 {
+		t.Errorf("'Fix bug' StartedAt = %d, want 5000", fix.StartedAt)
+	}
+	if fix.ActiveForm != "Fixing bug" {
+		t.Errorf("ActiveForm = %q, want 'Fixing bug'", fix.ActiveForm)
+	}
+}
+
+func TestResultText(t *testing.T) {
+	if got := resultText([]byte(`"plain"`)); got != "plain" {
+		t.Errorf("resultText(string) = %q", got)
+	}
+	if got := resultText([]byte(`[{"type":"text","text":"a"},{"type":"image"},{"type":"text","text":"b"}]`)); got != "a\nb" {
+		t.Errorf("resultText(blocks) = %q", got)
+	}
+	if got := resultText(nil); got != "" {
+		t.Errorf("resultText(nil) = %q", got)
+	}
+}
@@ -16,6 +16,74 @@ type Tool struct {
 	Name   string
 	Status ToolStatus
 	Count  int // Number of times this tool was invoked
+	Errors int // Number of invocations whose tool_result had is_error=true
+}
+
+// ToolCall represents a single tool_use paired with its tool_result.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Target    string // What the call operates on (command, file path, pattern, URL), secrets redacted
+	StartTime int64  // Timestamp when tool_use was issued (ms)
+	EndTime   int64  // Timestamp when tool_result was received (ms)
+	Done      bool   // true once the matching tool_result was seen
+	IsError   bool
+
+	// Interrupted is true when a later turn began before the tool_result,
+	// e.g. the user pressed Esc; such calls never get a result.
+	Interrupted bool
+}
+
+// DurationMs returns the call duration in milliseconds.
+// Returns 0 if the call is still running or timestamps are missing.
+func (c ToolCall) DurationMs() int64 {
+	if !c.Done || c.StartTime == 0 || c.EndTime == 0 || c.EndTime < c.StartTime {
+		return 0
+	}
+	return c.EndTime - c.StartTime
+}
+
+// ToolStats aggregates completed ToolCalls for a single tool name.
+type ToolStats struct {
+	Name    string
+	Count   int      // Completed invocations (tool_result received)
+	Errors  int      // Completed invocations that returned an error
+	P50Ms   int64    // Median latency of timed invocations
+	P95Ms   int64    // 95th percentile latency of timed invocations
+	Slowest ToolCall // Slowest timed invocation in the parsed window
+}
+
+// ErrorRate returns the percentage of completed invocations that errored.
+func (s ToolStats) ErrorRate() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Count) * 100
+}
+
+// Usage holds token counts and estimated cost.
+type Usage struct {
+	InputTokens      int
+	OutputTokens     int
+	CacheReadTokens  int
+	CacheWriteTokens int
+	CostUSD          float64
+}
+
+// TotalTokens returns the sum of all token counts.
+func (u Usage) TotalTokens() int {
+	return u.InputTokens + u.OutputTokens + u.CacheReadTokens + u.CacheWriteTokens
+}
+
+// Add returns the sum of two usages.
+func (u Usage) Add(o Usage) Usage {
+	return Usage{
+		InputTokens:      u.InputTokens + o.InputTokens,
+		OutputTokens:     u.OutputTokens + o.OutputTokens,
+		CacheReadTokens:  u.CacheReadTokens + o.CacheReadTokens,
+		CacheWriteTokens: u.CacheWriteTokens + o.CacheWriteTokens,
+		CostUSD:          u.CostUSD + o.CostUSD,
+	}
 }
 
 // Agent represents a sub-agent spawned via the Task tool.
@@ -26,6 +94,21 @@ type Agent struct {
 	Description string // Task description from input
 	StartTime   int64  // Timestamp when tool_use was issued (ms)
 	EndTime     int64  // Timestamp when tool_result was received (ms)
+
+	// Sub-agent consumption, filled from the agent's sidechain transcript.
+	AgentID   string   // Sidechain agent ID (links the Task call to its transcript)
+	ParentID  string   // ID of the agent that spawned this one, "" for top-level agents
+	Children  []string // IDs of agents spawned by this agent
+	Usage     Usage    // Tokens and cost consumed by this agent itself (excluding children)
+	ToolCount int      // Tool invocations made by this agent itself
+}
+
+// FileAccess represents a file touched by Read, Edit, MultiEdit, Write or NotebookEdit.
+type FileAccess struct {
+	Path         string
+	Read         bool  // Successfully read via the Read tool
+	Modified     bool  // Successfully changed via Edit, MultiEdit, Write or NotebookEdit
+	LastModified int64 // Timestamp of the last successful modification (ms)
 }
 
 // TodoStatus represents the execution state of a todo task.
@@ -37,16 +120,101 @@ const (
 	TodoCompleted  TodoStatus = "completed"
 )
 
-// Todo represents a task created via TaskCreate/TaskUpdate tools.
+// Todo represents a task created via TaskCreate/TaskUpdate or TodoWrite tools.
 type Todo struct {
-	ID      string
-	Subject string
-	Status  TodoStatus
+	ID         string
+	Subject    string
+	ActiveForm string // Present-continuous form shown while in progress (e.g., "Running tests")
+	Status     TodoStatus
+	StartedAt  int64 // Timestamp when the task went in progress (ms), 0 if not in progress
 }
 
 // Data holds the parsed transcript information for widgets.
 type Data struct {
-	Tools  []Tool
-	Agents []Agent
-	Todos  []Todo
+	Tools     []Tool
+	Calls     []ToolCall  // Individual invocations in tool_use order
+	ToolStats []ToolStats // Per-tool latency/error aggregates, in first-seen order
+	Agents    []Agent
+	Todos     []Todo
+	Files     []FileAccess // Files touched this session, in first-touched order
+
+	// LastModifiedFile is the path of the most recently modified file, "" if none.
+	LastModifiedFile string
+
+	// Usage is the token usage of the assistant messages in the parsed lines.
+	Usage Usage
+}
+
+// Agent returns the agent with the given ID, or nil if not found.
+func (d *Data) Agent(id string) *Agent {
+	for i := range d.Agents {
+		if d.Agents[i].ID == id {
+			return &d.Agents[i]
+		}
+	}
+	return nil
+}
+
+// TopLevelAgents returns agents spawned directly by the main session.
+func (d *Data) TopLevelAgents() []Agent {
+	var result []Agent
+	for _, a := range d.Agents {
+		if a.ParentID == "" {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// TreeUsage returns the usage of an agent including all of its descendants.
+func (d *Data) TreeUsage(id string) Usage {
+	return d.treeUsage(id, 0)
+}
+
+func (d *Data) treeUsage(id string, depth int) Usage {
+	agent := d.Agent(id)
+	if agent == nil || depth > maxAgentDepth {
+		return Usage{}
+	}
+	total := agent.Usage
+	for _, child := range agent.Children {
+		total = total.Add(d.treeUsage(child, depth+1))
+	}
+	return total
+}
+
+// CurrentCall returns the most recently issued call that has not
+// received its tool_result yet, or nil if nothing is running.
+// Interrupted calls are not running.
+func (d *Data) CurrentCall() *ToolCall {
+	for i := len(d.Calls) - 1; i >= 0; i-- {
+		if !d.Calls[i].Done && !d.Calls[i].Interrupted {
+			return &d.Calls[i]
+		}
+	}
+	return nil
+}
+
+// FileCounts returns the number of files read and the number of files modified.
+// A file that was both read and modified counts toward both.
+func (d *Data) FileCounts() (read, modified int) {
+	for _, f := range d.Files {
+		if f.Read {
+			read++
+		}
+		if f.Modified {
+			modified++
+		}
+	}
+	return read, modified
+}
+
+// StatsFor returns the aggregated stats for a tool name, or nil if none.
+func (d *Data) StatsFor(name string) *ToolStats {
+	for i := range d.ToolStats {
+		if d.ToolStats[i].Name == name {
+			return &d.ToolStats[i]
+		}
+	}
+	return nil
 }
@@ -50,6 +50,7 @@ func SampleSession() *input.Session {
 // RenderPreview renders a preview of the current configuration
 func RenderPreview(cfg *config.Config) string {
 	session := SampleSession()
+	render.SetWidths(cfg.General.AmbiguousWidth, cfg.General.PUAWidth)
 
 	var lines []string
 	for _, line := range cfg.Lines {
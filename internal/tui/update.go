@@ -1,8 +1,6 @@
 package tui
 
 import (
-	"strconv"
-
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -461,7 +459,7 @@ func (m *Model) initEditInputs() {
 	m.editInputs = make([]textinput.Model, len(m.editWidgetMeta.Options))
 	for i, opt := range m.editWidgetMeta.Options {
 		ti := textinput.New()
-		ti.Placeholder = opt.DefaultValue
+		ti.Placeholder = opt.Default
 		ti.CharLimit = 20
 
 		// Get current value or default
@@ -471,7 +469,7 @@ func (m *Model) initEditInputs() {
 			}
 		}
 		if ti.Value() == "" {
-			ti.SetValue(opt.DefaultValue)
+			ti.SetValue(opt.Default)
 		}
 
 		if i == 0 {
@@ -495,12 +493,12 @@ func (m *Model) applyEditedOptions() {
 		if i < len(m.editInputs) {
 			value := m.editInputs[i].Value()
 
-			// Validate based on option type
-			if !m.validateOptionValue(value, opt.Type) {
+			// Validate against the widget's option schema (empty uses default)
+			if value != "" && opt.Validate(value) != nil {
 				continue // Skip invalid values
 			}
 
-			if value != opt.DefaultValue && value != "" {
+			if value != opt.Default && value != "" {
 				m.editWidget.Extra[opt.Key] = value
 			} else {
 				delete(m.editWidget.Extra, opt.Key)
@@ -515,24 +513,3 @@ func (m *Model) applyEditedOptions() {
 
 	m.markDirty()
 }
-
-// validateOptionValue validates the value based on the option type
-func (m *Model) validateOptionValue(value string, optType OptionType) bool {
-	if value == "" {
-		return true // Empty is always valid (uses default)
-	}
-
-	switch optType {
-	case OptionTypeBool:
-		return value == "true" || value == "false"
-	case OptionTypeInt:
-		_, err := strconv.Atoi(value)
-		return err == nil
-	case OptionTypeFloat:
-		_, err := strconv.ParseFloat(value, 64)
-		return err == nil
-	case OptionTypeString:
-		return true
-	}
-	return true
-}
@@ -223,7 +223,7 @@ func (m Model) viewEditOptions() string {
 			inputView = inputStyle.Render(m.editInputs[i].View())
 		}
 
-		desc := disabledStyle.Render("  " + opt.Description)
+		desc := disabledStyle.Render("  " + optionHelp(opt))
 
 		b.WriteString(label + inputView + desc + "\n")
 	}
@@ -1,247 +1,72 @@
 package tui
 
-// OptionType defines the type of a widget option
-type OptionType int
+import (
+	"strings"
 
-const (
-	OptionTypeBool OptionType = iota
-	OptionTypeInt
-	OptionTypeFloat
-	OptionTypeString
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/widgets"
 )
 
-// OptionDef defines metadata for a widget option
-type OptionDef struct {
-	Key          string
-	Type         OptionType
-	DefaultValue string
-	Description  string
-}
-
 // WidgetMeta contains metadata for a widget
 type WidgetMeta struct {
 	Name        string
 	Description string
-	Options     []OptionDef
+	Options     config.Options // Declared by the widget itself
+}
+
+// widgetDescriptions lists the widgets offered by the editor, in menu order.
+var widgetDescriptions = []struct{ name, description string }{
+	{"model", "Display model name (e.g., Opus)"},
+	{"context", "Context window usage with progress bar"},
+	{"context_spark", "Context history sparkline"},
+	{"compact_eta", "Estimated time until context full"},
+	{"block_timer", "Claude Pro rate limit block timer"},
+	{"cache_hit", "API cache hit rate"},
+	{"api_latency", "Average API response time"},
+	{"cost", "Session cost in USD"},
+	{"burn_rate", "Cost per minute"},
+	{"code_changes", "Lines added/removed in session"},
+	{"git", "Git branch and status"},
+	{"tools", "Active tool calls with usage count"},
+	{"current_tool", "In-flight tool call with target and elapsed time"},
+	{"files", "Files read/edited this session and last edited file"},
+	{"tool_stats", "Per-tool latency (p50/p95) and error rate"},
+	{"agents", "Active agent status with details"},
+	// v0.6 Cost tracking widgets
+	{"daily_cost", "Today's aggregated cost"},
+	{"weekly_cost", "This week's aggregated cost"},
+	{"block_cost", "Cost in current 5-hour block"},
+	// v0.6 Usage limit widgets
+	{"block_limit", "5-hour rate limit utilization"},
+	{"week_limit", "7-day rate limit utilization"},
+	{"limit_forecast", "When the 5h/7d limits will be hit at the current rate"},
+	{"auth_status", "OAuth token state and time to expiry"},
+	// v0.10 New widgets
+	{"duration", "Session duration (e.g., 5m, 1h23m)"},
+	{"token_speed", "Output token generation speed"},
+	{"plan", "Detected plan type (Pro, API, Bedrock)"},
+	{"todos", "Task progress from TaskCreate/TaskUpdate/TodoWrite"},
+	{"config_counts", "Claude config counts (CLAUDE.md, rules, MCPs, hooks)"},
+	{"session_id", "Current session ID"},
+	{"cwd", "Current working directory path"},
+	// v0.12 Statusline field widgets
+	{"cc_version", "Claude Code version"},
+	{"output_style", "Active output style"},
+	{"project_dir", "Working directory relative to the project"},
+	{"long_context", "Warning when context exceeds 200k tokens"},
 }
 
 // AllWidgets returns metadata for all available widgets
 func AllWidgets() []WidgetMeta {
-	return []WidgetMeta{
-		{
-			Name:        "model",
-			Description: "Display model name (e.g., Opus)",
-			Options:     nil,
-		},
-		{
-			Name:        "context",
-			Description: "Context window usage with progress bar",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "true", Description: "Show 'Ctx:' prefix"},
-				{Key: "show_bar", Type: OptionTypeBool, DefaultValue: "true", Description: "Show progress bar"},
-				{Key: "bar_width", Type: OptionTypeInt, DefaultValue: "10", Description: "Progress bar width"},
-				{Key: "warn_threshold", Type: OptionTypeInt, DefaultValue: "60", Description: "Warning threshold %"},
-				{Key: "critical_threshold", Type: OptionTypeInt, DefaultValue: "80", Description: "Critical threshold %"},
-			},
-		},
-		{
-			Name:        "context_spark",
-			Description: "Context history sparkline",
-			Options: []OptionDef{
-				{Key: "width", Type: OptionTypeInt, DefaultValue: "8", Description: "Sparkline width"},
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'Ctx:' prefix"},
-			},
-		},
-		{
-			Name:        "compact_eta",
-			Description: "Estimated time until context full",
-			Options: []OptionDef{
-				{Key: "show_when_above", Type: OptionTypeInt, DefaultValue: "40", Description: "Show only above this %"},
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'ETA:' prefix"},
-			},
-		},
-		{
-			Name:        "block_timer",
-			Description: "Claude Pro rate limit block timer",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "true", Description: "Show 'Block:' prefix"},
-				{Key: "warn_threshold", Type: OptionTypeInt, DefaultValue: "80", Description: "Warning at % elapsed"},
-				{Key: "critical_threshold", Type: OptionTypeInt, DefaultValue: "95", Description: "Critical at % elapsed"},
-			},
-		},
-		{
-			Name:        "cache_hit",
-			Description: "API cache hit rate",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'Cache:' prefix"},
-				{Key: "good_threshold", Type: OptionTypeInt, DefaultValue: "80", Description: "Good/green threshold %"},
-				{Key: "warn_threshold", Type: OptionTypeInt, DefaultValue: "50", Description: "Warning threshold %"},
-			},
-		},
-		{
-			Name:        "api_latency",
-			Description: "Average API response time",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'Latency:' prefix"},
-				{Key: "warn_threshold", Type: OptionTypeInt, DefaultValue: "2000", Description: "Warning threshold ms"},
-				{Key: "critical_threshold", Type: OptionTypeInt, DefaultValue: "5000", Description: "Critical threshold ms"},
-			},
-		},
-		{
-			Name:        "cost",
-			Description: "Session cost in USD",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'Cost:' prefix"},
-				{Key: "warn_threshold", Type: OptionTypeFloat, DefaultValue: "0.5", Description: "Warning threshold USD"},
-				{Key: "critical_threshold", Type: OptionTypeFloat, DefaultValue: "1.0", Description: "Critical threshold USD"},
-			},
-		},
-		{
-			Name:        "burn_rate",
-			Description: "Cost per minute",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'Burn:' prefix"},
-				{Key: "warn_threshold", Type: OptionTypeInt, DefaultValue: "10", Description: "Warning threshold cents/min"},
-				{Key: "critical_threshold", Type: OptionTypeInt, DefaultValue: "25", Description: "Critical threshold cents/min"},
-			},
-		},
-		{
-			Name:        "code_changes",
-			Description: "Lines added/removed in session",
-			Options:     nil,
-		},
-		{
-			Name:        "git",
-			Description: "Git branch and status",
-			Options:     nil,
-		},
-		{
-			Name:        "tools",
-			Description: "Active tool calls with usage count",
-			Options: []OptionDef{
-				{Key: "max_display", Type: OptionTypeInt, DefaultValue: "3", Description: "Max tools to display"},
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'Tools:' prefix"},
-				{Key: "show_count", Type: OptionTypeBool, DefaultValue: "true", Description: "Show invocation count"},
-			},
-		},
-		{
-			Name:        "agents",
-			Description: "Active agent status with details",
-			Options: []OptionDef{
-				{Key: "max_display", Type: OptionTypeInt, DefaultValue: "2", Description: "Max agents to display"},
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'Agents:' prefix"},
-				{Key: "show_description", Type: OptionTypeBool, DefaultValue: "true", Description: "Show task description"},
-				{Key: "show_duration", Type: OptionTypeBool, DefaultValue: "true", Description: "Show elapsed time"},
-				{Key: "max_description_len", Type: OptionTypeInt, DefaultValue: "15", Description: "Max description length"},
-			},
-		},
-		// v0.6 Cost tracking widgets
-		{
-			Name:        "daily_cost",
-			Description: "Today's aggregated cost",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'Today:' prefix"},
-				{Key: "warn_threshold", Type: OptionTypeFloat, DefaultValue: "5.0", Description: "Warning threshold USD"},
-				{Key: "critical_threshold", Type: OptionTypeFloat, DefaultValue: "10.0", Description: "Critical threshold USD"},
-			},
-		},
-		{
-			Name:        "weekly_cost",
-			Description: "This week's aggregated cost",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'Week:' prefix"},
-				{Key: "warn_threshold", Type: OptionTypeFloat, DefaultValue: "25.0", Description: "Warning threshold USD"},
-				{Key: "critical_threshold", Type: OptionTypeFloat, DefaultValue: "50.0", Description: "Critical threshold USD"},
-			},
-		},
-		{
-			Name:        "block_cost",
-			Description: "Cost in current 5-hour block",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'Block$:' prefix"},
-				{Key: "warn_threshold", Type: OptionTypeFloat, DefaultValue: "2.0", Description: "Warning threshold USD"},
-				{Key: "critical_threshold", Type: OptionTypeFloat, DefaultValue: "5.0", Description: "Critical threshold USD"},
-			},
-		},
-		// v0.6 Usage limit widgets
-		{
-			Name:        "block_limit",
-			Description: "5-hour rate limit utilization",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "true", Description: "Show '5h:' prefix"},
-				{Key: "show_remaining", Type: OptionTypeBool, DefaultValue: "true", Description: "Show remaining time"},
-				{Key: "warn_threshold", Type: OptionTypeInt, DefaultValue: "70", Description: "Warning threshold %"},
-				{Key: "critical_threshold", Type: OptionTypeInt, DefaultValue: "90", Description: "Critical threshold %"},
-			},
-		},
-		{
-			Name:        "week_limit",
-			Description: "7-day rate limit utilization",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "true", Description: "Show '7d:' prefix"},
-				{Key: "show_remaining", Type: OptionTypeBool, DefaultValue: "false", Description: "Show remaining time"},
-				{Key: "warn_threshold", Type: OptionTypeInt, DefaultValue: "70", Description: "Warning threshold %"},
-				{Key: "critical_threshold", Type: OptionTypeInt, DefaultValue: "90", Description: "Critical threshold %"},
-			},
-		},
-		// v0.10 New widgets
-		{
-			Name:        "duration",
-			Description: "Session duration (e.g., 5m, 1h23m)",
-			Options: []OptionDef{
-				{Key: "show_icon", Type: OptionTypeBool, DefaultValue: "true", Description: "Show ⏱️ icon prefix"},
-			},
-		},
-		{
-			Name:        "token_speed",
-			Description: "Output token generation speed",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'out:' prefix"},
-				{Key: "warn_threshold", Type: OptionTypeInt, DefaultValue: "20", Description: "Warning below tok/s"},
-				{Key: "critical_threshold", Type: OptionTypeInt, DefaultValue: "10", Description: "Critical below tok/s"},
-			},
-		},
-		{
-			Name:        "plan",
-			Description: "Detected plan type (Pro, API, Bedrock)",
-			Options:     nil,
-		},
-		{
-			Name:        "todos",
-			Description: "Task progress from TaskCreate/TaskUpdate",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'Tasks:' prefix"},
-				{Key: "max_subject_len", Type: OptionTypeInt, DefaultValue: "30", Description: "Max task subject length"},
-			},
-		},
-		{
-			Name:        "config_counts",
-			Description: "Claude config counts (CLAUDE.md, rules, MCPs, hooks)",
-			Options: []OptionDef{
-				{Key: "show_claude_md", Type: OptionTypeBool, DefaultValue: "true", Description: "Show CLAUDE.md count"},
-				{Key: "show_rules", Type: OptionTypeBool, DefaultValue: "true", Description: "Show permission rules count"},
-				{Key: "show_mcps", Type: OptionTypeBool, DefaultValue: "true", Description: "Show MCP plugins count"},
-				{Key: "show_hooks", Type: OptionTypeBool, DefaultValue: "true", Description: "Show hooks count"},
-			},
-		},
-		{
-			Name:        "session_id",
-			Description: "Current session ID",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'Session:' prefix"},
-				{Key: "max_length", Type: OptionTypeInt, DefaultValue: "0", Description: "Max ID length (0 = full)"},
-			},
-		},
-		{
-			Name:        "cwd",
-			Description: "Current working directory path",
-			Options: []OptionDef{
-				{Key: "show_label", Type: OptionTypeBool, DefaultValue: "false", Description: "Show 'CWD:' prefix"},
-				{Key: "show_basename", Type: OptionTypeBool, DefaultValue: "false", Description: "Show only directory name"},
-				{Key: "max_length", Type: OptionTypeInt, DefaultValue: "0", Description: "Max path length (0 = full)"},
-			},
-		},
+	metas := make([]WidgetMeta, 0, len(widgetDescriptions))
+	for _, d := range widgetDescriptions {
+		w, ok := widgets.Get(d.name)
+		if !ok {
+			continue
+		}
+		metas = append(metas, WidgetMeta{Name: d.name, Description: d.description, Options: w.Options()})
 	}
+	return metas
 }
 
 // GetWidgetMeta returns metadata for a specific widget
@@ -263,3 +88,14 @@ func AvailableWidgetNames() []string {
 	}
 	return names
 }
+
+// optionHelp describes an option with its allowed values or range.
+func optionHelp(opt config.Option) string {
+	switch {
+	case opt.Enum != nil:
+		return opt.Description + " (" + strings.Join(opt.Enum, "|") + ")"
+	case opt.Range() != "":
+		return opt.Description + " (" + opt.Range() + ")"
+	}
+	return opt.Description
+}
@@ -1,7 +1,6 @@
 package usage
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,9 +12,9 @@ import (
 const (
 	// API endpoints
 	// NOTE: This is the assumed endpoint for Claude Pro usage data.
-	// The actual endpoint and response structure should be verified
-	// against the official Claude API documentation when available.
-	// This implementation is based on common OAuth API patterns.
+	// The response is decoded by decodeLimits, which accepts every known
+	// schema version, so a changed shape fails loudly (ErrUnknownSchema)
+	// and other LimitsSources take over.
 	baseURL = "https://api.claude.ai"
 
 	// Default timeout for API requests
@@ -24,8 +23,9 @@ const (
 
 // Client is an OAuth API client for Claude Pro usage data.
 type Client struct {
-	httpClient *http.Client
+	httpClient   *http.Client
 	authProvider auth.CredentialProvider
+	baseURL      string
 }
 
 // NewClient creates a new usage API client.
@@ -39,6 +39,7 @@ func NewClient(provider auth.CredentialProvider) *Client {
 			Timeout: defaultTimeout,
 		},
 		authProvider: provider,
+		baseURL:      baseURL,
 	}
 }
 
@@ -46,6 +47,16 @@ func NewClient(provider auth.CredentialProvider) *Client {
 type Limits struct {
 	FiveHour FiveHourLimit `json:"fiveHour"`
 	SevenDay SevenDayLimit `json:"sevenDay"`
+
+	// FetchedAt is when the limits were fetched from the API.
+	// Zero for locally estimated limits.
+	FetchedAt time.Time `json:"-"`
+
+	// Stale is true when the limits come from a cache older than its TTL.
+	Stale bool `json:"-"`
+
+	// Source names the LimitsSource that produced these limits.
+	Source string `json:"-"`
 }
 
 // FiveHourLimit represents the 5-hour rate limit.
@@ -64,22 +75,6 @@ type SevenDayLimit struct {
 	Total       int       `json:"total,omitempty"`
 }
 
-// apiResponse represents the raw API response.
-type apiResponse struct {
-	FiveHourBlock struct {
-		UtilizationPct float64 `json:"utilization_pct"`
-		ResetsAt       string  `json:"resets_at"`
-		MessagesLeft   int     `json:"messages_left,omitempty"`
-		TotalMessages  int     `json:"total_messages,omitempty"`
-	} `json:"five_hour_block"`
-	SevenDayBlock struct {
-		UtilizationPct float64 `json:"utilization_pct"`
-		ResetsAt       string  `json:"resets_at"`
-		MessagesLeft   int     `json:"messages_left,omitempty"`
-		TotalMessages  int     `json:"total_messages,omitempty"`
-	} `json:"seven_day_block"`
-}
-
 // GetLimits retrieves current usage limits from the API.
 func (c *Client) GetLimits() (*Limits, error) {
 	creds, err := c.authProvider.Get()
@@ -87,7 +82,7 @@ func (c *Client) GetLimits() (*Limits, error) {
 		return nil, fmt.Errorf("failed to get credentials: %w", err)
 	}
 
-	req, err := http.NewRequest("GET", baseURL+"/api/organizations/default/usage", nil)
+	req, err := http.NewRequest("GET", c.baseURL+"/api/organizations/default/usage", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -111,39 +106,13 @@ func (c *Client) GetLimits() (*Limits, error) {
 		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
 	}
 
-	var apiResp apiResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return c.parseResponse(&apiResp)
-}
-
-// parseResponse converts the API response to our Limits type.
-func (c *Client) parseResponse(resp *apiResponse) (*Limits, error) {
-	limits := &Limits{}
-
-	// Parse 5-hour block
-	limits.FiveHour.Utilization = resp.FiveHourBlock.UtilizationPct
-	limits.FiveHour.Remaining = resp.FiveHourBlock.MessagesLeft
-	limits.FiveHour.Total = resp.FiveHourBlock.TotalMessages
-	if resp.FiveHourBlock.ResetsAt != "" {
-		if t, err := time.Parse(time.RFC3339, resp.FiveHourBlock.ResetsAt); err == nil {
-			limits.FiveHour.ResetsAt = t
-		}
-	}
-
-	// Parse 7-day block
-	limits.SevenDay.Utilization = resp.SevenDayBlock.UtilizationPct
-	limits.SevenDay.Remaining = resp.SevenDayBlock.MessagesLeft
-	limits.SevenDay.Total = resp.SevenDayBlock.TotalMessages
-	if resp.SevenDayBlock.ResetsAt != "" {
-		if t, err := time.Parse(time.RFC3339, resp.SevenDayBlock.ResetsAt); err == nil {
-			limits.SevenDay.ResetsAt = t
-		}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	return limits, nil
+	limits, _, err := decodeLimits(body)
+	return limits, err
 }
 
 // FiveHourRemaining returns the remaining time in the 5-hour block.
@@ -1,6 +1,9 @@
 package usage
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -72,3 +75,45 @@ func TestLimitsZeroTime(t *testing.T) {
 		t.Errorf("FiveHourRemaining() for zero time = %v, want 0", remaining)
 	}
 }
+
+func TestClientGetLimits_Schemas(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want float64
+	}{
+		{"v1", `{"five_hour":{"utilization":42},"seven_day":{"utilization":69}}`, 42},
+		{"v0", `{"five_hour_block":{"utilization_pct":55},"seven_day_block":{"utilization_pct":12}}`, 55},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client := NewClient(staticProvider{})
+			client.baseURL = srv.URL
+			limits, err := client.GetLimits()
+			if err != nil {
+				t.Fatalf("GetLimits() error = %v", err)
+			}
+			if limits.FiveHour.Utilization != tt.want {
+				t.Errorf("FiveHour.Utilization = %.0f, want %.0f", limits.FiveHour.Utilization, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientGetLimits_UnknownSchema(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"something":"else"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(staticProvider{})
+	client.baseURL = srv.URL
+	if _, err := client.GetLimits(); !errors.Is(err, ErrUnknownSchema) {
+		t.Errorf("GetLimits() error = %v, want ErrUnknownSchema", err)
+	}
+}
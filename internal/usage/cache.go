@@ -0,0 +1,248 @@
+package usage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheTTL is how long fetched limits are considered fresh.
+	DefaultCacheTTL = 2 * time.Minute
+
+	// Backoff bounds after failed fetches (doubles per consecutive failure).
+	minBackoff = 30 * time.Second
+	maxBackoff = 15 * time.Minute
+
+	// refreshLockTimeout bounds how long another process's in-flight refresh
+	// blocks new refreshes. Longer than a token refresh plus the HTTP
+	// timeout so a crashed process cannot hold the lock forever.
+	refreshLockTimeout = 3 * defaultTimeout
+
+	cacheFileName = "usage_limits.json"
+)
+
+// ErrNoCachedLimits is returned when no limits have been fetched yet.
+var ErrNoCachedLimits = errors.New("no cached usage limits")
+
+// limitsGetter fetches limits from the network. Implemented by *Client.
+type limitsGetter interface {
+	GetLimits() (*Limits, error)
+}
+
+// cacheState is the on-disk cache format.
+type cacheState struct {
+	Limits          *Limits `json:"limits,omitempty"`
+	FetchedAt       int64   `json:"fetched_at,omitempty"`       // Unix ms of last successful fetch
+	Failures        int     `json:"failures,omitempty"`         // Consecutive failed fetches
+	NextAttemptAt   int64   `json:"next_attempt_at,omitempty"`  // Unix ms; no fetch before this
+	RefreshingSince int64   `json:"refreshing_since,omitempty"` // Unix ms; set while a refresh runs
+	LastError       string  `json:"last_error,omitempty"`
+}
+
+// CachedFetcher serves usage limits from a disk cache and refreshes them
+// in the background (stale-while-revalidate).
+//
+// The statusline is invoked as a short-lived process on every render, so the
+// cache is shared between processes through a JSON file. A refresh starts
+// once the cached data is older than half the TTL, so fresh data is normally
+// in place before the TTL expires. Failed fetches back off exponentially.
+type CachedFetcher struct {
+	// Spawn, when set, runs the refresh in another process (see
+	// DetachedRefresh) that calls Refresh, instead of in a goroutine. The
+	// statusline process then exits without waiting on the network.
+	Spawn func() error
+
+	client limitsGetter
+	path   string
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu         sync.Mutex
+	wg         sync.WaitGroup
+	refreshErr error
+}
+
+// NewCachedFetcher creates a fetcher that caches limits from client under dir.
+// If dir is empty, CacheDirFunc is used. If ttl is 0, DefaultCacheTTL is used.
+func NewCachedFetcher(client *Client, dir string, ttl time.Duration) *CachedFetcher {
+	if dir == "" {
+		dir = CacheDirFunc()
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &CachedFetcher{
+		client: client,
+		path:   filepath.Join(dir, cacheFileName),
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Get returns the cached limits immediately and starts a background refresh
+// when the cache is due for one. Limits older than the TTL are marked Stale.
+// Returns ErrNoCachedLimits if nothing has been fetched yet.
+func (f *CachedFetcher) Get() (*Limits, error) {
+	state := f.load()
+	now := f.now()
+
+	if f.shouldRefresh(state, now) {
+		f.startRefresh(state, now)
+	}
+
+	if state.Limits == nil {
+		return nil, ErrNoCachedLimits
+	}
+
+	limits := *state.Limits
+	limits.FetchedAt = time.UnixMilli(state.FetchedAt)
+	limits.Stale = now.Sub(limits.FetchedAt) > f.ttl
+	return &limits, nil
+}
+
+// Wait blocks until any background refresh started by Get has finished,
+// and returns its error. With Spawn set it returns at once with the spawn
+// error, if any.
+func (f *CachedFetcher) Wait() error {
+	f.wg.Wait()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.refreshErr
+}
+
+// shouldRefresh reports whether a new fetch should be started.
+func (f *CachedFetcher) shouldRefresh(state cacheState, now time.Time) bool {
+	nowMs := now.UnixMilli()
+
+	// Another process is already fetching
+	if state.RefreshingSince > 0 && nowMs-state.RefreshingSince < refreshLockTimeout.Milliseconds() {
+		return false
+	}
+
+	// Backing off after failures
+	if nowMs < state.NextAttemptAt {
+		return false
+	}
+
+	if state.Limits == nil {
+		return true
+	}
+	return nowMs-state.FetchedAt >= (f.ttl / 2).Milliseconds()
+}
+
+// startRefresh marks the cache as refreshing and fetches in a goroutine,
+// or hands the fetch to Spawn.
+func (f *CachedFetcher) startRefresh(state cacheState, now time.Time) {
+	state.RefreshingSince = now.UnixMilli()
+	_ = f.save(state)
+
+	if f.Spawn != nil {
+		if err := f.Spawn(); err != nil {
+			// Release the lock so the next render can retry
+			state.RefreshingSince = 0
+			_ = f.save(state)
+			f.refreshErr = err
+		}
+		return
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		err := f.refresh()
+		f.mu.Lock()
+		f.refreshErr = err
+		f.mu.Unlock()
+	}()
+}
+
+// Refresh fetches limits now and stores them in the cache, releasing the
+// refresh lock taken by Get. It is the entry point of a spawned refresh.
+func (f *CachedFetcher) Refresh() error {
+	return f.refresh()
+}
+
+// refresh fetches limits and records the result (or failure) in the cache.
+func (f *CachedFetcher) refresh() error {
+	limits, err := f.client.GetLimits()
+
+	// Reload so a concurrent writer's result is not discarded
+	state := f.load()
+	state.RefreshingSince = 0
+	now := f.now()
+
+	if err != nil {
+		state.Failures++
+		state.NextAttemptAt = now.Add(backoffFor(state.Failures)).UnixMilli()
+		state.LastError = err.Error()
+		if saveErr := f.save(state); saveErr != nil {
+			return saveErr
+		}
+		return err
+	}
+
+	state.Limits = limits
+	state.FetchedAt = now.UnixMilli()
+	state.Failures = 0
+	state.NextAttemptAt = 0
+	state.LastError = ""
+	return f.save(state)
+}
+
+// backoffFor returns the retry delay after n consecutive failures.
+func backoffFor(failures int) time.Duration {
+	d := minBackoff
+	for i := 1; i < failures; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
+
+// load reads the cache file. Missing or corrupted files yield an empty state.
+func (f *CachedFetcher) load() cacheState {
+	var state cacheState
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return cacheState{}
+	}
+	return state
+}
+
+// save writes the cache file atomically.
+func (f *CachedFetcher) save(state cacheState) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmpPath := f.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, f.path)
+}
+
+// CacheDirFunc is the function used to get the cache directory.
+// Can be overridden in tests.
+var CacheDirFunc = defaultCacheDir
+
+// defaultCacheDir returns ~/.cache/visor, shared with session history.
+func defaultCacheDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "/tmp"
+	}
+	return filepath.Join(home, ".cache", "visor")
+}
@@ -0,0 +1,271 @@
+package usage
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/namyoungkim/visor/internal/auth"
+)
+
+type staticProvider struct{}
+
+func (staticProvider) Get() (*auth.Credentials, error) {
+	return &auth.Credentials{AccessToken: "test-token"}, nil
+}
+
+// newStubFetcher returns a fetcher backed by an httptest server that responds
+// with status, counting requests in hits.
+func newStubFetcher(t *testing.T, status int, hits *int32) *CachedFetcher {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			w.Write([]byte(`{"five_hour_block":{"utilization_pct":42},"seven_day_block":{"utilization_pct":69}}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewClient(staticProvider{})
+	client.baseURL = srv.URL
+	return NewCachedFetcher(client, t.TempDir(), time.Minute)
+}
+
+func TestCachedFetcher_ColdStartRefreshesInBackground(t *testing.T) {
+	var hits int32
+	f := newStubFetcher(t, http.StatusOK, &hits)
+
+	if _, err := f.Get(); err != ErrNoCachedLimits {
+		t.Fatalf("Get() on empty cache error = %v, want ErrNoCachedLimits", err)
+	}
+	if err := f.Wait(); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	limits, err := f.Get()
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if limits.FiveHour.Utilization != 42 || limits.SevenDay.Utilization != 69 {
+		t.Errorf("limits = %+v, want 42/69", limits)
+	}
+	if limits.Stale {
+		t.Error("freshly fetched limits should not be stale")
+	}
+	f.Wait()
+	if hits != 1 {
+		t.Errorf("server hits = %d, want 1 (fresh cache must not refetch)", hits)
+	}
+}
+
+func TestCachedFetcher_ServesStaleWhileRevalidating(t *testing.T) {
+	var hits int32
+	f := newStubFetcher(t, http.StatusOK, &hits)
+
+	// Seed the cache with an old value
+	old := f.now().Add(-5 * time.Minute)
+	f.save(cacheState{
+		Limits:    &Limits{FiveHour: FiveHourLimit{Utilization: 10}},
+		FetchedAt: old.UnixMilli(),
+	})
+
+	limits, err := f.Get()
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if limits.FiveHour.Utilization != 10 {
+		t.Errorf("Get() should serve cached value immediately, got %.0f", limits.FiveHour.Utilization)
+	}
+	if !limits.Stale {
+		t.Error("limits older than TTL should be marked stale")
+	}
+
+	f.Wait()
+	limits, _ = f.Get()
+	if limits.FiveHour.Utilization != 42 || limits.Stale {
+		t.Errorf("after refresh got %.0f stale=%v, want 42 fresh", limits.FiveHour.Utilization, limits.Stale)
+	}
+}
+
+func TestCachedFetcher_RefreshAhead(t *testing.T) {
+	var hits int32
+	f := newStubFetcher(t, http.StatusOK, &hits)
+
+	// Past half the TTL but not yet expired: served fresh, refreshed anyway
+	f.save(cacheState{
+		Limits:    &Limits{FiveHour: FiveHourLimit{Utilization: 10}},
+		FetchedAt: f.now().Add(-40 * time.Second).UnixMilli(),
+	})
+
+	limits, _ := f.Get()
+	if limits.Stale {
+		t.Error("limits within TTL should not be stale")
+	}
+	f.Wait()
+	if hits != 1 {
+		t.Errorf("server hits = %d, want 1", hits)
+	}
+}
+
+func TestCachedFetcher_BackoffAfterFailure(t *testing.T) {
+	var hits int32
+	f := newStubFetcher(t, http.StatusInternalServerError, &hits)
+
+	f.Get()
+	if err := f.Wait(); err == nil {
+		t.Fatal("Wait() should report the failed refresh")
+	}
+
+	state := f.load()
+	if state.Failures != 1 {
+		t.Errorf("Failures = %d, want 1", state.Failures)
+	}
+	if state.RefreshingSince != 0 {
+		t.Error("refresh lock should be released after failure")
+	}
+
+	// Within the backoff window no request is made
+	f.Get()
+	f.Wait()
+	if hits != 1 {
+		t.Errorf("server hits = %d, want 1 during backoff", hits)
+	}
+
+	// After the window a retry happens and backoff doubles
+	f.now = func() time.Time { return time.Now().Add(minBackoff + time.Second) }
+	f.Get()
+	f.Wait()
+	if hits != 2 {
+		t.Errorf("server hits = %d, want 2 after backoff", hits)
+	}
+	if got := f.load().Failures; got != 2 {
+		t.Errorf("Failures = %d, want 2", got)
+	}
+}
+
+func TestCachedFetcher_SkipsWhileAnotherProcessRefreshes(t *testing.T) {
+	var hits int32
+	f := newStubFetcher(t, http.StatusOK, &hits)
+
+	f.save(cacheState{RefreshingSince: f.now().UnixMilli()})
+	f.Get()
+	f.Wait()
+	if hits != 0 {
+		t.Errorf("server hits = %d, want 0 while another refresh is in flight", hits)
+	}
+
+	// An abandoned lock expires
+	f.now = func() time.Time { return time.Now().Add(refreshLockTimeout + time.Second) }
+	f.Get()
+	f.Wait()
+	if hits != 1 {
+		t.Errorf("server hits = %d, want 1 after lock timeout", hits)
+	}
+}
+
+// TestRefreshChild is the detached refresh process started by
+// TestCachedFetcher_SpawnDoesNotWaitOnNetwork.
+func TestRefreshChild(t *testing.T) {
+	url := os.Getenv("VISOR_TEST_REFRESH_URL")
+	if url == "" {
+		t.Skip("helper process")
+	}
+	client := NewClient(staticProvider{})
+	client.baseURL = url
+	if err := NewCachedFetcher(client, os.Getenv("VISOR_TEST_REFRESH_DIR"), time.Minute).Refresh(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func TestCachedFetcher_SpawnDoesNotWaitOnNetwork(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(4 * time.Second):
+		}
+		w.Write([]byte(`{"five_hour_block":{"utilization_pct":42},"seven_day_block":{"utilization_pct":69}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	t.Setenv("VISOR_TEST_REFRESH_URL", srv.URL)
+	t.Setenv("VISOR_TEST_REFRESH_DIR", dir)
+
+	client := NewClient(staticProvider{})
+	client.baseURL = srv.URL
+	f := NewCachedFetcher(client, dir, time.Minute)
+	f.Spawn = DetachedRefresh("-test.run=^TestRefreshChild$")
+
+	// What the statusline does before exiting
+	start := time.Now()
+	if _, err := f.Get(); !errors.Is(err, ErrNoCachedLimits) {
+		t.Fatalf("Get() error = %v, want ErrNoCachedLimits", err)
+	}
+	if err := f.Wait(); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Get() and Wait() took %v, want no wait on the slow server", elapsed)
+	}
+	if f.load().RefreshingSince == 0 {
+		t.Error("refresh lock should be held while the child fetches")
+	}
+
+	// The child finishes the fetch on its own
+	close(release)
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if state := f.load(); state.Limits != nil {
+			if state.Limits.FiveHour.Utilization != 42 || state.RefreshingSince != 0 {
+				t.Errorf("child stored %+v, want 42 with the lock released", state)
+			}
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("detached refresh never stored limits")
+}
+
+func TestCachedFetcher_SpawnFailureReleasesLock(t *testing.T) {
+	var hits int32
+	f := newStubFetcher(t, http.StatusOK, &hits)
+	f.Spawn = func() error { return errors.New("no exec") }
+
+	f.Get()
+	if err := f.Wait(); err == nil {
+		t.Error("Wait() should report the spawn error")
+	}
+	if f.load().RefreshingSince != 0 {
+		t.Error("refresh lock should be released when the spawn fails")
+	}
+	if hits != 0 {
+		t.Errorf("server hits = %d, want 0 (no in-process fallback)", hits)
+	}
+}
+
+func TestBackoffFor(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{10, maxBackoff},
+	}
+	for _, tt := range tests {
+		if got := backoffFor(tt.failures); got != tt.want {
+			t.Errorf("backoffFor(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
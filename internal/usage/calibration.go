@@ -0,0 +1,143 @@
+package usage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/namyoungkim/visor/internal/cost"
+)
+
+const (
+	calibrationFileName = "limit_calibration.json"
+
+	// maxObservations bounds how many limit hits are kept per window.
+	// Older hits are dropped so limits follow plan or policy changes.
+	maxObservations = 10
+)
+
+// Observation is usage measured when a limit was hit.
+type Observation struct {
+	At       int64   `json:"at"` // Unix seconds of the limit hit
+	Tier     string  `json:"tier,omitempty"`
+	Messages int     `json:"messages"`
+	Weighted float64 `json:"weighted"`
+}
+
+// LearnedLimit is a per-user limit derived from observed limit hits.
+type LearnedLimit struct {
+	Messages int     // Median messages at a hit
+	Weighted float64 // Median weighted usage at a hit
+	Samples  int
+}
+
+// Calibration stores observed limit hits and derives per-user limits from them.
+type Calibration struct {
+	FiveHour []Observation `json:"five_hour,omitempty"`
+	SevenDay []Observation `json:"seven_day,omitempty"`
+
+	path string
+}
+
+// LoadCalibration loads calibration data from dir (CacheDirFunc if empty).
+// A missing or corrupt file yields an empty calibration.
+func LoadCalibration(dir string) *Calibration {
+	if dir == "" {
+		dir = CacheDirFunc()
+	}
+	c := &Calibration{path: filepath.Join(dir, calibrationFileName)}
+	if data, err := os.ReadFile(c.path); err == nil {
+		if err := json.Unmarshal(data, c); err != nil {
+			c.FiveHour, c.SevenDay = nil, nil
+		}
+	}
+	return c
+}
+
+// Record adds limit hits not seen before, tagged with tier.
+// Returns true if anything was added.
+func (c *Calibration) Record(hits []cost.LimitHit, tier string) bool {
+	changed := false
+	for _, h := range hits {
+		if h.Messages == 0 && h.Weighted == 0 {
+			continue
+		}
+		obs := Observation{At: h.At.Unix(), Tier: tier, Messages: h.Messages, Weighted: h.Weighted}
+		switch h.Window {
+		case cost.LimitWindowFiveHour:
+			c.FiveHour, changed = addObservation(c.FiveHour, obs, changed)
+		case cost.LimitWindowSevenDay:
+			c.SevenDay, changed = addObservation(c.SevenDay, obs, changed)
+		}
+	}
+	return changed
+}
+
+// addObservation appends obs unless already present, keeping the newest maxObservations.
+func addObservation(list []Observation, obs Observation, changed bool) ([]Observation, bool) {
+	for _, o := range list {
+		if o.At == obs.At {
+			return list, changed
+		}
+	}
+	list = append(list, obs)
+	sort.Slice(list, func(i, j int) bool { return list[i].At < list[j].At })
+	if len(list) > maxObservations {
+		list = list[len(list)-maxObservations:]
+	}
+	return list, true
+}
+
+// FiveHourLimit returns the learned 5-hour limit for tier.
+func (c *Calibration) FiveHourLimit(tier string) (LearnedLimit, bool) {
+	return learn(c.FiveHour, tier)
+}
+
+// SevenDayLimit returns the learned 7-day limit for tier.
+func (c *Calibration) SevenDayLimit(tier string) (LearnedLimit, bool) {
+	return learn(c.SevenDay, tier)
+}
+
+// learn takes the median of observations for tier. The median keeps one
+// unusual block (e.g. a hit right after a plan change) from skewing the limit.
+func learn(list []Observation, tier string) (LearnedLimit, bool) {
+	var messages []int
+	var weighted []float64
+	for _, o := range list {
+		if o.Tier != tier {
+			continue
+		}
+		messages = append(messages, o.Messages)
+		weighted = append(weighted, o.Weighted)
+	}
+	if len(messages) == 0 {
+		return LearnedLimit{}, false
+	}
+
+	sort.Ints(messages)
+	sort.Float64s(weighted)
+	mid := len(messages) / 2
+	learned := LearnedLimit{Messages: messages[mid], Weighted: weighted[mid], Samples: len(messages)}
+	if len(messages)%2 == 0 {
+		learned.Messages = (messages[mid-1] + messages[mid]) / 2
+		learned.Weighted = (weighted[mid-1] + weighted[mid]) / 2
+	}
+	return learned, true
+}
+
+// Save writes the calibration file atomically.
+func (c *Calibration) Save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	tmpPath := c.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, c.path)
+}
@@ -0,0 +1,99 @@
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/namyoungkim/visor/internal/cost"
+)
+
+func TestCalibration_RecordAndLearn(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	c := LoadCalibration(dir)
+	hits := []cost.LimitHit{
+		{At: base, Window: cost.LimitWindowFiveHour, Messages: 40, Weighted: 9000},
+		{At: base.Add(24 * time.Hour), Window: cost.LimitWindowFiveHour, Messages: 50, Weighted: 10000},
+		{At: base.Add(48 * time.Hour), Window: cost.LimitWindowFiveHour, Messages: 90, Weighted: 30000},
+		{At: base.Add(72 * time.Hour), Window: cost.LimitWindowSevenDay, Messages: 600, Weighted: 200000},
+	}
+	if !c.Record(hits, "pro") {
+		t.Fatal("Record() = false, want true for new hits")
+	}
+	if c.Record(hits, "pro") {
+		t.Error("Record() = true for already recorded hits, want false")
+	}
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded := LoadCalibration(dir)
+	five, ok := loaded.FiveHourLimit("pro")
+	if !ok {
+		t.Fatal("FiveHourLimit() not learned")
+	}
+	// Median of three: the 90-message outlier does not skew the limit
+	if five.Messages != 50 || five.Weighted != 10000 || five.Samples != 3 {
+		t.Errorf("FiveHourLimit() = %+v, want median 50 messages / 10000 weighted from 3 samples", five)
+	}
+	if seven, ok := loaded.SevenDayLimit("pro"); !ok || seven.Messages != 600 {
+		t.Errorf("SevenDayLimit() = %+v, %v, want 600 messages", seven, ok)
+	}
+
+	// Hits from another tier do not apply
+	if _, ok := loaded.FiveHourLimit("default_claude_max_5x"); ok {
+		t.Error("FiveHourLimit() learned for a tier without hits")
+	}
+}
+
+func TestCalibration_KeepsNewest(t *testing.T) {
+	c := LoadCalibration(t.TempDir())
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < maxObservations+5; i++ {
+		c.Record([]cost.LimitHit{{At: base.Add(time.Duration(i) * 24 * time.Hour), Window: cost.LimitWindowFiveHour, Messages: i + 1}}, "")
+	}
+	if len(c.FiveHour) != maxObservations {
+		t.Fatalf("kept %d observations, want %d", len(c.FiveHour), maxObservations)
+	}
+	if c.FiveHour[0].Messages != 6 {
+		t.Errorf("oldest kept observation has %d messages, want 6", c.FiveHour[0].Messages)
+	}
+}
+
+func TestEstimateLimitsCalibrated(t *testing.T) {
+	blockStart := time.Now().Add(-time.Hour)
+	costData := &cost.CostData{
+		FiveHourBlockMessages: 10,
+		FiveHourBlockWeighted: 5000,
+		WeekMessages:          100,
+		WeekWeighted:          50000,
+	}
+
+	c := LoadCalibration(t.TempDir())
+	c.Record([]cost.LimitHit{
+		{At: time.Now().Add(-48 * time.Hour), Window: cost.LimitWindowFiveHour, Messages: 40, Weighted: 20000},
+	}, "pro")
+
+	limits := EstimateLimitsCalibrated(costData, blockStart, "pro", 0, 0, c)
+
+	// Weighted: 5000/20000 = 25%, regardless of the message count
+	if limits.FiveHour.Utilization != 25 {
+		t.Errorf("FiveHour.Utilization = %v, want 25", limits.FiveHour.Utilization)
+	}
+	if limits.FiveHour.Total != 40 || limits.FiveHour.Remaining != 30 {
+		t.Errorf("FiveHour Total/Remaining = %d/%d, want 40/30", limits.FiveHour.Total, limits.FiveHour.Remaining)
+	}
+
+	// No weekly hit yet: tier default
+	if limits.SevenDay.Total != 675 {
+		t.Errorf("SevenDay.Total = %d, want tier default 675", limits.SevenDay.Total)
+	}
+
+	// An explicit limit still wins over the learned one
+	limits = EstimateLimitsCalibrated(costData, blockStart, "pro", 20, 0, c)
+	if limits.FiveHour.Total != 20 || limits.FiveHour.Utilization != 50 {
+		t.Errorf("FiveHour with explicit limit = %d total, %v%%, want 20 total, 50%%",
+			limits.FiveHour.Total, limits.FiveHour.Utilization)
+	}
+}
@@ -0,0 +1,77 @@
+package usage
+
+import (
+	"time"
+
+	"github.com/namyoungkim/visor/internal/cost"
+)
+
+// Limit window lengths, used to find where a window started from its reset time.
+const (
+	FiveHourWindow = cost.BlockDuration
+	SevenDayWindow = 7 * 24 * time.Hour
+)
+
+// Forecast projects a limit window forward at a usage rate.
+type Forecast struct {
+	Utilization float64       // Current utilization (0-100%)
+	RatePerHour float64       // Utilization gained per hour (%)
+	ResetsIn    time.Duration // Until the window resets
+	HitsIn      time.Duration // Until 100% at RatePerHour; 0 if already hit or no usage
+	AtReset     float64       // Projected utilization at reset (may exceed 100)
+}
+
+// Reached reports whether the limit is already used up.
+func (f Forecast) Reached() bool {
+	return f.Utilization >= 100
+}
+
+// WillHit reports whether the limit is reached before the window resets.
+func (f Forecast) WillHit() bool {
+	return f.Reached() || (f.RatePerHour > 0 && f.HitsIn < f.ResetsIn)
+}
+
+// ForecastLimit projects utilization until resetsAt for a window of the given
+// length. The rate is taken from recent usage when recentShare is known: the
+// fraction (0-1) of the window's usage that happened in the last recentWindow.
+// With recentShare < 0, the average rate since the window started is used.
+// Returns false if there is nothing to forecast (no reset time or usage).
+func ForecastLimit(utilization float64, resetsAt time.Time, window time.Duration, recentShare float64, recentWindow time.Duration, now time.Time) (Forecast, bool) {
+	if resetsAt.IsZero() || utilization <= 0 {
+		return Forecast{}, false
+	}
+	resetsIn := resetsAt.Sub(now)
+	if resetsIn <= 0 {
+		return Forecast{}, false
+	}
+
+	elapsed := window - resetsIn
+	if elapsed <= 0 {
+		return Forecast{}, false
+	}
+
+	f := Forecast{Utilization: utilization, ResetsIn: resetsIn, AtReset: utilization}
+	if recentShare >= 0 {
+		// A window younger than recentWindow holds all of its usage in less time
+		span := min(recentWindow, elapsed)
+		f.RatePerHour = utilization * min(recentShare, 1) / span.Hours()
+	} else {
+		f.RatePerHour = utilization / elapsed.Hours()
+	}
+
+	if f.RatePerHour > 0 {
+		f.AtReset = utilization + f.RatePerHour*resetsIn.Hours()
+		if !f.Reached() {
+			f.HitsIn = time.Duration((100 - utilization) / f.RatePerHour * float64(time.Hour))
+		}
+	}
+	return f, true
+}
+
+// RecentShare returns recent/total, or -1 if total is unknown (no local usage).
+func RecentShare(recent, total float64) float64 {
+	if total <= 0 {
+		return -1
+	}
+	return recent / total
+}
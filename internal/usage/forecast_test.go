@@ -0,0 +1,90 @@
+package usage
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestForecastLimit(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		util        float64
+		resetsIn    time.Duration
+		recentShare float64
+		wantOK      bool
+		wantRate    float64
+		wantHitsIn  time.Duration
+		wantWillHit bool
+	}{
+		{
+			// 2h elapsed at 40%: 20%/h, 60% left → 3h, resets in 3h → exactly at reset
+			name: "average rate", util: 40, resetsIn: 3 * time.Hour, recentShare: -1,
+			wantOK: true, wantRate: 20, wantHitsIn: 3 * time.Hour, wantWillHit: false,
+		},
+		{
+			// Half of 40% in the last 30m: 40%/h, 60% left → 1h30m before the 3h reset
+			name: "recent rate", util: 40, resetsIn: 3 * time.Hour, recentShare: 0.5,
+			wantOK: true, wantRate: 40, wantHitsIn: 90 * time.Minute, wantWillHit: true,
+		},
+		{
+			name: "idle recently", util: 40, resetsIn: 3 * time.Hour, recentShare: 0,
+			wantOK: true, wantRate: 0, wantHitsIn: 0, wantWillHit: false,
+		},
+		{
+			name: "already reached", util: 100, resetsIn: time.Hour, recentShare: -1,
+			wantOK: true, wantRate: 25, wantHitsIn: 0, wantWillHit: true,
+		},
+		{
+			name: "no usage", util: 0, resetsIn: time.Hour, recentShare: -1,
+		},
+		{
+			name: "already reset", util: 40, resetsIn: -time.Minute, recentShare: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, ok := ForecastLimit(tt.util, now.Add(tt.resetsIn), FiveHourWindow, tt.recentShare, 30*time.Minute, now)
+			if ok != tt.wantOK {
+				t.Fatalf("ForecastLimit() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if math.Abs(f.RatePerHour-tt.wantRate) > 0.001 {
+				t.Errorf("RatePerHour = %v, want %v", f.RatePerHour, tt.wantRate)
+			}
+			if (f.HitsIn - tt.wantHitsIn).Abs() > time.Second {
+				t.Errorf("HitsIn = %v, want %v", f.HitsIn, tt.wantHitsIn)
+			}
+			if f.WillHit() != tt.wantWillHit {
+				t.Errorf("WillHit() = %v, want %v", f.WillHit(), tt.wantWillHit)
+			}
+		})
+	}
+}
+
+func TestForecastLimit_YoungWindow(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	// Window started 10m ago with all usage recent: rate is over 10m, not 30m
+	f, ok := ForecastLimit(10, now.Add(FiveHourWindow-10*time.Minute), FiveHourWindow, 1, 30*time.Minute, now)
+	if !ok {
+		t.Fatal("ForecastLimit() ok = false")
+	}
+	if math.Abs(f.RatePerHour-60) > 0.001 {
+		t.Errorf("RatePerHour = %v, want 60", f.RatePerHour)
+	}
+}
+
+func TestRecentShare(t *testing.T) {
+	if got := RecentShare(25, 100); got != 0.25 {
+		t.Errorf("RecentShare(25, 100) = %v, want 0.25", got)
+	}
+	if got := RecentShare(0, 0); got != -1 {
+		t.Errorf("RecentShare(0, 0) = %v, want -1", got)
+	}
+}
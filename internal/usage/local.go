@@ -31,40 +31,28 @@ func DefaultLimitForTier(tier string) (int, int) {
 // If fiveHourLimit or sevenDayLimit is 0, they are auto-detected from the tier.
 // If tier is also empty, Pro defaults are used.
 func EstimateLimits(costData *cost.CostData, blockStart time.Time, tier string, fiveHourLimit, sevenDayLimit int) *Limits {
+	return EstimateLimitsCalibrated(costData, blockStart, tier, fiveHourLimit, sevenDayLimit, nil)
+}
+
+// EstimateLimitsCalibrated is EstimateLimits with limits learned from past
+// limit hits. For each window, an explicit limit wins, then a learned limit,
+// then the tier default. With a learned limit, utilization compares weighted
+// usage (model and token volume) rather than raw message counts.
+func EstimateLimitsCalibrated(costData *cost.CostData, blockStart time.Time, tier string, fiveHourLimit, sevenDayLimit int, calib *Calibration) *Limits {
 	if costData == nil {
 		return nil
 	}
 
-	// Auto-detect limits from tier if not specified
-	if fiveHourLimit == 0 || sevenDayLimit == 0 {
-		autoFive, autoSeven := DefaultLimitForTier(tier)
-		if fiveHourLimit == 0 {
-			fiveHourLimit = autoFive
-		}
-		if sevenDayLimit == 0 {
-			sevenDayLimit = autoSeven
-		}
-	}
-
-	now := time.Now()
-
-	// 5-hour block utilization
-	fiveHourUtil := float64(0)
-	if fiveHourLimit > 0 {
-		fiveHourUtil = float64(costData.FiveHourBlockMessages) / float64(fiveHourLimit) * 100
-		if fiveHourUtil > 100 {
-			fiveHourUtil = 100
-		}
-	}
+	autoFive, autoSeven := DefaultLimitForTier(tier)
 
-	// 7-day utilization
-	sevenDayUtil := float64(0)
-	if sevenDayLimit > 0 {
-		sevenDayUtil = float64(costData.WeekMessages) / float64(sevenDayLimit) * 100
-		if sevenDayUtil > 100 {
-			sevenDayUtil = 100
-		}
+	five := windowEstimate{limit: fiveHourLimit, messages: costData.FiveHourBlockMessages, weighted: costData.FiveHourBlockWeighted}
+	seven := windowEstimate{limit: sevenDayLimit, messages: costData.WeekMessages, weighted: costData.WeekWeighted}
+	if calib != nil {
+		five.learned, five.hasLearned = calib.FiveHourLimit(tier)
+		seven.learned, seven.hasLearned = calib.SevenDayLimit(tier)
 	}
+	fiveHourUtil, fiveHourTotal, fiveHourRemaining := five.estimate(autoFive)
+	sevenDayUtil, sevenDayTotal, sevenDayRemaining := seven.estimate(autoSeven)
 
 	// Calculate reset times
 	var fiveHourReset time.Time
@@ -72,30 +60,53 @@ func EstimateLimits(costData *cost.CostData, blockStart time.Time, tier string,
 		fiveHourReset = blockStart.Add(cost.BlockDuration)
 	}
 
-	weekStart := cost.StartOfWeek(now)
+	weekStart := cost.StartOfWeek(time.Now())
 	sevenDayReset := weekStart.Add(7 * 24 * time.Hour)
 
-	fiveHourRemaining := fiveHourLimit - costData.FiveHourBlockMessages
-	if fiveHourRemaining < 0 {
-		fiveHourRemaining = 0
-	}
-	sevenDayRemaining := sevenDayLimit - costData.WeekMessages
-	if sevenDayRemaining < 0 {
-		sevenDayRemaining = 0
-	}
-
 	return &Limits{
 		FiveHour: FiveHourLimit{
 			Utilization: fiveHourUtil,
 			ResetsAt:    fiveHourReset,
 			Remaining:   fiveHourRemaining,
-			Total:       fiveHourLimit,
+			Total:       fiveHourTotal,
 		},
 		SevenDay: SevenDayLimit{
 			Utilization: sevenDayUtil,
 			ResetsAt:    sevenDayReset,
 			Remaining:   sevenDayRemaining,
-			Total:       sevenDayLimit,
+			Total:       sevenDayTotal,
 		},
 	}
 }
+
+// windowEstimate holds the inputs for estimating one limit window.
+type windowEstimate struct {
+	limit      int // Explicitly configured message limit (0 = unset)
+	messages   int
+	weighted   float64
+	learned    LearnedLimit
+	hasLearned bool
+}
+
+// estimate returns utilization (0-100), total and remaining messages.
+func (w windowEstimate) estimate(tierDefault int) (float64, int, int) {
+	if w.limit == 0 && w.hasLearned && w.learned.Weighted > 0 {
+		util := min(w.weighted/w.learned.Weighted*100, 100)
+		remaining := int(float64(w.learned.Messages) * (1 - util/100))
+		return util, w.learned.Messages, remaining
+	}
+
+	total := w.limit
+	if total == 0 && w.hasLearned && w.learned.Messages > 0 {
+		total = w.learned.Messages
+	}
+	if total == 0 {
+		total = tierDefault
+	}
+
+	util := float64(0)
+	if total > 0 {
+		util = min(float64(w.messages)/float64(total)*100, 100)
+	}
+	return util, total, max(total-w.messages, 0)
+}
@@ -0,0 +1,133 @@
+package usage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Response schema versions understood by decodeLimits.
+const (
+	// SchemaV0 is the originally assumed shape:
+	// {"five_hour_block": {"utilization_pct": 42, "resets_at": "..."}, ...}
+	SchemaV0 = "v0"
+
+	// SchemaV1 matches the OAuth usage endpoint and is the format for
+	// user-supplied files and commands:
+	// {"five_hour": {"utilization": 42, "resets_at": "..."}, "seven_day": {...}}
+	SchemaV1 = "v1"
+)
+
+// ErrUnknownSchema is returned when a response matches no known schema.
+var ErrUnknownSchema = errors.New("unrecognized usage limits schema")
+
+// limitsDocument is the union of all known response shapes.
+type limitsDocument struct {
+	// v1
+	FiveHour *windowV1 `json:"five_hour"`
+	SevenDay *windowV1 `json:"seven_day"`
+
+	// v0
+	FiveHourBlock *windowV0 `json:"five_hour_block"`
+	SevenDayBlock *windowV0 `json:"seven_day_block"`
+}
+
+// windowV1 is a rate limit window in SchemaV1.
+type windowV1 struct {
+	Utilization float64         `json:"utilization"` // 0-100%
+	ResetsAt    json.RawMessage `json:"resets_at"`   // RFC3339 string or Unix seconds
+	Remaining   int             `json:"remaining,omitempty"`
+	Total       int             `json:"total,omitempty"`
+}
+
+// windowV0 is a rate limit window in SchemaV0.
+type windowV0 struct {
+	UtilizationPct float64 `json:"utilization_pct"`
+	ResetsAt       string  `json:"resets_at"`
+	MessagesLeft   int     `json:"messages_left,omitempty"`
+	TotalMessages  int     `json:"total_messages,omitempty"`
+}
+
+// decodeLimits parses a usage limits document and reports which schema matched.
+func decodeLimits(data []byte) (*Limits, string, error) {
+	var doc limitsDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	limits := &Limits{}
+	switch {
+	case doc.FiveHour != nil || doc.SevenDay != nil:
+		if w := doc.FiveHour; w != nil {
+			limits.FiveHour = FiveHourLimit{
+				Utilization: w.Utilization,
+				ResetsAt:    parseResetTime(w.ResetsAt),
+				Remaining:   w.Remaining,
+				Total:       w.Total,
+			}
+		}
+		if w := doc.SevenDay; w != nil {
+			limits.SevenDay = SevenDayLimit{
+				Utilization: w.Utilization,
+				ResetsAt:    parseResetTime(w.ResetsAt),
+				Remaining:   w.Remaining,
+				Total:       w.Total,
+			}
+		}
+		return limits, SchemaV1, nil
+
+	case doc.FiveHourBlock != nil || doc.SevenDayBlock != nil:
+		if w := doc.FiveHourBlock; w != nil {
+			limits.FiveHour = FiveHourLimit{
+				Utilization: w.UtilizationPct,
+				ResetsAt:    parseTimestamp(w.ResetsAt),
+				Remaining:   w.MessagesLeft,
+				Total:       w.TotalMessages,
+			}
+		}
+		if w := doc.SevenDayBlock; w != nil {
+			limits.SevenDay = SevenDayLimit{
+				Utilization: w.UtilizationPct,
+				ResetsAt:    parseTimestamp(w.ResetsAt),
+				Remaining:   w.MessagesLeft,
+				Total:       w.TotalMessages,
+			}
+		}
+		return limits, SchemaV0, nil
+	}
+
+	return nil, "", ErrUnknownSchema
+}
+
+// parseResetTime accepts an RFC3339 string or Unix seconds.
+func parseResetTime(raw json.RawMessage) time.Time {
+	if len(raw) == 0 {
+		return time.Time{}
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return parseTimestamp(s)
+	}
+	var secs float64
+	if err := json.Unmarshal(raw, &secs); err == nil && secs > 0 {
+		return time.Unix(int64(secs), 0)
+	}
+	return time.Time{}
+}
+
+// parseTimestamp parses an RFC3339 timestamp or Unix seconds (as sent in
+// rate limit headers), returning zero on failure.
+func parseTimestamp(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil && secs > 0 {
+		return time.Unix(secs, 0)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,98 @@
+package usage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecodeLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantSchema string
+		wantFive   float64
+		wantSeven  float64
+		wantReset  time.Time
+		wantErr    bool
+	}{
+		{
+			name:       "v1 with RFC3339 reset",
+			body:       `{"five_hour":{"utilization":42,"resets_at":"2026-01-15T15:00:00Z"},"seven_day":{"utilization":69}}`,
+			wantSchema: SchemaV1,
+			wantFive:   42,
+			wantSeven:  69,
+			wantReset:  time.Date(2026, 1, 15, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "v1 with Unix reset",
+			body:       `{"five_hour":{"utilization":10.5,"resets_at":1768489200}}`,
+			wantSchema: SchemaV1,
+			wantFive:   10.5,
+			wantReset:  time.Unix(1768489200, 0),
+		},
+		{
+			name:       "v0 legacy shape",
+			body:       `{"five_hour_block":{"utilization_pct":55,"resets_at":"2026-01-15T15:00:00Z","messages_left":20,"total_messages":45},"seven_day_block":{"utilization_pct":12}}`,
+			wantSchema: SchemaV0,
+			wantFive:   55,
+			wantSeven:  12,
+			wantReset:  time.Date(2026, 1, 15, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "unknown shape",
+			body:    `{"usage":{"pct":1}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			body:    `{`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits, schema, err := decodeLimits([]byte(tt.body))
+			if tt.wantErr {
+				if err == nil {
+					t.Error("decodeLimits() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeLimits() error = %v", err)
+			}
+			if schema != tt.wantSchema {
+				t.Errorf("schema = %q, want %q", schema, tt.wantSchema)
+			}
+			if limits.FiveHour.Utilization != tt.wantFive || limits.SevenDay.Utilization != tt.wantSeven {
+				t.Errorf("utilization = %.1f/%.1f, want %.1f/%.1f",
+					limits.FiveHour.Utilization, limits.SevenDay.Utilization, tt.wantFive, tt.wantSeven)
+			}
+			if !limits.FiveHour.ResetsAt.Equal(tt.wantReset) {
+				t.Errorf("FiveHour.ResetsAt = %v, want %v", limits.FiveHour.ResetsAt, tt.wantReset)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-01-15T10:00:00Z", want},
+		{"2026-01-15T19:00:00+09:00", want},
+		{"1768471200", want},
+		{"0", time.Time{}},
+		{"-5", time.Time{}},
+		{"tomorrow", time.Time{}},
+		{"", time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := parseTimestamp(tt.in); !got.Equal(tt.want) {
+			t.Errorf("parseTimestamp(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package usage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/namyoungkim/visor/internal/cost"
+	"github.com/namyoungkim/visor/internal/transcript"
+)
+
+// Source names for [usage] limit_sources.
+const (
+	SourceOAuth   = "oauth"
+	SourceHeaders = "headers"
+	SourceFile    = "file"
+	SourceCommand = "command"
+	SourceLocal   = "local"
+)
+
+// DefaultSourceOrder is used when no order is configured.
+var DefaultSourceOrder = []string{SourceOAuth, SourceHeaders, SourceLocal}
+
+// commandTimeout bounds a user-supplied limits command.
+const commandTimeout = 2 * time.Second
+
+// LimitsSource provides usage limits from one origin.
+type LimitsSource interface {
+	// Name identifies the source in debug output and widgets.
+	Name() string
+
+	// Limits returns current limits, or an error if this source has none.
+	Limits() (*Limits, error)
+}
+
+// FirstAvailable tries sources in order and returns the first limits found,
+// with Limits.Source set. On failure, the error lists every source's reason.
+func FirstAvailable(sources []LimitsSource) (*Limits, error) {
+	var errs []error
+	for _, s := range sources {
+		limits, err := s.Limits()
+		if err == nil && limits != nil {
+			limits.Source = s.Name()
+			return limits, nil
+		}
+		if err == nil {
+			err = errors.New("no data")
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", s.Name(), err))
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("no usage limit sources configured")
+	}
+	return nil, errors.Join(errs...)
+}
+
+// OAuthSource reads limits from the OAuth usage endpoint through the disk cache.
+type OAuthSource struct {
+	Fetcher *CachedFetcher
+}
+
+func (s *OAuthSource) Name() string { return SourceOAuth }
+
+func (s *OAuthSource) Limits() (*Limits, error) {
+	return s.Fetcher.Get()
+}
+
+// FileSource reads limits from a JSON file in SchemaV1 (or SchemaV0).
+// Useful for limits exported by another tool. Limits are Stale once the
+// file is older than TTL (DefaultCacheTTL if 0).
+type FileSource struct {
+	Path string
+	TTL  time.Duration
+}
+
+func (s *FileSource) Name() string { return SourceFile }
+
+func (s *FileSource) Limits() (*Limits, error) {
+	if s.Path == "" {
+		return nil, errors.New("limits_file not set")
+	}
+	data, err := os.ReadFile(expandHome(s.Path))
+	if err != nil {
+		return nil, err
+	}
+	limits, _, err := decodeLimits(data)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(expandHome(s.Path)); err == nil {
+		ttl := s.TTL
+		if ttl <= 0 {
+			ttl = DefaultCacheTTL
+		}
+		limits.FetchedAt = info.ModTime()
+		limits.Stale = time.Since(limits.FetchedAt) > ttl
+	}
+	return limits, nil
+}
+
+// CommandSource runs a user-supplied shell command that prints limits JSON
+// in SchemaV1 (or SchemaV0) to stdout.
+type CommandSource struct {
+	Command string
+}
+
+func (s *CommandSource) Name() string { return SourceCommand }
+
+func (s *CommandSource) Limits() (*Limits, error) {
+	if s.Command == "" {
+		return nil, errors.New("limits_command not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.Command)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("limits_command failed: %w", err)
+	}
+
+	limits, _, err := decodeLimits(stdout.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	limits.FetchedAt = time.Now()
+	return limits, nil
+}
+
+// Unified rate limit response headers, as recorded in transcripts.
+// Utilization is a 0-1 fraction; reset is Unix seconds.
+const (
+	headerPrefix        = "anthropic-ratelimit-unified-"
+	headerFiveHourUtil  = headerPrefix + "5h-utilization"
+	headerFiveHourReset = headerPrefix + "5h-reset"
+	headerSevenDayUtil  = headerPrefix + "7d-utilization"
+	headerSevenDayReset = headerPrefix + "7d-reset"
+)
+
+// HeaderSource reads the most recent Anthropic unified rate-limit headers
+// found in a session transcript. Claude Code records response headers only
+// in some entries (e.g. API errors), so this source is often empty. Only
+// the transcript tail is read, like the transcript widgets, to keep
+// renders fast in long sessions.
+type HeaderSource struct {
+	TranscriptPath string
+}
+
+func (s *HeaderSource) Name() string { return SourceHeaders }
+
+func (s *HeaderSource) Limits() (*Limits, error) {
+	if s.TranscriptPath == "" {
+		return nil, errors.New("no transcript")
+	}
+	lines, err := transcript.Tail(s.TranscriptPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Newest first: the first match is the latest
+	var latest map[string]string
+	var latestTs time.Time
+	for i := len(lines) - 1; i >= 0 && latest == nil; i-- {
+		if !strings.Contains(lines[i], headerPrefix) {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(lines[i]), &entry); err != nil {
+			continue
+		}
+		if latest = findRateLimitHeaders(entry); latest != nil {
+			if ts, ok := entry["timestamp"].(string); ok {
+				latestTs, _ = time.Parse(time.RFC3339, ts)
+			}
+		}
+	}
+	if latest == nil {
+		return nil, errors.New("no rate limit headers in transcript")
+	}
+
+	limits := &Limits{FetchedAt: latestTs}
+	limits.FiveHour.Utilization = headerUtilization(latest[headerFiveHourUtil])
+	limits.FiveHour.ResetsAt = parseTimestamp(latest[headerFiveHourReset])
+	limits.SevenDay.Utilization = headerUtilization(latest[headerSevenDayUtil])
+	limits.SevenDay.ResetsAt = parseTimestamp(latest[headerSevenDayReset])
+
+	// Headers describe a window that has since reset; they no longer apply
+	if !limits.FiveHour.ResetsAt.IsZero() && time.Now().After(limits.FiveHour.ResetsAt) {
+		return nil, errors.New("rate limit headers are from an expired window")
+	}
+	return limits, nil
+}
+
+// findRateLimitHeaders searches a decoded JSON value for an object holding
+// unified rate-limit headers and returns them with lowercased keys.
+func findRateLimitHeaders(v any) map[string]string {
+	switch val := v.(type) {
+	case map[string]any:
+		var found map[string]string
+		for k, item := range val {
+			key := strings.ToLower(k)
+			if strings.HasPrefix(key, headerPrefix) {
+				if found == nil {
+					found = make(map[string]string)
+				}
+				found[key] = headerValue(item)
+			}
+		}
+		if found[headerFiveHourUtil] != "" || found[headerSevenDayUtil] != "" {
+			return found
+		}
+		for _, item := range val {
+			if h := findRateLimitHeaders(item); h != nil {
+				return h
+			}
+		}
+	case []any:
+		for _, item := range val {
+			if h := findRateLimitHeaders(item); h != nil {
+				return h
+			}
+		}
+	}
+	return nil
+}
+
+// headerValue converts a header value (string, number, or list) to a string.
+func headerValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case []any:
+		if len(val) > 0 {
+			return headerValue(val[0])
+		}
+	}
+	return ""
+}
+
+// headerUtilization converts a 0-1 header fraction to a percentage.
+func headerUtilization(s string) float64 {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return f * 100
+}
+
+// LocalSource estimates limits from local JSONL message counts,
+// calibrated by past limit hits when Calibration is set.
+type LocalSource struct {
+	CostData      *cost.CostData
+	BlockStart    time.Time
+	Tier          string
+	FiveHourLimit int
+	SevenDayLimit int
+	Calibration   *Calibration
+}
+
+func (s *LocalSource) Name() string { return SourceLocal }
+
+func (s *LocalSource) Limits() (*Limits, error) {
+	limits := EstimateLimitsCalibrated(s.CostData, s.BlockStart, s.Tier, s.FiveHourLimit, s.SevenDayLimit, s.Calibration)
+	if limits == nil {
+		return nil, errors.New("no local usage data")
+	}
+	return limits, nil
+}
+
+// expandHome expands a leading ~/ in path.
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return home + path[1:]
+		}
+	}
+	return path
+}
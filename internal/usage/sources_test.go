@@ -0,0 +1,199 @@
+package usage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namyoungkim/visor/internal/cost"
+)
+
+type stubSource struct {
+	name   string
+	limits *Limits
+	err    error
+	calls  int
+}
+
+func (s *stubSource) Name() string { return s.name }
+
+func (s *stubSource) Limits() (*Limits, error) {
+	s.calls++
+	return s.limits, s.err
+}
+
+func TestFirstAvailable_Order(t *testing.T) {
+	failing := &stubSource{name: "oauth", err: errors.New("offline")}
+	empty := &stubSource{name: "headers"}
+	local := &stubSource{name: "local", limits: &Limits{FiveHour: FiveHourLimit{Utilization: 30}}}
+	never := &stubSource{name: "command", limits: &Limits{}}
+
+	limits, err := FirstAvailable([]LimitsSource{failing, empty, local, never})
+	if err != nil {
+		t.Fatalf("FirstAvailable() error = %v", err)
+	}
+	if limits.Source != "local" || limits.FiveHour.Utilization != 30 {
+		t.Errorf("got source=%q util=%.0f, want local/30", limits.Source, limits.FiveHour.Utilization)
+	}
+	if never.calls != 0 {
+		t.Error("sources after the first success should not be queried")
+	}
+}
+
+func TestFirstAvailable_AllFail(t *testing.T) {
+	_, err := FirstAvailable([]LimitsSource{
+		&stubSource{name: "oauth", err: errors.New("offline")},
+		&stubSource{name: "file", err: errors.New("missing")},
+	})
+	if err == nil {
+		t.Fatal("FirstAvailable() expected error")
+	}
+	for _, want := range []string{"oauth: offline", "file: missing"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should contain %q", err, want)
+		}
+	}
+
+	if _, err := FirstAvailable(nil); err == nil {
+		t.Error("FirstAvailable(nil) expected error")
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.json")
+	os.WriteFile(path, []byte(`{"five_hour":{"utilization":12},"seven_day":{"utilization":34}}`), 0644)
+
+	limits, err := (&FileSource{Path: path}).Limits()
+	if err != nil {
+		t.Fatalf("Limits() error = %v", err)
+	}
+	if limits.FiveHour.Utilization != 12 || limits.SevenDay.Utilization != 34 {
+		t.Errorf("limits = %+v", limits)
+	}
+	if limits.FetchedAt.IsZero() {
+		t.Error("FetchedAt should come from file mtime")
+	}
+	if limits.Stale {
+		t.Error("a fresh file should not be stale")
+	}
+
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(path, old, old)
+	limits, _ = (&FileSource{Path: path, TTL: 10 * time.Minute}).Limits()
+	if !limits.Stale {
+		t.Error("a file older than TTL should be stale")
+	}
+
+	if _, err := (&FileSource{}).Limits(); err == nil {
+		t.Error("FileSource without path should fail")
+	}
+}
+
+func TestCommandSource(t *testing.T) {
+	cmd := `echo '{"five_hour":{"utilization":77}}'`
+	limits, err := (&CommandSource{Command: cmd}).Limits()
+	if err != nil {
+		t.Fatalf("Limits() error = %v", err)
+	}
+	if limits.FiveHour.Utilization != 77 {
+		t.Errorf("FiveHour.Utilization = %.0f, want 77", limits.FiveHour.Utilization)
+	}
+
+	if _, err := (&CommandSource{Command: "exit 3"}).Limits(); err == nil {
+		t.Error("failing command should return an error")
+	}
+}
+
+func TestHeaderSource(t *testing.T) {
+	reset := time.Now().Add(2 * time.Hour).Unix()
+	lines := []string{
+		`{"type":"user","message":{"role":"user","content":"hi"}}`,
+		`{"type":"system","timestamp":"2026-01-15T10:00:00Z","error":{"headers":{"anthropic-ratelimit-unified-5h-utilization":"0.10"}}}`,
+		`{"type":"system","timestamp":"2026-01-15T11:00:00Z","error":{"headers":{` +
+			`"Anthropic-Ratelimit-Unified-5h-Utilization":"0.42",` +
+			`"anthropic-ratelimit-unified-5h-reset":"` + strconv.FormatInt(reset, 10) + `",` +
+			`"anthropic-ratelimit-unified-7d-utilization":["0.69"]}}}`,
+	}
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+
+	limits, err := (&HeaderSource{TranscriptPath: path}).Limits()
+	if err != nil {
+		t.Fatalf("Limits() error = %v", err)
+	}
+	if limits.FiveHour.Utilization != 42 || limits.SevenDay.Utilization != 69 {
+		t.Errorf("utilization = %.0f/%.0f, want latest headers 42/69",
+			limits.FiveHour.Utilization, limits.SevenDay.Utilization)
+	}
+	if limits.FiveHour.ResetsAt.Unix() != reset {
+		t.Errorf("FiveHour.ResetsAt = %v, want %v", limits.FiveHour.ResetsAt.Unix(), reset)
+	}
+	if limits.FetchedAt.IsZero() {
+		t.Error("FetchedAt should come from the entry timestamp")
+	}
+}
+
+func TestHeaderSource_ReadsTailOnly(t *testing.T) {
+	t.Setenv("VISOR_TRANSCRIPT_MAX_LINES", "2")
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	lines := []string{
+		`{"headers":{"anthropic-ratelimit-unified-5h-utilization":"0.5","anthropic-ratelimit-unified-5h-reset":"` + reset + `"}}`,
+		`{"type":"user"}`,
+		`{"type":"assistant"}`,
+	}
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+
+	if _, err := (&HeaderSource{TranscriptPath: path}).Limits(); err == nil {
+		t.Error("headers before the transcript tail should not be read")
+	}
+
+	t.Setenv("VISOR_TRANSCRIPT_MAX_LINES", "3")
+	if limits, err := (&HeaderSource{TranscriptPath: path}).Limits(); err != nil || limits.FiveHour.Utilization != 50 {
+		t.Errorf("Limits() = %+v, %v, want 50%% from the tail", limits, err)
+	}
+}
+
+func TestHeaderSource_ExpiredWindow(t *testing.T) {
+	reset := time.Now().Add(-time.Hour).Unix()
+	line := `{"headers":{"anthropic-ratelimit-unified-5h-utilization":"0.9","anthropic-ratelimit-unified-5h-reset":"` +
+		strconv.FormatInt(reset, 10) + `"}}`
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	os.WriteFile(path, []byte(line+"\n"), 0644)
+
+	if _, err := (&HeaderSource{TranscriptPath: path}).Limits(); err == nil {
+		t.Error("headers from an expired window should be ignored")
+	}
+}
+
+func TestHeaderSource_NoHeaders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	os.WriteFile(path, []byte(`{"type":"assistant"}`+"\n"), 0644)
+
+	if _, err := (&HeaderSource{TranscriptPath: path}).Limits(); err == nil {
+		t.Error("transcript without headers should return an error")
+	}
+}
+
+func TestLocalSource(t *testing.T) {
+	if _, err := (&LocalSource{}).Limits(); err == nil {
+		t.Error("LocalSource without cost data should fail")
+	}
+
+	limits, err := (&LocalSource{
+		CostData:      &cost.CostData{FiveHourBlockMessages: 9, WeekMessages: 90},
+		BlockStart:    time.Now().Add(-time.Hour),
+		FiveHourLimit: 45,
+		SevenDayLimit: 675,
+	}).Limits()
+	if err != nil {
+		t.Fatalf("Limits() error = %v", err)
+	}
+	if limits.FiveHour.Utilization != 20 {
+		t.Errorf("FiveHour.Utilization = %.1f, want 20", limits.FiveHour.Utilization)
+	}
+}
@@ -0,0 +1,24 @@
+package usage
+
+import (
+	"os"
+	"os/exec"
+)
+
+// DetachedRefresh returns a CachedFetcher.Spawn function that starts the
+// running executable with args in the background. The child gets no stdio
+// and its own session, so the caller can exit while it fetches.
+func DetachedRefresh(args ...string) func() error {
+	return func() error {
+		exe, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		cmd := exec.Command(exe, args...)
+		detach(cmd)
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		return cmd.Process.Release()
+	}
+}
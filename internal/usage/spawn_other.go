@@ -0,0 +1,9 @@
+//go:build !darwin && !linux
+
+package usage
+
+import "os/exec"
+
+// detach is a no-op on other platforms; the child already outlives the
+// parent once started.
+func detach(cmd *exec.Cmd) {}
@@ -0,0 +1,14 @@
+//go:build darwin || linux
+
+package usage
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts cmd in a new session, out of the statusline's process
+// group, so it outlives the parent.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
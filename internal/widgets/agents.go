@@ -1,6 +1,7 @@
 package widgets
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -12,23 +13,33 @@ import (
 
 // AgentsWidget displays the status of spawned sub-agents with details.
 //
-// Supported Extra options:
-//   - max_display: maximum number of agents to show, 0 = unlimited (default: "2")
-//   - show_label: "true"/"false" - show prefix (default: false)
-//   - show_description: "true"/"false" - show task description (default: true)
-//   - show_duration: "true"/"false" - show elapsed time (default: true)
-//   - max_description_len: max length for description (default: "15")
+// Supported Extra options are declared in agentsOptions.
 //
 // Output format: "Explore: Analyze widgets (42s)" (with description and duration)
 // Running agents show elapsed time with "..." suffix: "(42s...)"
+// Tree mode: "✓Explore: Scan repo (42s) 12.3k $0.08 ×14 [✓general-purpose 1.2k $0.01 ×3]"
 type AgentsWidget struct {
 	transcript *transcript.Data
 }
 
+var agentsOptions = config.Options{
+	{Key: "max_display", Type: config.OptionInt, Default: "2", Min: "0", Description: "Max agents to display (0 = unlimited)"},
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Agents:' prefix"},
+	{Key: "show_description", Type: config.OptionBool, Default: "true", Description: "Show task description"},
+	{Key: "show_duration", Type: config.OptionBool, Default: "true", Description: "Show elapsed time"},
+	{Key: "max_description_len", Type: config.OptionInt, Default: "15", Min: "0", Description: "Max description length"},
+	{Key: "show_usage", Type: config.OptionBool, Default: "false", Description: "Show tokens, cost and tool count (always on in tree mode)"},
+	{Key: "mode", Type: config.OptionString, Default: "flat", Enum: []string{"flat", "tree"}, Description: "Layout: flat (top-level agents) or tree (nested agents)"},
+}
+
 func (w *AgentsWidget) Name() string {
 	return "agents"
 }
 
+func (w *AgentsWidget) Options() config.Options {
+	return agentsOptions
+}
+
 // SetTranscript sets the transcript data for this widget.
 func (w *AgentsWidget) SetTranscript(t *transcript.Data) {
 	w.transcript = t
@@ -39,11 +50,15 @@ func (w *AgentsWidget) Render(session *input.Session, cfg *config.WidgetConfig)
 		return ""
 	}
 
-	maxDisplay := GetExtraInt(cfg, "max_display", 2) // 0 = unlimited
-	showDescription := GetExtraBool(cfg, "show_description", true)
-	showDuration := GetExtraBool(cfg, "show_duration", true)
-	maxDescLen := GetExtraInt(cfg, "max_description_len", 15)
-	agents := w.transcript.Agents
+	maxDisplay := agentsOptions.Int(cfg, "max_display") // 0 = unlimited
+	showDescription := agentsOptions.Bool(cfg, "show_description")
+	showDuration := agentsOptions.Bool(cfg, "show_duration")
+	maxDescLen := agentsOptions.Int(cfg, "max_description_len")
+	treeMode := agentsOptions.Value(cfg, "mode") == "tree"
+	showUsage := treeMode || agentsOptions.Bool(cfg, "show_usage")
+
+	// Nested agents are only shown inside their parent in tree mode
+	agents := w.transcript.TopLevelAgents()
 
 	// Prioritize running agents: running first, then completed.
 	// Preserve original order within each group.
@@ -68,12 +83,18 @@ func (w *AgentsWidget) Render(session *input.Session, cfg *config.WidgetConfig)
 	var parts []string
 	for _, agent := range display {
 		part := w.renderAgent(agent, showDescription, showDuration, maxDescLen)
+		if showUsage {
+			part += agentUsageSuffix(agent)
+		}
+		if treeMode {
+			part += w.renderChildren(agent, 0)
+		}
 		parts = append(parts, part)
 	}
 
 	text := strings.Join(parts, " · ")
 
-	if GetExtraBool(cfg, "show_label", false) {
+	if agentsOptions.Bool(cfg, "show_label") {
 		text = "Agents: " + text
 	}
 
@@ -116,17 +137,67 @@ func (w *AgentsWidget) renderAgent(agent transcript.Agent, showDesc, showDur boo
 	return result
 }
 
-// truncateString truncates a string to maxLen runes, adding "..." if truncated.
-// Uses rune count instead of byte count to handle multibyte characters correctly.
+// renderChildren renders nested agents as "[child, child [grandchild]]".
+// Children use the compact format (type, usage) to keep the line short.
+func (w *AgentsWidget) renderChildren(agent transcript.Agent, depth int) string {
+	if len(agent.Children) == 0 || depth >= maxAgentTreeDepth {
+		return ""
+	}
+
+	var children []string
+	for _, id := range agent.Children {
+		child := w.transcript.Agent(id)
+		if child == nil {
+			continue
+		}
+		part := w.renderAgent(*child, false, false, 0) + agentUsageSuffix(*child) + w.renderChildren(*child, depth+1)
+		children = append(children, part)
+	}
+	if len(children) == 0 {
+		return ""
+	}
+	return render.Colorize(" [", "dim") + strings.Join(children, ", ") + render.Colorize("]", "dim")
+}
+
+// maxAgentTreeDepth limits how many nesting levels tree mode renders.
+const maxAgentTreeDepth = 3
+
+// agentUsageSuffix returns " 12.3k $0.08 ×14" for agents with sidechain usage.
+// Returns "" when no usage was recorded (e.g., sidechain transcript not found).
+func agentUsageSuffix(agent transcript.Agent) string {
+	if agent.Usage.TotalTokens() == 0 && agent.ToolCount == 0 {
+		return ""
+	}
+	text := " " + formatTokens(agent.Usage.TotalTokens()) + " " + formatCost(agent.Usage.CostUSD)
+	if agent.ToolCount > 0 {
+		text += " ×" + itoa(agent.ToolCount)
+	}
+	return render.Colorize(text, "dim")
+}
+
+// formatTokens formats a token count compactly: "950", "12.3k", "1.2M".
+func formatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	default:
+		return itoa(n)
+	}
+}
+
+// truncateString truncates a string to maxLen display cells, adding "..." if
+// truncated. Wide characters count as two cells and grapheme clusters
+// (emoji sequences, combining marks) are never split.
 func truncateString(s string, maxLen int) string {
-	runes := []rune(s)
-	if len(runes) <= maxLen {
+	if render.StringWidth(s) <= maxLen {
 		return s
 	}
 	if maxLen <= 3 {
 		return "..."
 	}
-	return string(runes[:maxLen-3]) + "..."
+	return render.Head(s, maxLen-3) + "..."
 }
 
 // formatDurationSec formats seconds into a human-readable duration string.
@@ -161,5 +232,5 @@ func agentStatusIcon(status string) (string, string) {
 }
 
 func (w *AgentsWidget) ShouldRender(session *input.Session, cfg *config.WidgetConfig) bool {
-	return w.transcript != nil && len(w.transcript.Agents) > 0
+	return w.transcript != nil && len(w.transcript.TopLevelAgents()) > 0
 }
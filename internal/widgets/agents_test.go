@@ -471,11 +471,14 @@ func TestTruncateString(t *testing.T) {
 		{"abc", 3, "abc"},      // Fits exactly, no truncation
 		{"abcd", 3, "..."},     // maxLen <= 3 returns "..."
 		{"abcdef", 5, "ab..."}, // Truncates normally
-		// Multibyte character tests (rune-based truncation)
-		{"한글테스트", 5, "한글테스트"},      // 5 runes, fits exactly
-		{"한글테스트입니다", 7, "한글테스..."},  // 8 runes, truncate to 7
-		{"분석하기", 10, "분석하기"},        // 4 runes, fits in 10
-		{"코드리뷰중", 5, "코드리뷰중"},       // 5 runes, fits exactly
+		// Wide characters take two cells
+		{"한글테스트", 10, "한글테스트"},   // 10 cells, fits exactly
+		{"한글테스트", 5, "한..."},     // 2 cells of text + "..."
+		{"한글테스트입니다", 8, "한글..."}, // odd cell left over is dropped
+		{"분석하기", 10, "분석하기"},     // 8 cells, fits in 10
+		// Grapheme clusters are never split
+		{"👨‍👩‍👧 family trip", 8, "👨‍👩‍👧 fa..."},
+		{"🇰🇷🇯🇵🇺🇸 flags", 7, "🇰🇷🇯🇵..."},
 	}
 
 	for _, tt := range tests {
@@ -485,3 +488,72 @@ func TestTruncateString(t *testing.T) {
 		}
 	}
 }
+
+func TestAgentsWidget_TreeMode(t *testing.T) {
+	w := &AgentsWidget{}
+	w.SetTranscript(&transcript.Data{
+		Agents: []transcript.Agent{
+			{
+				ID: "1", Type: "Explore", Status: "completed", StartTime: 1000, EndTime: 43000,
+				Usage:     transcript.Usage{InputTokens: 12000, OutputTokens: 300, CostUSD: 0.08},
+				ToolCount: 14,
+				Children:  []string{"2"},
+			},
+			{
+				ID: "2", Type: "general-purpose", Status: "completed", ParentID: "1",
+				Usage:     transcript.Usage{InputTokens: 1200, CostUSD: 0.01},
+				ToolCount: 3,
+			},
+		},
+	})
+
+	cfg := &config.WidgetConfig{Extra: map[string]string{"mode": "tree"}}
+	result := stripANSI(w.Render(&input.Session{}, cfg))
+
+	expected := "✓Explore (42s) 12.3k $0.08 ×14 [✓general-purpose 1.2k $0.01 ×3]"
+	if result != expected {
+		t.Errorf("Render() = '%s', want '%s'", result, expected)
+	}
+}
+
+func TestAgentsWidget_FlatModeHidesNested(t *testing.T) {
+	w := &AgentsWidget{}
+	w.SetTranscript(&transcript.Data{
+		Agents: []transcript.Agent{
+			{ID: "1", Type: "Explore", Status: "completed", Children: []string{"2"},
+				Usage: transcript.Usage{OutputTokens: 950, CostUSD: 0.005}},
+			{ID: "2", Type: "Plan", Status: "running", ParentID: "1"},
+		},
+	})
+
+	result := stripANSI(w.Render(&input.Session{}, &config.WidgetConfig{}))
+	if strings.Contains(result, "Plan") {
+		t.Errorf("expected nested agent hidden in flat mode, got '%s'", result)
+	}
+	if strings.Contains(result, "950") {
+		t.Errorf("expected usage hidden by default, got '%s'", result)
+	}
+
+	cfg := &config.WidgetConfig{Extra: map[string]string{"show_usage": "true"}}
+	result = stripANSI(w.Render(&input.Session{}, cfg))
+	if !strings.Contains(result, "✓Explore 950 $0.005") {
+		t.Errorf("expected usage suffix, got '%s'", result)
+	}
+}
+
+func TestFormatTokens(t *testing.T) {
+	tests := []struct {
+		n        int
+		expected string
+	}{
+		{0, "0"},
+		{950, "950"},
+		{12345, "12.3k"},
+		{1_250_000, "1.2M"},
+	}
+	for _, tt := range tests {
+		if got := formatTokens(tt.n); got != tt.expected {
+			t.Errorf("formatTokens(%d) = %s, want %s", tt.n, got, tt.expected)
+		}
+	}
+}
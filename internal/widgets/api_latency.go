@@ -13,15 +13,22 @@ import (
 //
 // Formula: total_api_duration_ms / total_api_calls
 //
-// Supported Extra options:
-//   - warn_threshold: "2000" - milliseconds for warning color (default: 2000)
-//   - critical_threshold: "5000" - milliseconds for critical/red color (default: 5000)
+// Supported Extra options are declared in apiLatencyOptions.
 type APILatencyWidget struct{}
 
+var apiLatencyOptions = config.Options{
+	{Key: "warn_threshold", Type: config.OptionFloat, Default: optionNumber(LatencyWarningMs), Min: "0", Description: "Warning threshold (ms)"},
+	{Key: "critical_threshold", Type: config.OptionFloat, Default: optionNumber(LatencyDangerMs), Min: "0", Description: "Critical threshold (ms)"},
+}
+
 func (w *APILatencyWidget) Name() string {
 	return "api_latency"
 }
 
+func (w *APILatencyWidget) Options() config.Options {
+	return apiLatencyOptions
+}
+
 func (w *APILatencyWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
 	totalMs := session.Cost.TotalAPIDurationMs
 	calls := session.Cost.TotalAPICalls
@@ -40,8 +47,8 @@ func (w *APILatencyWidget) Render(session *input.Session, cfg *config.WidgetConf
 		text = fmt.Sprintf("API: %dms", ms)
 	}
 
-	warnThreshold := GetExtraFloat(cfg, "warn_threshold", LatencyWarningMs)
-	criticalThreshold := GetExtraFloat(cfg, "critical_threshold", LatencyDangerMs)
+	warnThreshold := apiLatencyOptions.Float(cfg, "warn_threshold")
+	criticalThreshold := apiLatencyOptions.Float(cfg, "critical_threshold")
 	color := ColorByThreshold(float64(ms), warnThreshold, criticalThreshold)
 	return render.Colorize(text, color)
 }
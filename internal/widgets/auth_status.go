@@ -0,0 +1,116 @@
+package widgets
+
+import (
+	"time"
+
+	"github.com/namyoungkim/visor/internal/auth"
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/render"
+)
+
+// AuthExpiryWarningMinutes is the default remaining lifetime that turns the
+// widget yellow.
+const AuthExpiryWarningMinutes = 30
+
+// AuthStatusWidget displays the OAuth credential state used for usage limits.
+//
+// Output examples:
+//   - "🔑 3h12m"   - token valid, expires in 3h12m
+//   - "🔑 ↻ 7h59m" - token was refreshed by visor
+//   - "🔑 expired" - token expired and could not be refreshed
+//   - "🔑 error"   - credential store unreadable (e.g. keychain denied)
+//
+// Supported Extra options are declared in authStatusOptions.
+type AuthStatusWidget struct {
+	status *auth.Status
+}
+
+var authStatusOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Auth:' instead of the key icon"},
+	{Key: "show_expiry", Type: config.OptionBool, Default: "true", Description: "Show time until expiry"},
+	{Key: "warn_minutes", Type: config.OptionInt, Default: optionNumber(AuthExpiryWarningMinutes), Min: "0", Description: "Warning threshold (minutes to expiry)"},
+	{Key: "hide_when_valid", Type: config.OptionBool, Default: "false", Description: "Only show when action is needed"},
+}
+
+func (w *AuthStatusWidget) Name() string {
+	return "auth_status"
+}
+
+func (w *AuthStatusWidget) Options() config.Options {
+	return authStatusOptions
+}
+
+// SetStatus sets the authentication status for this widget.
+func (w *AuthStatusWidget) SetStatus(status *auth.Status) {
+	w.status = status
+}
+
+func (w *AuthStatusWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
+	prefix := "🔑 "
+	if authStatusOptions.Bool(cfg, "show_label") {
+		prefix = "Auth: "
+	}
+
+	if w.status == nil {
+		return render.Colorize(prefix+"—", "gray")
+	}
+
+	var text, color string
+	switch w.status.State {
+	case auth.StateExpired:
+		text, color = "expired", "red"
+	case auth.StateError:
+		text, color = "error", "red"
+	case auth.StateNone:
+		text, color = "none", "gray"
+	default:
+		text, color = "ok", "green"
+		if w.status.State == auth.StateRefreshed {
+			text = "↻"
+		}
+
+		remaining := w.remaining()
+		if remaining > 0 && authStatusOptions.Bool(cfg, "show_expiry") {
+			if text == "ok" {
+				text = formatDuration(remaining)
+			} else {
+				text += " " + formatDuration(remaining)
+			}
+		}
+		if w.expiringSoon(cfg) {
+			color = "yellow"
+		}
+	}
+
+	return render.Colorize(prefix+text, color)
+}
+
+func (w *AuthStatusWidget) ShouldRender(session *input.Session, cfg *config.WidgetConfig) bool {
+	if w.status == nil || w.status.State == auth.StateNone {
+		return false
+	}
+	if authStatusOptions.Bool(cfg, "hide_when_valid") {
+		return w.status.State == auth.StateExpired || w.status.State == auth.StateError || w.expiringSoon(cfg)
+	}
+	return true
+}
+
+// expiringSoon reports whether the token expires within warn_minutes.
+func (w *AuthStatusWidget) expiringSoon(cfg *config.WidgetConfig) bool {
+	warn := time.Duration(authStatusOptions.Int(cfg, "warn_minutes")) * time.Minute
+	remaining := w.remaining()
+	return remaining > 0 && remaining < warn
+}
+
+// remaining returns the time until the token expires, or 0 if unknown.
+func (w *AuthStatusWidget) remaining() time.Duration {
+	if w.status == nil || w.status.ExpiresAt.IsZero() {
+		return 0
+	}
+	d := time.Until(w.status.ExpiresAt)
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,102 @@
+package widgets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/namyoungkim/visor/internal/auth"
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+)
+
+func TestAuthStatusWidget_Render(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *auth.Status
+		extra  map[string]string
+		want   string
+	}{
+		{
+			name:   "no status",
+			status: nil,
+			want:   "🔑 —",
+		},
+		{
+			name:   "valid with expiry",
+			status: &auth.Status{State: auth.StateValid, ExpiresAt: time.Now().Add(3*time.Hour + 12*time.Minute + 30*time.Second)},
+			want:   "🔑 3h12m",
+		},
+		{
+			name:   "valid without expiry",
+			status: &auth.Status{State: auth.StateValid},
+			want:   "🔑 ok",
+		},
+		{
+			name:   "refreshed",
+			status: &auth.Status{State: auth.StateRefreshed, ExpiresAt: time.Now().Add(45*time.Minute + 30*time.Second)},
+			want:   "🔑 ↻ 45m",
+		},
+		{
+			name:   "expiry hidden",
+			status: &auth.Status{State: auth.StateValid, ExpiresAt: time.Now().Add(time.Hour)},
+			extra:  map[string]string{"show_expiry": "false"},
+			want:   "🔑 ok",
+		},
+		{
+			name:   "expired with label",
+			status: &auth.Status{State: auth.StateExpired},
+			extra:  map[string]string{"show_label": "true"},
+			want:   "Auth: expired",
+		},
+		{
+			name:   "error",
+			status: &auth.Status{State: auth.StateError},
+			want:   "🔑 error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &AuthStatusWidget{}
+			w.SetStatus(tt.status)
+			got := stripANSI(w.Render(&input.Session{}, &config.WidgetConfig{Extra: tt.extra}))
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthStatusWidget_ShouldRender(t *testing.T) {
+	w := &AuthStatusWidget{}
+	session := &input.Session{}
+	cfg := &config.WidgetConfig{}
+	hideValid := &config.WidgetConfig{Extra: map[string]string{"hide_when_valid": "true"}}
+
+	if w.ShouldRender(session, cfg) {
+		t.Error("ShouldRender() = true without status, want false")
+	}
+
+	w.SetStatus(&auth.Status{State: auth.StateNone})
+	if w.ShouldRender(session, cfg) {
+		t.Error("ShouldRender() = true without credentials, want false")
+	}
+
+	w.SetStatus(&auth.Status{State: auth.StateValid, ExpiresAt: time.Now().Add(5 * time.Hour)})
+	if !w.ShouldRender(session, cfg) {
+		t.Error("ShouldRender() = false for valid token, want true")
+	}
+	if w.ShouldRender(session, hideValid) {
+		t.Error("ShouldRender() = true for valid token with hide_when_valid, want false")
+	}
+
+	w.SetStatus(&auth.Status{State: auth.StateValid, ExpiresAt: time.Now().Add(10 * time.Minute)})
+	if !w.ShouldRender(session, hideValid) {
+		t.Error("ShouldRender() = false for expiring token with hide_when_valid, want true")
+	}
+
+	w.SetStatus(&auth.Status{State: auth.StateExpired})
+	if !w.ShouldRender(session, hideValid) {
+		t.Error("ShouldRender() = false for expired token with hide_when_valid, want true")
+	}
+}
@@ -16,18 +16,25 @@ const (
 // BlockCostWidget displays the cost spent in the current 5-hour block.
 // This is useful for Claude Pro users to track spending within rate limit windows.
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - whether to show "Block$:" prefix (default: false)
-//   - warn_threshold: "2.0" - USD for warning color (default: 2.0)
-//   - critical_threshold: "5.0" - USD for critical/red color (default: 5.0)
+// Supported Extra options are declared in blockCostOptions.
 type BlockCostWidget struct {
 	costData *cost.CostData
 }
 
+var blockCostOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Block$:' prefix"},
+	{Key: "warn_threshold", Type: config.OptionFloat, Default: optionNumber(BlockCostWarningUSD), Min: "0", Description: "Warning threshold (USD)"},
+	{Key: "critical_threshold", Type: config.OptionFloat, Default: optionNumber(BlockCostCriticalUSD), Min: "0", Description: "Critical threshold (USD)"},
+}
+
 func (w *BlockCostWidget) Name() string {
 	return "block_cost"
 }
 
+func (w *BlockCostWidget) Options() config.Options {
+	return blockCostOptions
+}
+
 // SetCostData sets the cost data for this widget.
 func (w *BlockCostWidget) SetCostData(data *cost.CostData) {
 	w.costData = data
@@ -43,14 +50,14 @@ func (w *BlockCostWidget) Render(session *input.Session, cfg *config.WidgetConfi
 	var text string
 	if cfg.Format != "" {
 		text = FormatOutput(cfg, "", value)
-	} else if GetExtraBool(cfg, "show_label", false) {
+	} else if blockCostOptions.Bool(cfg, "show_label") {
 		text = "Block$: " + value
 	} else {
 		text = value
 	}
 
-	warnThreshold := GetExtraFloat(cfg, "warn_threshold", BlockCostWarningUSD)
-	criticalThreshold := GetExtraFloat(cfg, "critical_threshold", BlockCostCriticalUSD)
+	warnThreshold := blockCostOptions.Float(cfg, "warn_threshold")
+	criticalThreshold := blockCostOptions.Float(cfg, "critical_threshold")
 	color := ColorByThreshold(w.costData.FiveHourBlock, warnThreshold, criticalThreshold)
 	return render.Colorize(text, color)
 }
@@ -17,24 +17,36 @@ const (
 	BlockLimitCriticalPct = 90.0
 )
 
+// LimitStaleMarker is appended to cached limits older than the cache TTL.
+const LimitStaleMarker = "*"
+
 // BlockLimitWidget displays the 5-hour rate limit utilization.
 // This is for Claude Pro users to see their usage against the rate limit.
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - whether to show "5h:" prefix (default: true)
-//   - show_remaining: "true"/"false" - show remaining time (default: true)
-//   - show_bar: "true"/"false" - show progress bar (default: false)
-//   - bar_width: "10" - progress bar width in characters (default: 10)
-//   - warn_threshold: "70" - % utilization for warning color (default: 70)
-//   - critical_threshold: "90" - % utilization for critical color (default: 90)
+// Supported Extra options are declared in blockLimitOptions.
 type BlockLimitWidget struct {
 	limits *usage.Limits
 }
 
+var blockLimitOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "true", Description: "Show '5h:' prefix"},
+	{Key: "show_remaining", Type: config.OptionBool, Default: "true", Description: "Show time until reset"},
+	{Key: "show_bar", Type: config.OptionBool, Default: "false", Description: "Show progress bar"},
+	{Key: "bar_width", Type: config.OptionInt, Default: optionNumber(DefaultBarWidth), Min: "1", Description: "Progress bar width"},
+	{Key: "warn_threshold", Type: config.OptionFloat, Default: optionNumber(BlockLimitWarningPct), Min: "0", Max: "100", Description: "Warning threshold (% used)"},
+	{Key: "critical_threshold", Type: config.OptionFloat, Default: optionNumber(BlockLimitCriticalPct), Min: "0", Max: "100", Description: "Critical threshold (% used)"},
+	{Key: "show_stale", Type: config.OptionBool, Default: "true", Description: "Mark cached data older than the TTL with '*'"},
+	{Key: "show_source", Type: config.OptionBool, Default: "false", Description: "Show limits source, e.g. [oauth]"},
+}
+
 func (w *BlockLimitWidget) Name() string {
 	return "block_limit"
 }
 
+func (w *BlockLimitWidget) Options() config.Options {
+	return blockLimitOptions
+}
+
 // SetLimits sets the usage limits for this widget.
 func (w *BlockLimitWidget) SetLimits(limits *usage.Limits) {
 	w.limits = limits
@@ -46,38 +58,38 @@ func (w *BlockLimitWidget) Render(session *input.Session, cfg *config.WidgetConf
 	}
 
 	pct := w.limits.FiveHour.Utilization
-	pctStr := fmt.Sprintf("%.0f%%", pct)
+	pctStr := fmt.Sprintf("%.0f%%", pct) + staleMarker(w.limits, cfg)
 
 	// Build value with optional progress bar
 	var valueParts []string
 	valueParts = append(valueParts, pctStr)
 
-	if GetExtraBool(cfg, "show_bar", false) {
-		barWidth := GetExtraInt(cfg, "bar_width", DefaultBarWidth)
+	if blockLimitOptions.Bool(cfg, "show_bar") {
+		barWidth := blockLimitOptions.Int(cfg, "bar_width")
 		bar := ProgressBar(pct, barWidth)
 		valueParts = append(valueParts, bar)
 	}
 
-	if GetExtraBool(cfg, "show_remaining", true) {
+	if blockLimitOptions.Bool(cfg, "show_remaining") {
 		remaining := w.limits.FiveHourRemaining()
 		if remaining > 0 {
 			valueParts = append(valueParts, fmt.Sprintf("(%s)", formatDuration(remaining)))
 		}
 	}
 
-	value := strings.Join(valueParts, " ")
+	value := strings.Join(valueParts, " ") + sourceHint(w.limits, cfg)
 
 	var text string
 	if cfg.Format != "" {
 		text = FormatOutput(cfg, "", value)
-	} else if GetExtraBool(cfg, "show_label", true) {
+	} else if blockLimitOptions.Bool(cfg, "show_label") {
 		text = "5h: " + value
 	} else {
 		text = value
 	}
 
-	warnThreshold := GetExtraFloat(cfg, "warn_threshold", BlockLimitWarningPct)
-	criticalThreshold := GetExtraFloat(cfg, "critical_threshold", BlockLimitCriticalPct)
+	warnThreshold := blockLimitOptions.Float(cfg, "warn_threshold")
+	criticalThreshold := blockLimitOptions.Float(cfg, "critical_threshold")
 	color := ColorByThreshold(pct, warnThreshold, criticalThreshold)
 	return render.Colorize(text, color)
 }
@@ -86,6 +98,22 @@ func (w *BlockLimitWidget) ShouldRender(session *input.Session, cfg *config.Widg
 	return w.limits != nil && w.limits.FiveHour.Utilization > 0
 }
 
+// staleMarker returns LimitStaleMarker when limits come from an expired cache.
+func staleMarker(limits *usage.Limits, cfg *config.WidgetConfig) string {
+	if limits.Stale && blockLimitOptions.Bool(cfg, "show_stale") {
+		return LimitStaleMarker
+	}
+	return ""
+}
+
+// sourceHint returns " [source]" naming the LimitsSource when show_source is enabled.
+func sourceHint(limits *usage.Limits, cfg *config.WidgetConfig) string {
+	if limits.Source == "" || !blockLimitOptions.Bool(cfg, "show_source") {
+		return ""
+	}
+	return " [" + limits.Source + "]"
+}
+
 // formatDuration formats a duration for display.
 func formatDuration(d time.Duration) string {
 	hours := int(d.Hours())
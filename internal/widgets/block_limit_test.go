@@ -61,6 +61,15 @@ func TestBlockLimitWidget_Render(t *testing.T) {
 			},
 			want: "5h: 50% █████░░░░░",
 		},
+		{
+			name: "stale cache is marked",
+			limits: &usage.Limits{
+				FiveHour: usage.FiveHourLimit{Utilization: 42.0},
+				Stale:    true,
+			},
+			cfg:  &config.WidgetConfig{},
+			want: "5h: 42%*",
+		},
 		{
 			name:   "no data returns dash",
 			limits: nil,
@@ -86,6 +95,62 @@ func TestBlockLimitWidget_Render(t *testing.T) {
 	}
 }
 
+func TestLimitWidgets_StaleMarkerDisabled(t *testing.T) {
+	limits := &usage.Limits{
+		FiveHour: usage.FiveHourLimit{Utilization: 42.0},
+		SevenDay: usage.SevenDayLimit{Utilization: 69.0},
+		Stale:    true,
+	}
+	cfg := &config.WidgetConfig{
+		Extra: map[string]string{"show_stale": "false", "show_remaining": "false"},
+	}
+
+	block := &BlockLimitWidget{}
+	block.SetLimits(limits)
+	if got := stripANSI(block.Render(&input.Session{}, cfg)); got != "5h: 42%" {
+		t.Errorf("block_limit Render() = %q, want %q", got, "5h: 42%")
+	}
+
+	week := &WeekLimitWidget{}
+	week.SetLimits(limits)
+	if got := stripANSI(week.Render(&input.Session{}, cfg)); got != "7d: 69%" {
+		t.Errorf("week_limit Render() = %q, want %q", got, "7d: 69%")
+	}
+
+	cfg.Extra["show_stale"] = "true"
+	if got := stripANSI(week.Render(&input.Session{}, cfg)); got != "7d: 69%*" {
+		t.Errorf("week_limit Render() = %q, want %q", got, "7d: 69%*")
+	}
+}
+
+func TestLimitWidgets_SourceHint(t *testing.T) {
+	limits := &usage.Limits{
+		FiveHour: usage.FiveHourLimit{Utilization: 42.0},
+		SevenDay: usage.SevenDayLimit{Utilization: 69.0},
+		Source:   "headers",
+	}
+	cfg := &config.WidgetConfig{
+		Extra: map[string]string{"show_source": "true", "show_remaining": "false"},
+	}
+
+	block := &BlockLimitWidget{}
+	block.SetLimits(limits)
+	if got := stripANSI(block.Render(&input.Session{}, cfg)); got != "5h: 42% [headers]" {
+		t.Errorf("block_limit Render() = %q, want %q", got, "5h: 42% [headers]")
+	}
+
+	week := &WeekLimitWidget{}
+	week.SetLimits(limits)
+	if got := stripANSI(week.Render(&input.Session{}, cfg)); got != "7d: 69% [headers]" {
+		t.Errorf("week_limit Render() = %q, want %q", got, "7d: 69% [headers]")
+	}
+
+	// Hidden by default
+	if got := stripANSI(week.Render(&input.Session{}, &config.WidgetConfig{})); got != "7d: 69%" {
+		t.Errorf("week_limit Render() default = %q, want %q", got, "7d: 69%")
+	}
+}
+
 func TestBlockLimitWidget_ShouldRender(t *testing.T) {
 	w := &BlockLimitWidget{}
 	session := &input.Session{}
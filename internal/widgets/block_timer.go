@@ -11,18 +11,25 @@ import (
 
 // BlockTimerWidget displays remaining time in the 5-hour Claude Pro rate limit block.
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - whether to show "Block:" prefix (default: true)
-//   - warn_threshold: "80" - percentage elapsed for warning color (default: 80)
-//   - critical_threshold: "95" - percentage elapsed for critical/red color (default: 95)
+// Supported Extra options are declared in blockTimerOptions.
 type BlockTimerWidget struct {
 	history *history.History
 }
 
+var blockTimerOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "true", Description: "Show 'Block:' prefix"},
+	{Key: "warn_threshold", Type: config.OptionFloat, Default: optionNumber(BlockTimerWarningPct), Min: "0", Max: "100", Description: "Warning threshold (% elapsed)"},
+	{Key: "critical_threshold", Type: config.OptionFloat, Default: optionNumber(BlockTimerCriticalPct), Min: "0", Max: "100", Description: "Critical threshold (% elapsed)"},
+}
+
 func (w *BlockTimerWidget) Name() string {
 	return "block_timer"
 }
 
+func (w *BlockTimerWidget) Options() config.Options {
+	return blockTimerOptions
+}
+
 func (w *BlockTimerWidget) SetHistory(h *history.History) {
 	w.history = h
 }
@@ -54,7 +61,7 @@ func (w *BlockTimerWidget) Render(session *input.Session, cfg *config.WidgetConf
 	var text string
 	if cfg.Format != "" {
 		text = FormatOutput(cfg, "", value)
-	} else if GetExtraBool(cfg, "show_label", true) {
+	} else if blockTimerOptions.Bool(cfg, "show_label") {
 		text = "Block: " + value
 	} else {
 		text = value
@@ -62,8 +69,8 @@ func (w *BlockTimerWidget) Render(session *input.Session, cfg *config.WidgetConf
 
 	// Determine color based on elapsed percentage
 	elapsedPct := w.history.GetBlockElapsedPct()
-	warnThreshold := GetExtraFloat(cfg, "warn_threshold", BlockTimerWarningPct)
-	criticalThreshold := GetExtraFloat(cfg, "critical_threshold", BlockTimerCriticalPct)
+	warnThreshold := blockTimerOptions.Float(cfg, "warn_threshold")
+	criticalThreshold := blockTimerOptions.Float(cfg, "critical_threshold")
 	color := ColorByThreshold(elapsedPct, warnThreshold, criticalThreshold)
 
 	return render.Colorize(text, color)
@@ -18,16 +18,23 @@ const (
 //
 // Calculation: total_cost_usd / (total_duration_ms / 60000)
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - whether to show "Burn:" prefix (default: false)
-//   - warn_threshold: "10" - cents/min for warning color (default: 10)
-//   - critical_threshold: "25" - cents/min for critical/red color (default: 25)
+// Supported Extra options are declared in burnRateOptions.
 type BurnRateWidget struct{}
 
+var burnRateOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Burn:' prefix"},
+	{Key: "warn_threshold", Type: config.OptionFloat, Default: optionNumber(BurnRateWarningCents), Min: "0", Description: "Warning threshold (cents/min)"},
+	{Key: "critical_threshold", Type: config.OptionFloat, Default: optionNumber(BurnRateDangerCents), Min: "0", Description: "Critical threshold (cents/min)"},
+}
+
 func (w *BurnRateWidget) Name() string {
 	return "burn_rate"
 }
 
+func (w *BurnRateWidget) Options() config.Options {
+	return burnRateOptions
+}
+
 func (w *BurnRateWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
 	cost := session.Cost.TotalCostUSD
 	durationMs := session.Cost.TotalDurationMs
@@ -57,14 +64,14 @@ func (w *BurnRateWidget) Render(session *input.Session, cfg *config.WidgetConfig
 	var text string
 	if cfg.Format != "" {
 		text = FormatOutput(cfg, "", value)
-	} else if GetExtraBool(cfg, "show_label", false) {
+	} else if burnRateOptions.Bool(cfg, "show_label") {
 		text = "Burn: " + value
 	} else {
 		text = value
 	}
 
-	warnThreshold := GetExtraFloat(cfg, "warn_threshold", BurnRateWarningCents)
-	criticalThreshold := GetExtraFloat(cfg, "critical_threshold", BurnRateDangerCents)
+	warnThreshold := burnRateOptions.Float(cfg, "warn_threshold")
+	criticalThreshold := burnRateOptions.Float(cfg, "critical_threshold")
 	color := ColorByThreshold(burnRateCents, warnThreshold, criticalThreshold)
 	return render.Colorize(text, color)
 }
@@ -12,22 +12,29 @@ import (
 // This is a unique metric that no other statusline exposes.
 // Formula: cache_read_input_tokens / (cache_read_input_tokens + input_tokens) * 100
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - whether to show "Cache:" prefix (default: true)
-//   - good_threshold: "80" - percentage for good/green color (default: 80)
-//   - warn_threshold: "50" - percentage for warning color (default: 50)
+// Supported Extra options are declared in cacheHitOptions.
 type CacheHitWidget struct{}
 
+var cacheHitOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "true", Description: "Show 'Cache:' prefix"},
+	{Key: "good_threshold", Type: config.OptionFloat, Default: optionNumber(CacheHitGoodPct), Min: "0", Max: "100", Description: "Good (green) threshold %"},
+	{Key: "warn_threshold", Type: config.OptionFloat, Default: optionNumber(CacheHitWarningPct), Min: "0", Max: "100", Description: "Warning threshold %"},
+}
+
 func (w *CacheHitWidget) Name() string {
 	return "cache_hit"
 }
 
+func (w *CacheHitWidget) Options() config.Options {
+	return cacheHitOptions
+}
+
 func (w *CacheHitWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
 	// Check if current_usage is available
 	cu := session.GetCurrentUsage()
 	if cu == nil {
 		label := "Cache: —"
-		if !GetExtraBool(cfg, "show_label", true) {
+		if !cacheHitOptions.Bool(cfg, "show_label") {
 			label = "—"
 		}
 		return render.Colorize(label, "gray")
@@ -39,15 +46,15 @@ func (w *CacheHitWidget) Render(session *input.Session, cfg *config.WidgetConfig
 	total := cacheRead + inputTokens
 	if total == 0 {
 		label := "Cache: —"
-		if !GetExtraBool(cfg, "show_label", true) {
+		if !cacheHitOptions.Bool(cfg, "show_label") {
 			label = "—"
 		}
 		return render.Colorize(label, "gray")
 	}
 
 	rate := float64(cacheRead) / float64(total) * 100
-	goodThreshold := GetExtraFloat(cfg, "good_threshold", CacheHitGoodPct)
-	warnThreshold := GetExtraFloat(cfg, "warn_threshold", CacheHitWarningPct)
+	goodThreshold := cacheHitOptions.Float(cfg, "good_threshold")
+	warnThreshold := cacheHitOptions.Float(cfg, "warn_threshold")
 	color := ColorByThresholdInverse(rate, goodThreshold, warnThreshold)
 
 	value := fmt.Sprintf("%.0f%%", rate)
@@ -55,7 +62,7 @@ func (w *CacheHitWidget) Render(session *input.Session, cfg *config.WidgetConfig
 	var text string
 	if cfg.Format != "" {
 		text = FormatOutput(cfg, "", value)
-	} else if GetExtraBool(cfg, "show_label", true) {
+	} else if cacheHitOptions.Bool(cfg, "show_label") {
 		text = "Cache: " + value
 	} else {
 		text = value
@@ -0,0 +1,92 @@
+package widgets
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/render"
+)
+
+// CCVersionWidget displays the Claude Code version.
+//
+// Supported Extra options are declared in ccVersionOptions.
+//
+// Output format: "CC 2.1.3" or "2.1.3"
+type CCVersionWidget struct{}
+
+var ccVersionOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "true", Description: "Show 'CC' prefix"},
+	{Key: "min_version", Type: config.OptionString, Default: "", Description: "Show older versions in warning color"},
+}
+
+func (w *CCVersionWidget) Name() string {
+	return "cc_version"
+}
+
+func (w *CCVersionWidget) Options() config.Options {
+	return ccVersionOptions
+}
+
+func (w *CCVersionWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
+	version := session.Version
+	if version == "" {
+		return ""
+	}
+
+	var text string
+	if cfg.Format != "" {
+		text = FormatOutput(cfg, "", version)
+	} else if ccVersionOptions.Bool(cfg, "show_label") {
+		text = "CC " + version
+	} else {
+		text = version
+	}
+
+	color := "gray"
+	if minVersion := ccVersionOptions.Value(cfg, "min_version"); minVersion != "" && compareVersions(version, minVersion) < 0 {
+		color = "yellow"
+	}
+	return render.Colorize(text, color)
+}
+
+func (w *CCVersionWidget) ShouldRender(session *input.Session, cfg *config.WidgetConfig) bool {
+	return session.Version != ""
+}
+
+// compareVersions compares dotted numeric versions ("2.1.3"), ignoring any
+// pre-release suffix. Returns -1, 0 or 1.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionParts parses "v2.1.3-beta" into [2 1 3].
+func versionParts(v string) []int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+ "); i >= 0 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(s)
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,66 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/render"
+)
+
+func TestCCVersionWidget_Render(t *testing.T) {
+	w := &CCVersionWidget{}
+
+	tests := []struct {
+		name     string
+		version  string
+		extra    map[string]string
+		expected string
+	}{
+		{"empty", "", nil, ""},
+		{"default label", "2.1.3", nil, "CC 2.1.3"},
+		{"no label", "2.1.3", map[string]string{"show_label": "false"}, "2.1.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &input.Session{Version: tt.version}
+			result := stripANSI(w.Render(session, &config.WidgetConfig{Extra: tt.extra}))
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCCVersionWidget_MinVersion(t *testing.T) {
+	w := &CCVersionWidget{}
+	cfg := &config.WidgetConfig{Extra: map[string]string{"min_version": "2.0.0"}}
+
+	old := w.Render(&input.Session{Version: "1.0.98"}, cfg)
+	if old != render.Colorize("CC 1.0.98", "yellow") {
+		t.Errorf("Expected yellow for old version, got %q", old)
+	}
+	current := w.Render(&input.Session{Version: "2.1.3"}, cfg)
+	if current != render.Colorize("CC 2.1.3", "gray") {
+		t.Errorf("Expected gray for current version, got %q", current)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"2.1.3", "2.1.3", 0},
+		{"2.1.3", "2.1.10", -1},
+		{"2.1", "2.1.0", 0},
+		{"v2.0.0-beta", "1.9.9", 1},
+	}
+
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.expected {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
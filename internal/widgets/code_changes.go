@@ -16,6 +16,10 @@ func (w *CodeChangesWidget) Name() string {
 	return "code_changes"
 }
 
+func (w *CodeChangesWidget) Options() config.Options {
+	return nil
+}
+
 func (w *CodeChangesWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
 	added := session.Workspace.LinesAdded
 	removed := session.Workspace.LinesRemoved
@@ -20,15 +20,22 @@ const (
 // Calculation: (80 - current%) / burn_rate_per_min
 // where burn_rate_per_min = current_percentage / (total_duration_ms / 60000)
 //
-// Supported Extra options:
-//   - show_when_above: context % threshold to start showing (default: "40")
-//   - show_label: "true"/"false" - show "ETA:" prefix (default: false)
+// Supported Extra options are declared in compactETAOptions.
 type CompactETAWidget struct{}
 
+var compactETAOptions = config.Options{
+	{Key: "show_when_above", Type: config.OptionInt, Default: "40", Min: "0", Max: "100", Description: "Show only above this context %"},
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'ETA:' prefix"},
+}
+
 func (w *CompactETAWidget) Name() string {
 	return "compact_eta"
 }
 
+func (w *CompactETAWidget) Options() config.Options {
+	return compactETAOptions
+}
+
 func (w *CompactETAWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
 	pct := session.ContextWindow.UsedPercentage
 	durationMs := session.Cost.TotalDurationMs
@@ -77,7 +84,7 @@ func (w *CompactETAWidget) Render(session *input.Session, cfg *config.WidgetConf
 	var text string
 	if cfg.Format != "" {
 		text = FormatOutput(cfg, "", value)
-	} else if GetExtraBool(cfg, "show_label", false) {
+	} else if compactETAOptions.Bool(cfg, "show_label") {
 		text = "ETA: " + value
 	} else {
 		text = value
@@ -98,7 +105,7 @@ func (w *CompactETAWidget) Render(session *input.Session, cfg *config.WidgetConf
 
 func (w *CompactETAWidget) ShouldRender(session *input.Session, cfg *config.WidgetConfig) bool {
 	// Get threshold from config (default: 40%)
-	threshold := GetExtraInt(cfg, "show_when_above", 40)
+	threshold := compactETAOptions.Int(cfg, "show_when_above")
 
 	pct := session.ContextWindow.UsedPercentage
 	durationMs := session.Cost.TotalDurationMs
@@ -13,21 +13,28 @@ import (
 
 // ConfigCountsWidget displays Claude configuration counts.
 //
-// Supported Extra options:
-//   - show_claude_md: "true"/"false" - show CLAUDE.md count (default: true)
-//   - show_rules: "true"/"false" - show permission rules count (default: true)
-//   - show_mcps: "true"/"false" - show MCP plugins count (default: true)
-//   - show_hooks: "true"/"false" - show hooks count (default: true)
+// Supported Extra options are declared in configCountsOptions.
 //
 // Output format: "2 CLAUDE.md | 3 rules | 2 MCPs | 1 hook"
 type ConfigCountsWidget struct {
 	counts *claudeconfig.Counts
 }
 
+var configCountsOptions = config.Options{
+	{Key: "show_claude_md", Type: config.OptionBool, Default: "true", Description: "Show CLAUDE.md count"},
+	{Key: "show_rules", Type: config.OptionBool, Default: "true", Description: "Show permission rules count"},
+	{Key: "show_mcps", Type: config.OptionBool, Default: "true", Description: "Show MCP plugins count"},
+	{Key: "show_hooks", Type: config.OptionBool, Default: "true", Description: "Show hooks count"},
+}
+
 func (w *ConfigCountsWidget) Name() string {
 	return "config_counts"
 }
 
+func (w *ConfigCountsWidget) Options() config.Options {
+	return configCountsOptions
+}
+
 // SetCounts sets the config counts data for this widget.
 func (w *ConfigCountsWidget) SetCounts(counts *claudeconfig.Counts) {
 	w.counts = counts
@@ -40,10 +47,10 @@ func (w *ConfigCountsWidget) Render(session *input.Session, cfg *config.WidgetCo
 		w.counts = claudeconfig.LoadCounts(cwd)
 	}
 
-	showClaudeMD := GetExtraBool(cfg, "show_claude_md", true)
-	showRules := GetExtraBool(cfg, "show_rules", true)
-	showMCPs := GetExtraBool(cfg, "show_mcps", true)
-	showHooks := GetExtraBool(cfg, "show_hooks", true)
+	showClaudeMD := configCountsOptions.Bool(cfg, "show_claude_md")
+	showRules := configCountsOptions.Bool(cfg, "show_rules")
+	showMCPs := configCountsOptions.Bool(cfg, "show_mcps")
+	showHooks := configCountsOptions.Bool(cfg, "show_hooks")
 
 	var parts []string
 
@@ -10,30 +10,37 @@ import (
 
 // ContextWidget displays context window usage percentage.
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - whether to show "Ctx:" prefix (default: true)
-//   - show_bar: "true"/"false" - whether to show progress bar (default: true)
-//   - bar_width: "10" - progress bar width in characters (default: 10)
-//   - warn_threshold: "60" - percentage for warning color (default: 60)
-//   - critical_threshold: "80" - percentage for critical/red color (default: 80)
+// Supported Extra options are declared in contextOptions.
 type ContextWidget struct{}
 
+var contextOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "true", Description: "Show 'Ctx:' prefix"},
+	{Key: "show_bar", Type: config.OptionBool, Default: "true", Description: "Show progress bar"},
+	{Key: "bar_width", Type: config.OptionInt, Default: optionNumber(DefaultBarWidth), Min: "1", Description: "Progress bar width"},
+	{Key: "warn_threshold", Type: config.OptionFloat, Default: optionNumber(ContextWarningPct), Min: "0", Max: "100", Description: "Warning threshold %"},
+	{Key: "critical_threshold", Type: config.OptionFloat, Default: optionNumber(ContextDangerPct), Min: "0", Max: "100", Description: "Critical threshold %"},
+}
+
 func (w *ContextWidget) Name() string {
 	return "context"
 }
 
+func (w *ContextWidget) Options() config.Options {
+	return contextOptions
+}
+
 func (w *ContextWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
 	pct := session.ContextWindow.UsedPercentage
-	warnThreshold := GetExtraFloat(cfg, "warn_threshold", ContextWarningPct)
-	criticalThreshold := GetExtraFloat(cfg, "critical_threshold", ContextDangerPct)
+	warnThreshold := contextOptions.Float(cfg, "warn_threshold")
+	criticalThreshold := contextOptions.Float(cfg, "critical_threshold")
 	color := ColorByThreshold(pct, warnThreshold, criticalThreshold)
 
 	pctStr := fmt.Sprintf("%.0f%%", pct)
 
 	// Build value with optional progress bar
 	var value string
-	if GetExtraBool(cfg, "show_bar", true) {
-		barWidth := GetExtraInt(cfg, "bar_width", DefaultBarWidth)
+	if contextOptions.Bool(cfg, "show_bar") {
+		barWidth := contextOptions.Int(cfg, "bar_width")
 		bar := ProgressBar(pct, barWidth)
 		value = pctStr + " " + bar
 	} else {
@@ -43,7 +50,7 @@ func (w *ContextWidget) Render(session *input.Session, cfg *config.WidgetConfig)
 	var text string
 	if cfg.Format != "" {
 		text = FormatOutput(cfg, "", value)
-	} else if GetExtraBool(cfg, "show_label", true) {
+	} else if contextOptions.Bool(cfg, "show_label") {
 		text = "Ctx: " + value
 	} else {
 		text = value
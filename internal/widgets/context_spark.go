@@ -12,17 +12,24 @@ var sparkChars = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
 
 // ContextSparkWidget displays a sparkline of recent context usage.
 //
-// Supported Extra options:
-//   - width: number of characters in sparkline (default: "8")
-//   - show_label: "true"/"false" - show prefix (default: false)
+// Supported Extra options are declared in contextSparkOptions.
 type ContextSparkWidget struct {
 	history *history.History
 }
 
+var contextSparkOptions = config.Options{
+	{Key: "width", Type: config.OptionInt, Default: "8", Min: "1", Description: "Sparkline width"},
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Ctx:' prefix"},
+}
+
 func (w *ContextSparkWidget) Name() string {
 	return "context_spark"
 }
 
+func (w *ContextSparkWidget) Options() config.Options {
+	return contextSparkOptions
+}
+
 // SetHistory sets the history for this widget.
 func (w *ContextSparkWidget) SetHistory(h *history.History) {
 	w.history = h
@@ -33,7 +40,7 @@ func (w *ContextSparkWidget) Render(session *input.Session, cfg *config.WidgetCo
 		return render.Colorize("—", "dim")
 	}
 
-	width := GetExtraInt(cfg, "width", 8)
+	width := contextSparkOptions.Int(cfg, "width")
 	values := w.history.GetContextHistory(width)
 
 	if len(values) < 2 {
@@ -47,7 +54,7 @@ func (w *ContextSparkWidget) Render(session *input.Session, cfg *config.WidgetCo
 	var text string
 	if cfg.Format != "" {
 		text = FormatOutput(cfg, "", spark)
-	} else if GetExtraBool(cfg, "show_label", false) {
+	} else if contextSparkOptions.Bool(cfg, "show_label") {
 		text = "Ctx: " + spark
 	} else {
 		text = spark
@@ -10,16 +10,23 @@ import (
 
 // CostWidget displays the total API cost.
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - whether to show "Cost:" prefix (default: false)
-//   - warn_threshold: "0.5" - USD amount for warning color (default: 0.5)
-//   - critical_threshold: "1.0" - USD amount for critical/red color (default: 1.0)
+// Supported Extra options are declared in costOptions.
 type CostWidget struct{}
 
+var costOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Cost:' prefix"},
+	{Key: "warn_threshold", Type: config.OptionFloat, Default: optionNumber(CostWarningUSD), Min: "0", Description: "Warning threshold (USD)"},
+	{Key: "critical_threshold", Type: config.OptionFloat, Default: optionNumber(CostDangerUSD), Min: "0", Description: "Critical threshold (USD)"},
+}
+
 func (w *CostWidget) Name() string {
 	return "cost"
 }
 
+func (w *CostWidget) Options() config.Options {
+	return costOptions
+}
+
 func (w *CostWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
 	cost := session.Cost.TotalCostUSD
 
@@ -36,14 +43,14 @@ func (w *CostWidget) Render(session *input.Session, cfg *config.WidgetConfig) st
 	var text string
 	if cfg.Format != "" {
 		text = FormatOutput(cfg, "", value)
-	} else if GetExtraBool(cfg, "show_label", false) {
+	} else if costOptions.Bool(cfg, "show_label") {
 		text = "Cost: " + value
 	} else {
 		text = value
 	}
 
-	warnThreshold := GetExtraFloat(cfg, "warn_threshold", CostWarningUSD)
-	criticalThreshold := GetExtraFloat(cfg, "critical_threshold", CostDangerUSD)
+	warnThreshold := costOptions.Float(cfg, "warn_threshold")
+	criticalThreshold := costOptions.Float(cfg, "critical_threshold")
 	color := ColorByThreshold(cost, warnThreshold, criticalThreshold)
 	return render.Colorize(text, color)
 }
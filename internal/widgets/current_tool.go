@@ -0,0 +1,86 @@
+package widgets
+
+import (
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/render"
+	"github.com/namyoungkim/visor/internal/transcript"
+)
+
+// CurrentToolWidget displays the tool call that is currently in flight,
+// with what it operates on and how long it has been running.
+//
+// Supported Extra options are declared in currentToolOptions.
+//
+// Output format: "Bash: go test ./... (37s)", "Edit: internal/render/layout.go (2s)"
+// File paths under the session CWD are shown relative to it. Values that look
+// like secrets (tokens, passwords, URL credentials) are masked as "***".
+type CurrentToolWidget struct {
+	transcript *transcript.Data
+}
+
+var currentToolOptions = config.Options{
+	{Key: "show_target", Type: config.OptionBool, Default: "true", Description: "Show command/path/pattern/URL"},
+	{Key: "show_elapsed", Type: config.OptionBool, Default: "true", Description: "Show elapsed time"},
+	{Key: "max_target_len", Type: config.OptionInt, Default: "40", Min: "0", Description: "Max target length"},
+}
+
+func (w *CurrentToolWidget) Name() string {
+	return "current_tool"
+}
+
+func (w *CurrentToolWidget) Options() config.Options {
+	return currentToolOptions
+}
+
+// SetTranscript sets the transcript data for this widget.
+func (w *CurrentToolWidget) SetTranscript(t *transcript.Data) {
+	w.transcript = t
+}
+
+func (w *CurrentToolWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
+	if w.transcript == nil {
+		return ""
+	}
+	call := w.transcript.CurrentCall()
+	if call == nil {
+		return ""
+	}
+
+	text := render.Colorize(call.Name, "yellow")
+
+	if currentToolOptions.Bool(cfg, "show_target") && call.Target != "" {
+		target := call.Target
+		if isFileTool(call.Name) {
+			target = relativeToCWD(target, session.GetCurrentDir())
+		}
+		maxLen := currentToolOptions.Int(cfg, "max_target_len")
+		if maxLen > 0 {
+			target = truncateString(target, maxLen)
+		}
+		text += ": " + target
+	}
+
+	if currentToolOptions.Bool(cfg, "show_elapsed") && call.StartTime > 0 {
+		elapsedSec := (nowUnixMilli() - call.StartTime) / 1000
+		if elapsedSec < 0 {
+			elapsedSec = 0
+		}
+		text += render.Colorize(" ("+formatDurationSec(elapsedSec)+")", "dim")
+	}
+
+	return text
+}
+
+func (w *CurrentToolWidget) ShouldRender(session *input.Session, cfg *config.WidgetConfig) bool {
+	return w.transcript != nil && w.transcript.CurrentCall() != nil
+}
+
+// isFileTool reports whether a tool's target is a file path.
+func isFileTool(name string) bool {
+	switch name {
+	case "Read", "Edit", "MultiEdit", "Write", "NotebookEdit":
+		return true
+	}
+	return false
+}
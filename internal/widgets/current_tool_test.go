@@ -0,0 +1,91 @@
+package widgets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/transcript"
+)
+
+func TestCurrentToolWidget_Render(t *testing.T) {
+	originalNow := nowUnixMilli
+	nowUnixMilli = func() int64 { return 38000 }
+	defer func() { nowUnixMilli = originalNow }()
+
+	tests := []struct {
+		name     string
+		call     transcript.ToolCall
+		cwd      string
+		extra    map[string]string
+		expected string
+	}{
+		{
+			name:     "bash command with elapsed",
+			call:     transcript.ToolCall{Name: "Bash", Target: "go test ./...", StartTime: 1000},
+			expected: "Bash: go test ./... (37s)",
+		},
+		{
+			name:     "file path relative to cwd",
+			call:     transcript.ToolCall{Name: "Edit", Target: "/repo/internal/render/layout.go", StartTime: 36000},
+			cwd:      "/repo",
+			expected: "Edit: internal/render/layout.go (2s)",
+		},
+		{
+			name:     "file path outside cwd stays absolute",
+			call:     transcript.ToolCall{Name: "Read", Target: "/etc/hosts", StartTime: 38000},
+			cwd:      "/repo",
+			expected: "Read: /etc/hosts (0s)",
+		},
+		{
+			name:     "long target truncated",
+			call:     transcript.ToolCall{Name: "Grep", Target: "func (w \\*\\w+Widget) Render", StartTime: 1000},
+			extra:    map[string]string{"max_target_len": "10", "show_elapsed": "false"},
+			expected: "Grep: func (w...",
+		},
+		{
+			name:     "target hidden",
+			call:     transcript.ToolCall{Name: "WebFetch", Target: "https://example.com"},
+			extra:    map[string]string{"show_target": "false"},
+			expected: "WebFetch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &CurrentToolWidget{}
+			w.SetTranscript(&transcript.Data{Calls: []transcript.ToolCall{tt.call}})
+
+			result := stripANSI(w.Render(&input.Session{CWD: tt.cwd}, &config.WidgetConfig{Extra: tt.extra}))
+			if result != tt.expected {
+				t.Errorf("Render() = '%s', want '%s'", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCurrentToolWidget_NothingRunning(t *testing.T) {
+	w := &CurrentToolWidget{}
+	w.SetTranscript(&transcript.Data{
+		Calls: []transcript.ToolCall{{Name: "Bash", Target: "ls", Done: true}},
+	})
+
+	if w.ShouldRender(&input.Session{}, &config.WidgetConfig{}) {
+		t.Error("expected ShouldRender=false when no call is running")
+	}
+	if result := w.Render(&input.Session{}, &config.WidgetConfig{}); strings.TrimSpace(result) != "" {
+		t.Errorf("expected empty output, got '%s'", result)
+	}
+}
+
+func TestCurrentToolWidget_InterruptedCall(t *testing.T) {
+	w := &CurrentToolWidget{}
+	w.SetTranscript(&transcript.Data{
+		Calls: []transcript.ToolCall{{Name: "Bash", Target: "sleep 600", StartTime: 1000, Interrupted: true}},
+	})
+
+	if w.ShouldRender(&input.Session{}, &config.WidgetConfig{}) {
+		t.Error("expected ShouldRender=false for an interrupted call")
+	}
+}
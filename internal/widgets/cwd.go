@@ -12,26 +12,33 @@ import (
 
 // CWDWidget displays the current working directory path.
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - show "CWD:" prefix (default: false)
-//   - show_basename: "true"/"false" - show only directory name (default: false)
-//   - max_length: maximum path length, 0 = auto (terminal width / 3). Truncates with "…/" prefix.
+// Supported Extra options are declared in cwdOptions.
 //
 // Output format: "~/project/visor" or "CWD: visor"
 type CWDWidget struct{}
 
+var cwdOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'CWD:' prefix"},
+	{Key: "show_basename", Type: config.OptionBool, Default: "false", Description: "Show only directory name"},
+	{Key: "max_length", Type: config.OptionInt, Default: "0", Min: "0", Description: "Max path length (0 = terminal width / 3)"},
+}
+
 func (w *CWDWidget) Name() string {
 	return "cwd"
 }
 
+func (w *CWDWidget) Options() config.Options {
+	return cwdOptions
+}
+
 func (w *CWDWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
-	cwd := session.CWD
+	cwd := session.GetCurrentDir()
 	if cwd == "" {
 		return ""
 	}
 
-	showBasename := GetExtraBool(cfg, "show_basename", false)
-	maxLen := GetExtraInt(cfg, "max_length", 0)
+	showBasename := cwdOptions.Bool(cfg, "show_basename")
+	maxLen := cwdOptions.Int(cfg, "max_length")
 
 	var display string
 	if showBasename {
@@ -45,11 +52,12 @@ func (w *CWDWidget) Render(session *input.Session, cfg *config.WidgetConfig) str
 		maxLen = render.TerminalWidth() / 3
 	}
 
-	if len([]rune(display)) > maxLen {
+	if render.StringWidth(display) > maxLen {
 		display = truncatePath(display, maxLen)
 	}
 
-	if GetExtraBool(cfg, "show_label", false) {
+	display = render.Hyperlink(display, render.FileURL(cwd))
+	if cwdOptions.Bool(cfg, "show_label") {
 		display = "CWD: " + display
 	}
 
@@ -57,7 +65,7 @@ func (w *CWDWidget) Render(session *input.Session, cfg *config.WidgetConfig) str
 }
 
 func (w *CWDWidget) ShouldRender(session *input.Session, cfg *config.WidgetConfig) bool {
-	return session.CWD != ""
+	return session.GetCurrentDir() != ""
 }
 
 // abbreviateHome replaces the home directory prefix with ~.
@@ -75,23 +83,31 @@ func abbreviateHome(path string) string {
 	return path
 }
 
-// truncatePath shortens a path to fit within maxLen (in runes) by replacing
-// leading segments with "…/". Uses rune counting for proper Unicode handling.
+// relativeToCWD returns path relative to cwd when it lies inside cwd,
+// otherwise the home-abbreviated path.
+func relativeToCWD(path, cwd string) string {
+	if cwd != "" && filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(cwd, path); err == nil && rel != ".." && !strings.HasPrefix(rel, "../") {
+			return rel
+		}
+	}
+	return abbreviateHome(path)
+}
+
+// truncatePath shortens a path to fit within maxLen display cells by
+// replacing leading segments with "…/". Cuts on grapheme cluster boundaries.
 func truncatePath(path string, maxLen int) string {
-	runes := []rune(path)
-	if len(runes) <= maxLen {
+	if render.StringWidth(path) <= maxLen {
 		return path
 	}
-	if maxLen < 2 {
-		return string(runes[:maxLen])
+	ellipsis := render.StringWidth("…")
+	if maxLen <= ellipsis {
+		return render.Head(path, maxLen)
 	}
-	// "…" takes 1 rune, so we have maxLen-1 runes for the tail
-	tail := runes[len(runes)-(maxLen-1):]
+	tail := render.Tail(path, maxLen-ellipsis)
 	// Try to find a '/' boundary for cleaner truncation
-	for i, r := range tail {
-		if r == '/' {
-			return "…" + string(tail[i:])
-		}
+	if i := strings.IndexByte(tail, '/'); i >= 0 {
+		return "…" + tail[i:]
 	}
-	return "…" + string(tail)
+	return "…" + tail
 }
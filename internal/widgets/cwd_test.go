@@ -7,6 +7,7 @@ import (
 
 	"github.com/namyoungkim/visor/internal/config"
 	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/render"
 )
 
 func TestCWDWidget_Empty(t *testing.T) {
@@ -245,15 +246,16 @@ func TestTruncatePath(t *testing.T) {
 		{"maxLen 2", "/a/b/c", 2, "…c"},
 		{"maxLen 3", "/a/b/c", 3, "…/c"},
 		{"non-ascii path", "/home/프로젝트/visor", 8, "…/visor"},
-		{"non-ascii truncate boundary", "/프/로/젝/트", 5, "…/젝/트"},
+		{"non-ascii truncate boundary", "/프/로/젝/트", 5, "…/트"},
+		{"wide fits by width", "/프/로", 6, "/프/로"},
+		{"emoji segment", "/a/🧑‍💻/b", 5, "…/b"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := truncatePath(tt.path, tt.maxLen)
-			runes := []rune(result)
-			if len(runes) > tt.maxLen {
-				t.Errorf("Result rune length %d exceeds maxLen %d: '%s'", len(runes), tt.maxLen, result)
+			if width := render.StringWidth(result); width > tt.maxLen {
+				t.Errorf("Result width %d exceeds maxLen %d: '%s'", width, tt.maxLen, result)
 			}
 			if result != tt.expected {
 				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
@@ -261,3 +263,21 @@ func TestTruncatePath(t *testing.T) {
 		})
 	}
 }
+
+func TestCWDWidget_Hyperlink(t *testing.T) {
+	render.SetHyperlinks(true)
+	defer render.SetHyperlinks(false)
+
+	w := &CWDWidget{}
+	session := &input.Session{CWD: "/srv/app"}
+
+	result := w.Render(session, &config.WidgetConfig{
+		Extra: map[string]string{"show_label": "true"},
+	})
+	if !strings.Contains(result, "CWD: \033]8;;"+render.FileURL("/srv/app")+"\033\\") {
+		t.Errorf("Expected label outside a file:// link, got %q", result)
+	}
+	if got := render.VisibleLength(result); got != len("CWD: /srv/app") {
+		t.Errorf("Expected visible width %d, got %d", len("CWD: /srv/app"), got)
+	}
+}
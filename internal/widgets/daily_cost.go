@@ -17,18 +17,25 @@ const (
 
 // DailyCostWidget displays today's aggregated cost.
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - whether to show "Today:" prefix (default: false)
-//   - warn_threshold: "5.0" - USD for warning color (default: 5.0)
-//   - critical_threshold: "10.0" - USD for critical/red color (default: 10.0)
+// Supported Extra options are declared in dailyCostOptions.
 type DailyCostWidget struct {
 	costData *cost.CostData
 }
 
+var dailyCostOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Today:' prefix"},
+	{Key: "warn_threshold", Type: config.OptionFloat, Default: optionNumber(DailyCostWarningUSD), Min: "0", Description: "Warning threshold (USD)"},
+	{Key: "critical_threshold", Type: config.OptionFloat, Default: optionNumber(DailyCostCriticalUSD), Min: "0", Description: "Critical threshold (USD)"},
+}
+
 func (w *DailyCostWidget) Name() string {
 	return "daily_cost"
 }
 
+func (w *DailyCostWidget) Options() config.Options {
+	return dailyCostOptions
+}
+
 // SetCostData sets the cost data for this widget.
 func (w *DailyCostWidget) SetCostData(data *cost.CostData) {
 	w.costData = data
@@ -44,14 +51,14 @@ func (w *DailyCostWidget) Render(session *input.Session, cfg *config.WidgetConfi
 	var text string
 	if cfg.Format != "" {
 		text = FormatOutput(cfg, "", value)
-	} else if GetExtraBool(cfg, "show_label", false) {
+	} else if dailyCostOptions.Bool(cfg, "show_label") {
 		text = "Today: " + value
 	} else {
 		text = value
 	}
 
-	warnThreshold := GetExtraFloat(cfg, "warn_threshold", DailyCostWarningUSD)
-	criticalThreshold := GetExtraFloat(cfg, "critical_threshold", DailyCostCriticalUSD)
+	warnThreshold := dailyCostOptions.Float(cfg, "warn_threshold")
+	criticalThreshold := dailyCostOptions.Float(cfg, "critical_threshold")
 	color := ColorByThreshold(w.costData.Today, warnThreshold, criticalThreshold)
 	return render.Colorize(text, color)
 }
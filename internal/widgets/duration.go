@@ -10,16 +10,23 @@ import (
 
 // DurationWidget displays the session duration.
 //
-// Supported Extra options:
-//   - show_icon: "true"/"false" - show ⏱️ prefix (default: true)
+// Supported Extra options are declared in durationOptions.
 //
 // Output format: "⏱️ 5m" or "45s" or "1h23m"
 type DurationWidget struct{}
 
+var durationOptions = config.Options{
+	{Key: "show_icon", Type: config.OptionBool, Default: "true", Description: "Show ⏱️ icon prefix"},
+}
+
 func (w *DurationWidget) Name() string {
 	return "duration"
 }
 
+func (w *DurationWidget) Options() config.Options {
+	return durationOptions
+}
+
 func (w *DurationWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
 	ms := session.Cost.TotalDurationMs
 	if ms <= 0 {
@@ -27,7 +34,7 @@ func (w *DurationWidget) Render(session *input.Session, cfg *config.WidgetConfig
 	}
 
 	duration := formatDurationMs(ms)
-	showIcon := GetExtraBool(cfg, "show_icon", true)
+	showIcon := durationOptions.Bool(cfg, "show_icon")
 
 	var text string
 	if showIcon {
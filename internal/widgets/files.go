@@ -0,0 +1,81 @@
+package widgets
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/render"
+	"github.com/namyoungkim/visor/internal/transcript"
+)
+
+// FilesWidget displays how many files Claude read and modified this session,
+// and the most recently edited file.
+//
+// Supported Extra options are declared in filesOptions.
+//
+// Output format: "12 read · 4 edited · ✎ internal/render/layout.go"
+// Paths under the session CWD are shown relative to it. A file that was read
+// and then edited counts toward both numbers.
+type FilesWidget struct {
+	transcript *transcript.Data
+}
+
+var filesOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Files:' prefix"},
+	{Key: "show_last", Type: config.OptionBool, Default: "true", Description: "Show last edited file"},
+	{Key: "show_basename", Type: config.OptionBool, Default: "false", Description: "Show only the file name"},
+	{Key: "max_path_len", Type: config.OptionInt, Default: "30", Min: "0", Description: "Max path length (0 = full)"},
+}
+
+func (w *FilesWidget) Name() string {
+	return "files"
+}
+
+func (w *FilesWidget) Options() config.Options {
+	return filesOptions
+}
+
+// SetTranscript sets the transcript data for this widget.
+func (w *FilesWidget) SetTranscript(t *transcript.Data) {
+	w.transcript = t
+}
+
+func (w *FilesWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
+	if w.transcript == nil || len(w.transcript.Files) == 0 {
+		return ""
+	}
+
+	read, modified := w.transcript.FileCounts()
+
+	parts := []string{
+		render.Colorize(itoa(read)+" read", "cyan"),
+		render.Colorize(itoa(modified)+" edited", "yellow"),
+	}
+
+	if last := w.transcript.LastModifiedFile; last != "" && filesOptions.Bool(cfg, "show_last") {
+		var display string
+		if filesOptions.Bool(cfg, "show_basename") {
+			display = filepath.Base(last)
+		} else {
+			display = relativeToCWD(last, session.GetCurrentDir())
+		}
+		if maxLen := filesOptions.Int(cfg, "max_path_len"); maxLen > 0 {
+			display = truncatePath(display, maxLen)
+		}
+		parts = append(parts, render.Colorize("✎ "+display, "dim"))
+	}
+
+	text := strings.Join(parts, " · ")
+
+	if filesOptions.Bool(cfg, "show_label") {
+		text = "Files: " + text
+	}
+
+	return text
+}
+
+func (w *FilesWidget) ShouldRender(session *input.Session, cfg *config.WidgetConfig) bool {
+	return w.transcript != nil && len(w.transcript.Files) > 0
+}
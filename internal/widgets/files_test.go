@@ -0,0 +1,70 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/transcript"
+)
+
+func TestFilesWidget_Render(t *testing.T) {
+	data := &transcript.Data{
+		Files: []transcript.FileAccess{
+			{Path: "/repo/go.mod", Read: true},
+			{Path: "/repo/internal/render/layout.go", Read: true, Modified: true},
+			{Path: "/repo/internal/render/truncate.go", Modified: true},
+		},
+		LastModifiedFile: "/repo/internal/render/layout.go",
+	}
+
+	tests := []struct {
+		name     string
+		extra    map[string]string
+		expected string
+	}{
+		{
+			name:     "default shows counts and last edit relative to cwd",
+			expected: "2 read · 2 edited · ✎ internal/render/layout.go",
+		},
+		{
+			name:     "basename and label",
+			extra:    map[string]string{"show_basename": "true", "show_label": "true"},
+			expected: "Files: 2 read · 2 edited · ✎ layout.go",
+		},
+		{
+			name:     "truncated path",
+			extra:    map[string]string{"max_path_len": "12"},
+			expected: "2 read · 2 edited · ✎ …/layout.go",
+		},
+		{
+			name:     "hide last",
+			extra:    map[string]string{"show_last": "false"},
+			expected: "2 read · 2 edited",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &FilesWidget{}
+			w.SetTranscript(data)
+
+			result := stripANSI(w.Render(&input.Session{CWD: "/repo"}, &config.WidgetConfig{Extra: tt.extra}))
+			if result != tt.expected {
+				t.Errorf("Render() = '%s', want '%s'", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilesWidget_Empty(t *testing.T) {
+	w := &FilesWidget{}
+	if w.ShouldRender(&input.Session{}, &config.WidgetConfig{}) {
+		t.Error("expected ShouldRender=false for nil transcript")
+	}
+
+	w.SetTranscript(&transcript.Data{})
+	if result := w.Render(&input.Session{}, &config.WidgetConfig{}); result != "" {
+		t.Errorf("expected empty output, got '%s'", result)
+	}
+}
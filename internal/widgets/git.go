@@ -17,6 +17,10 @@ func (w *GitWidget) Name() string {
 	return "git"
 }
 
+func (w *GitWidget) Options() config.Options {
+	return nil
+}
+
 func (w *GitWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
 	status := git.GetStatus()
 	if !status.IsRepo {
@@ -30,7 +34,12 @@ func (w *GitWidget) Render(session *input.Session, cfg *config.WidgetConfig) str
 	if branch == "" {
 		branch = "HEAD"
 	}
-	parts = append(parts, render.Colorize("", "magenta")+render.Colorize(branch, "magenta"))
+	branchText := render.Colorize(branch, "magenta")
+	if render.HyperlinksEnabled() {
+		web := git.WebURL(git.RemoteURL(status.Branch))
+		branchText = render.Hyperlink(branchText, git.BranchURL(web, status.Branch, status.Ahead))
+	}
+	parts = append(parts, render.Colorize("", "magenta")+branchText)
 
 	// Status indicators (with spaces between)
 	var indicators []string
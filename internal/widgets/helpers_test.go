@@ -2,10 +2,12 @@ package widgets
 
 import (
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/namyoungkim/visor/internal/config"
 	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/render"
 )
 
 // stripANSI removes ANSI escape codes from a string for testing.
@@ -112,129 +114,6 @@ func TestFormatOutput(t *testing.T) {
 	}
 }
 
-func TestGetExtra(t *testing.T) {
-	cfg := &config.WidgetConfig{
-		Extra: map[string]string{
-			"key1": "value1",
-			"key2": "value2",
-		},
-	}
-
-	if v := GetExtra(cfg, "key1", "default"); v != "value1" {
-		t.Errorf("Expected 'value1', got '%s'", v)
-	}
-
-	if v := GetExtra(cfg, "missing", "default"); v != "default" {
-		t.Errorf("Expected 'default', got '%s'", v)
-	}
-
-	// Nil Extra map
-	cfgNil := &config.WidgetConfig{}
-	if v := GetExtra(cfgNil, "key", "default"); v != "default" {
-		t.Errorf("Expected 'default' for nil Extra, got '%s'", v)
-	}
-}
-
-func TestGetExtraBool(t *testing.T) {
-	cfg := &config.WidgetConfig{
-		Extra: map[string]string{
-			"enabled":  "true",
-			"disabled": "false",
-			"yes":      "yes",
-			"one":      "1",
-		},
-	}
-
-	tests := []struct {
-		key      string
-		defVal   bool
-		expected bool
-	}{
-		{"enabled", false, true},
-		{"disabled", true, false},
-		{"yes", false, true},
-		{"one", false, true},
-		{"missing", true, true},
-		{"missing", false, false},
-	}
-
-	for _, tt := range tests {
-		result := GetExtraBool(cfg, tt.key, tt.defVal)
-		if result != tt.expected {
-			t.Errorf("GetExtraBool(%q, %v) = %v, expected %v",
-				tt.key, tt.defVal, result, tt.expected)
-		}
-	}
-}
-
-func TestGetExtraInt(t *testing.T) {
-	cfg := &config.WidgetConfig{
-		Extra: map[string]string{
-			"width":   "15",
-			"count":   "0",
-			"invalid": "abc",
-		},
-	}
-
-	tests := []struct {
-		key      string
-		defVal   int
-		expected int
-	}{
-		{"width", 10, 15},
-		{"count", 5, 0},
-		{"invalid", 10, 10}, // Invalid number returns default
-		{"missing", 10, 10},
-	}
-
-	for _, tt := range tests {
-		result := GetExtraInt(cfg, tt.key, tt.defVal)
-		if result != tt.expected {
-			t.Errorf("GetExtraInt(%q, %d) = %d, expected %d",
-				tt.key, tt.defVal, result, tt.expected)
-		}
-	}
-}
-
-func TestGetExtraFloat(t *testing.T) {
-	cfg := &config.WidgetConfig{
-		Extra: map[string]string{
-			"threshold": "0.5",
-			"rate":      "10.25",
-			"zero":      "0",
-			"negative":  "-5.5",
-			"invalid":   "abc",
-		},
-	}
-
-	tests := []struct {
-		key      string
-		defVal   float64
-		expected float64
-	}{
-		{"threshold", 1.0, 0.5},
-		{"rate", 0.0, 10.25},
-		{"zero", 1.0, 0.0},
-		{"negative", 0.0, -5.5},
-		{"invalid", 1.0, 1.0},   // Invalid number returns default
-		{"missing", 99.9, 99.9}, // Missing key returns default
-	}
-
-	for _, tt := range tests {
-		result := GetExtraFloat(cfg, tt.key, tt.defVal)
-		if result != tt.expected {
-			t.Errorf("GetExtraFloat(%q, %.1f) = %.2f, expected %.2f",
-				tt.key, tt.defVal, result, tt.expected)
-		}
-	}
-
-	// Test nil Extra map
-	cfgNil := &config.WidgetConfig{}
-	if v := GetExtraFloat(cfgNil, "key", 42.0); v != 42.0 {
-		t.Errorf("Expected 42.0 for nil Extra, got %.1f", v)
-	}
-}
-
 func TestProgressBar(t *testing.T) {
 	tests := []struct {
 		pct      float64
@@ -286,6 +165,52 @@ func TestRenderAll(t *testing.T) {
 	}
 }
 
+func TestRenderAll_Style(t *testing.T) {
+	session := &input.Session{Cost: input.Cost{TotalCostUSD: 0.25}}
+
+	// A foreground color replaces the widget's own colors
+	result := RenderAll(session, []config.WidgetConfig{
+		{Name: "cost", Style: config.StyleConfig{Fg: "208", Italic: true}},
+	})
+	if want := render.Italic + "\033[38;5;208m$0.25" + render.Reset; result[0] != want {
+		t.Errorf("RenderAll() = %q, want %q", result[0], want)
+	}
+
+	// Attributes and backgrounds keep them
+	result = RenderAll(session, []config.WidgetConfig{
+		{Name: "cost", Style: config.StyleConfig{Bg: "blue", Underline: true}},
+	})
+	if want := render.Underline + render.BgBlue + render.FgGreen + "$0.25" + render.Reset; result[0] != want {
+		t.Errorf("RenderAll() = %q, want %q", result[0], want)
+	}
+}
+
+func TestRenderAll_RawFieldPlaceholders(t *testing.T) {
+	session := input.Parse(strings.NewReader(`{
+		"cost": {"total_cost_usd": 0.25},
+		"version": "2.1.3",
+		"output_style": {"name": "Explanatory"}
+	}`))
+
+	widgets := []config.WidgetConfig{
+		{Name: "cost", Format: "{value} · CC {raw.version} ({raw.output_style.name}){raw.missing}"},
+	}
+
+	result := RenderAll(session, widgets)
+	if len(result) != 1 {
+		t.Fatalf("Expected 1 rendered widget, got %d", len(result))
+	}
+	want := "$0.25 · CC 2.1.3 (Explanatory)"
+	if got := stripANSI(result[0]); got != want {
+		t.Errorf("RenderAll() = %q, want %q", got, want)
+	}
+
+	// The widget config itself is not modified
+	if widgets[0].Format != "{value} · CC {raw.version} ({raw.output_style.name}){raw.missing}" {
+		t.Errorf("Format was modified: %q", widgets[0].Format)
+	}
+}
+
 func TestRenderAll_UnknownWidget(t *testing.T) {
 	session := &input.Session{
 		Model: input.Model{DisplayName: "Opus"},
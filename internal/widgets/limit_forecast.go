@@ -0,0 +1,165 @@
+package widgets
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/cost"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/render"
+	"github.com/namyoungkim/visor/internal/usage"
+)
+
+// LimitForecastWarningPct is the default projected utilization at reset
+// that turns a window yellow.
+const LimitForecastWarningPct = 80.0
+
+// LimitForecastWidget projects when the 5-hour and 7-day limits will be hit
+// at the current usage rate, relative to when they reset.
+//
+// The rate comes from recent weighted usage (last 30m for 5h, last 24h for 7d)
+// when local transcript data is available, otherwise from the average rate
+// since the window started.
+//
+// Output examples:
+//   - "5h: hits limit in 38m (resets in 1h12m)" - red, runs out before reset
+//   - "5h: ~64% at reset (resets in 1h12m)"     - green/yellow, lasts until reset
+//   - "7d: limit reached (resets in 2d4h)"      - red
+//
+// Supported Extra options are declared in limitForecastOptions.
+type LimitForecastWidget struct {
+	limits   *usage.Limits
+	costData *cost.CostData
+}
+
+var limitForecastOptions = config.Options{
+	{Key: "window", Type: config.OptionString, Default: "both", Enum: []string{"5h", "7d", "both"}, Description: "Windows to forecast"},
+	{Key: "rate", Type: config.OptionString, Default: "recent", Enum: []string{"recent", "average"}, Description: "Rate: recent usage or average since window start"},
+	{Key: "show_label", Type: config.OptionBool, Default: "true", Description: "Show '5h:'/'7d:' prefixes"},
+	{Key: "show_reset", Type: config.OptionBool, Default: "true", Description: "Show time until reset"},
+	{Key: "warn_threshold", Type: config.OptionFloat, Default: optionNumber(LimitForecastWarningPct), Min: "0", Max: "100", Description: "Warning threshold (% at reset)"},
+	{Key: "hide_when_safe", Type: config.OptionBool, Default: "false", Description: "Only show windows that run out before reset"},
+}
+
+func (w *LimitForecastWidget) Name() string {
+	return "limit_forecast"
+}
+
+func (w *LimitForecastWidget) Options() config.Options {
+	return limitForecastOptions
+}
+
+// SetLimits sets the usage limits for this widget.
+func (w *LimitForecastWidget) SetLimits(limits *usage.Limits) {
+	w.limits = limits
+}
+
+// SetCostData sets the local usage data used for recent rates.
+func (w *LimitForecastWidget) SetCostData(data *cost.CostData) {
+	w.costData = data
+}
+
+// windowForecast is a forecast for one labeled limit window.
+type windowForecast struct {
+	label    string
+	forecast usage.Forecast
+}
+
+func (w *LimitForecastWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
+	forecasts := w.forecasts(cfg)
+	if len(forecasts) == 0 {
+		return render.Colorize("—", "gray")
+	}
+
+	warnThreshold := limitForecastOptions.Float(cfg, "warn_threshold")
+	showLabel := limitForecastOptions.Bool(cfg, "show_label")
+	showReset := limitForecastOptions.Bool(cfg, "show_reset")
+
+	var parts []string
+	for _, wf := range forecasts {
+		f := wf.forecast
+
+		var value, color string
+		switch {
+		case f.Reached():
+			value, color = "limit reached", "red"
+		case f.WillHit():
+			value, color = "hits limit in "+formatForecastDuration(f.HitsIn), "red"
+		default:
+			value, color = fmt.Sprintf("~%.0f%% at reset", f.AtReset), "green"
+			if f.AtReset >= warnThreshold {
+				color = "yellow"
+			}
+		}
+		if showReset {
+			value += fmt.Sprintf(" (resets in %s)", formatForecastDuration(f.ResetsIn))
+		}
+
+		var text string
+		if cfg.Format != "" {
+			text = FormatOutput(cfg, "", value)
+		} else if showLabel {
+			text = wf.label + ": " + value
+		} else {
+			text = value
+		}
+		parts = append(parts, render.Colorize(text, color))
+	}
+
+	return strings.Join(parts, " · ")
+}
+
+func (w *LimitForecastWidget) ShouldRender(session *input.Session, cfg *config.WidgetConfig) bool {
+	return len(w.forecasts(cfg)) > 0
+}
+
+// forecasts returns forecasts for the configured windows, skipping windows
+// without data (and safe windows when hide_when_safe is set).
+func (w *LimitForecastWidget) forecasts(cfg *config.WidgetConfig) []windowForecast {
+	if w.limits == nil {
+		return nil
+	}
+
+	window := limitForecastOptions.Value(cfg, "window")
+	useRecent := limitForecastOptions.Value(cfg, "rate") == "recent"
+	hideSafe := limitForecastOptions.Bool(cfg, "hide_when_safe")
+	now := time.Now()
+
+	// Share of each window's usage that is recent; -1 selects the average rate
+	fiveShare, sevenShare := -1.0, -1.0
+	if useRecent && w.costData != nil {
+		fiveShare = usage.RecentShare(w.costData.RecentWeighted, w.costData.FiveHourBlockWeighted)
+		sevenShare = usage.RecentShare(w.costData.LastDayWeighted, w.costData.WeekWeighted)
+	}
+
+	var result []windowForecast
+	add := func(label string, f usage.Forecast, ok bool) {
+		if ok && (!hideSafe || f.WillHit()) {
+			result = append(result, windowForecast{label: label, forecast: f})
+		}
+	}
+
+	if window == "5h" || window == "both" {
+		f, ok := usage.ForecastLimit(w.limits.FiveHour.Utilization, w.limits.FiveHour.ResetsAt,
+			usage.FiveHourWindow, fiveShare, cost.RecentWindow, now)
+		add("5h", f, ok)
+	}
+	if window == "7d" || window == "both" {
+		f, ok := usage.ForecastLimit(w.limits.SevenDay.Utilization, w.limits.SevenDay.ResetsAt,
+			usage.SevenDayWindow, sevenShare, cost.DayWindow, now)
+		add("7d", f, ok)
+	}
+	return result
+}
+
+// formatForecastDuration formats a duration, using days beyond 24 hours.
+func formatForecastDuration(d time.Duration) string {
+	if d >= 24*time.Hour {
+		days := int(d.Hours()) / 24
+		hours := int(d.Hours()) % 24
+		return fmt.Sprintf("%dd%dh", days, hours)
+	}
+	return formatDuration(d)
+}
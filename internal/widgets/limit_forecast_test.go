@@ -0,0 +1,110 @@
+package widgets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/cost"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/usage"
+)
+
+func TestLimitForecastWidget_Render(t *testing.T) {
+	now := time.Now()
+	// 2h into the block at 40%, resets in 3h (+30s so minutes round down cleanly)
+	limits := &usage.Limits{
+		FiveHour: usage.FiveHourLimit{Utilization: 40, ResetsAt: now.Add(3*time.Hour + 30*time.Second)},
+		SevenDay: usage.SevenDayLimit{Utilization: 100, ResetsAt: now.Add(52*time.Hour + 30*time.Second)},
+	}
+
+	tests := []struct {
+		name     string
+		limits   *usage.Limits
+		costData *cost.CostData
+		extra    map[string]string
+		want     string
+	}{
+		{
+			// Half of the block's usage in the last 30m: 40%/h → 100% in 1h30m
+			name:     "hits before reset",
+			costData: &cost.CostData{FiveHourBlockWeighted: 1000, RecentWeighted: 500},
+			extra:    map[string]string{"window": "5h"},
+			want:     "5h: hits limit in 1h30m (resets in 3h0m)",
+		},
+		{
+			// Nothing recent: stays at 40%
+			name:     "lasts until reset",
+			costData: &cost.CostData{FiveHourBlockWeighted: 1000},
+			extra:    map[string]string{"window": "5h"},
+			want:     "5h: ~40% at reset (resets in 3h0m)",
+		},
+		{
+			// Average rate 10%/h over 2h → ~50% at reset
+			name: "average rate without local data",
+			limits: &usage.Limits{
+				FiveHour: usage.FiveHourLimit{Utilization: 20, ResetsAt: now.Add(3 * time.Hour)},
+			},
+			extra: map[string]string{"window": "5h", "show_reset": "false"},
+			want:  "5h: ~50% at reset",
+		},
+		{
+			name:  "weekly limit reached",
+			extra: map[string]string{"window": "7d"},
+			want:  "7d: limit reached (resets in 2d4h)",
+		},
+		{
+			name:     "both windows without labels",
+			costData: &cost.CostData{FiveHourBlockWeighted: 1000},
+			extra:    map[string]string{"show_label": "false", "show_reset": "false"},
+			want:     "~40% at reset · limit reached",
+		},
+		{
+			name:     "hide safe windows",
+			costData: &cost.CostData{FiveHourBlockWeighted: 1000},
+			extra:    map[string]string{"hide_when_safe": "true", "show_reset": "false"},
+			want:     "7d: limit reached",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &LimitForecastWidget{}
+			if tt.limits != nil {
+				w.SetLimits(tt.limits)
+			} else {
+				w.SetLimits(limits)
+			}
+			w.SetCostData(tt.costData)
+			got := stripANSI(w.Render(&input.Session{}, &config.WidgetConfig{Extra: tt.extra}))
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitForecastWidget_ShouldRender(t *testing.T) {
+	w := &LimitForecastWidget{}
+	session := &input.Session{}
+	cfg := &config.WidgetConfig{}
+
+	if w.ShouldRender(session, cfg) {
+		t.Error("ShouldRender() = true without limits, want false")
+	}
+
+	w.SetLimits(&usage.Limits{})
+	if w.ShouldRender(session, cfg) {
+		t.Error("ShouldRender() = true without utilization, want false")
+	}
+
+	w.SetLimits(&usage.Limits{
+		FiveHour: usage.FiveHourLimit{Utilization: 10, ResetsAt: time.Now().Add(4 * time.Hour)},
+	})
+	if !w.ShouldRender(session, cfg) {
+		t.Error("ShouldRender() = false with 5h data, want true")
+	}
+	if w.ShouldRender(session, &config.WidgetConfig{Extra: map[string]string{"hide_when_safe": "true"}}) {
+		t.Error("ShouldRender() = true for a safe window with hide_when_safe, want false")
+	}
+}
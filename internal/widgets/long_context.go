@@ -0,0 +1,43 @@
+package widgets
+
+import (
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/render"
+)
+
+// LongContextWidget warns when the context has grown past 200k tokens,
+// where long-context pricing applies and quality may degrade.
+// Renders only while Claude Code reports exceeds_200k_tokens.
+//
+// Supported Extra options are declared in longContextOptions.
+type LongContextWidget struct{}
+
+var longContextOptions = config.Options{
+	{Key: "text", Type: config.OptionString, Default: "⚠ >200k", Description: "Warning text"},
+	{Key: "color", Type: config.OptionString, Default: "yellow", Description: "Warning color"},
+}
+
+func (w *LongContextWidget) Name() string {
+	return "long_context"
+}
+
+func (w *LongContextWidget) Options() config.Options {
+	return longContextOptions
+}
+
+func (w *LongContextWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
+	if !session.ExceedsTokens {
+		return ""
+	}
+
+	text := longContextOptions.Value(cfg, "text")
+	if cfg.Format != "" {
+		text = FormatOutput(cfg, "", text)
+	}
+	return render.Colorize(text, longContextOptions.Value(cfg, "color"))
+}
+
+func (w *LongContextWidget) ShouldRender(session *input.Session, cfg *config.WidgetConfig) bool {
+	return session.ExceedsTokens
+}
@@ -0,0 +1,36 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+)
+
+func TestLongContextWidget_Render(t *testing.T) {
+	w := &LongContextWidget{}
+
+	tests := []struct {
+		name     string
+		exceeds  bool
+		extra    map[string]string
+		expected string
+	}{
+		{"below 200k", false, nil, ""},
+		{"above 200k", true, nil, "⚠ >200k"},
+		{"custom text", true, map[string]string{"text": "LONG"}, "LONG"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &input.Session{ExceedsTokens: tt.exceeds}
+			result := stripANSI(w.Render(session, &config.WidgetConfig{Extra: tt.extra}))
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+			if got := w.ShouldRender(session, &config.WidgetConfig{}); got != tt.exceeds {
+				t.Errorf("ShouldRender = %v, want %v", got, tt.exceeds)
+			}
+		})
+	}
+}
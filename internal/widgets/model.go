@@ -13,6 +13,10 @@ func (w *ModelWidget) Name() string {
 	return "model"
 }
 
+func (w *ModelWidget) Options() config.Options {
+	return nil
+}
+
 func (w *ModelWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
 	name := session.Model.DisplayName
 	if name == "" {
@@ -0,0 +1,22 @@
+package widgets
+
+import (
+	"strconv"
+
+	"github.com/namyoungkim/visor/internal/config"
+)
+
+// optionNumber formats a threshold constant as an option default.
+func optionNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// Schema returns the option schema of every registered widget, for
+// config.Check.
+func Schema() config.Schema {
+	schema := make(config.Schema, len(Registry))
+	for name, w := range Registry {
+		schema[name] = w.Options()
+	}
+	return schema
+}
@@ -0,0 +1,63 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+)
+
+func TestWidgetOptions_Declarations(t *testing.T) {
+	for _, name := range Names() {
+		w, _ := Get(name)
+		seen := make(map[string]bool)
+		for _, opt := range w.Options() {
+			if seen[opt.Key] {
+				t.Errorf("%s: duplicate option %q", name, opt.Key)
+			}
+			seen[opt.Key] = true
+
+			if opt.Description == "" {
+				t.Errorf("%s.%s: missing description", name, opt.Key)
+			}
+			if opt.Default != "" || opt.Type != config.OptionString {
+				if err := opt.Validate(opt.Default); err != nil {
+					t.Errorf("%s.%s: default %q is invalid: %v", name, opt.Key, opt.Default, err)
+				}
+			}
+			if (opt.Min != "" || opt.Max != "") && opt.Type != config.OptionInt && opt.Type != config.OptionFloat {
+				t.Errorf("%s.%s: range on non-numeric option", name, opt.Key)
+			}
+			if opt.Enum != nil && opt.Type != config.OptionString {
+				t.Errorf("%s.%s: enum on non-string option", name, opt.Key)
+			}
+		}
+	}
+}
+
+func TestWidgetOptions_DefaultsDriveRender(t *testing.T) {
+	// An out-of-range bar width falls back to the declared default (10)
+	session := &input.Session{ContextWindow: input.ContextWindow{UsedPercentage: 50}}
+	w := &ContextWidget{}
+
+	want := stripANSI(w.Render(session, &config.WidgetConfig{}))
+	got := stripANSI(w.Render(session, &config.WidgetConfig{Extra: map[string]string{"bar_width": "0"}}))
+	if got != want {
+		t.Errorf("bar_width=0: got %q, want default %q", got, want)
+	}
+
+	// An unknown enum value falls back to the declared default (summary)
+	if v := todosOptions.Value(&config.WidgetConfig{Extra: map[string]string{"mode": "bars"}}, "mode"); v != "summary" {
+		t.Errorf("todos mode = %q, want summary", v)
+	}
+}
+
+func TestNames_RegistrationOrder(t *testing.T) {
+	names := Names()
+	if len(names) != len(Registry) {
+		t.Fatalf("Names() = %d names, Registry has %d", len(names), len(Registry))
+	}
+	if names[0] != "model" {
+		t.Errorf("Names()[0] = %q, want model", names[0])
+	}
+}
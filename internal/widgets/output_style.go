@@ -0,0 +1,55 @@
+package widgets
+
+import (
+	"strings"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/render"
+)
+
+// OutputStyleWidget displays the active Claude Code output style.
+//
+// Supported Extra options are declared in outputStyleOptions.
+//
+// Output format: "✎ Explanatory" or "Style: Explanatory"
+type OutputStyleWidget struct{}
+
+var outputStyleOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Style:' instead of ✎"},
+	{Key: "hide_default", Type: config.OptionBool, Default: "true", Description: "Hide the default style"},
+}
+
+func (w *OutputStyleWidget) Name() string {
+	return "output_style"
+}
+
+func (w *OutputStyleWidget) Options() config.Options {
+	return outputStyleOptions
+}
+
+func (w *OutputStyleWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
+	name := session.OutputStyle.Name
+	if name == "" {
+		return ""
+	}
+
+	var text string
+	if cfg.Format != "" {
+		text = FormatOutput(cfg, "", name)
+	} else if outputStyleOptions.Bool(cfg, "show_label") {
+		text = "Style: " + name
+	} else {
+		text = "✎ " + name
+	}
+
+	return render.Colorize(text, "magenta")
+}
+
+func (w *OutputStyleWidget) ShouldRender(session *input.Session, cfg *config.WidgetConfig) bool {
+	name := session.OutputStyle.Name
+	if name == "" {
+		return false
+	}
+	return !outputStyleOptions.Bool(cfg, "hide_default") || !strings.EqualFold(name, "default")
+}
@@ -0,0 +1,59 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+)
+
+func TestOutputStyleWidget_Render(t *testing.T) {
+	w := &OutputStyleWidget{}
+
+	tests := []struct {
+		name     string
+		style    string
+		extra    map[string]string
+		expected string
+	}{
+		{"empty", "", nil, ""},
+		{"default icon", "Explanatory", nil, "✎ Explanatory"},
+		{"label", "Learning", map[string]string{"show_label": "true"}, "Style: Learning"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &input.Session{OutputStyle: input.OutputStyle{Name: tt.style}}
+			result := stripANSI(w.Render(session, &config.WidgetConfig{Extra: tt.extra}))
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestOutputStyleWidget_ShouldRender(t *testing.T) {
+	w := &OutputStyleWidget{}
+
+	tests := []struct {
+		name     string
+		style    string
+		extra    map[string]string
+		expected bool
+	}{
+		{"empty", "", nil, false},
+		{"custom style", "Explanatory", nil, true},
+		{"default hidden", "default", nil, false},
+		{"default shown", "default", map[string]string{"hide_default": "false"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &input.Session{OutputStyle: input.OutputStyle{Name: tt.style}}
+			result := w.ShouldRender(session, &config.WidgetConfig{Extra: tt.extra})
+			if result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
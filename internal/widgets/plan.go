@@ -19,19 +19,26 @@ import (
 //  4. OAuth credentials available (DefaultProvider) → "Pro"
 //  5. No API key and no bedrock/vertex → "Pro" (subscription assumed)
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - whether to show "Plan:" prefix (default: false)
+// Supported Extra options are declared in planOptions.
 //
 // Output format: "Pro" or "API" or "Bedrock" or "Vertex" (or "Plan: Pro" with show_label)
 type PlanWidget struct{}
 
+var planOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Plan:' prefix"},
+}
+
 func (w *PlanWidget) Name() string {
 	return "plan"
 }
 
+func (w *PlanWidget) Options() config.Options {
+	return planOptions
+}
+
 func (w *PlanWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
 	modelID := strings.ToLower(session.Model.ID)
-	showLabel := GetExtraBool(cfg, "show_label", false)
+	showLabel := planOptions.Bool(cfg, "show_label")
 
 	var text, color string
 	switch {
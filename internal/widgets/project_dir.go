@@ -0,0 +1,90 @@
+package widgets
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/render"
+)
+
+// ProjectDirWidget shows where the working directory is relative to the
+// project directory Claude Code was started in, so a `cd` away from the
+// project is noticed.
+//
+// Supported Extra options are declared in projectDirOptions.
+//
+// Output format:
+//   - "↳ internal/input"        - inside the project (cyan)
+//   - "⚠ ~/work/other"          - outside the project (yellow)
+//   - "visor ↳ internal/input"  - with show_project
+//   - "visor"                   - at the project root with show_when_same
+type ProjectDirWidget struct{}
+
+var projectDirOptions = config.Options{
+	{Key: "show_project", Type: config.OptionBool, Default: "false", Description: "Prefix the project name"},
+	{Key: "show_when_same", Type: config.OptionBool, Default: "false", Description: "Show at the project root"},
+}
+
+func (w *ProjectDirWidget) Name() string {
+	return "project_dir"
+}
+
+func (w *ProjectDirWidget) Options() config.Options {
+	return projectDirOptions
+}
+
+func (w *ProjectDirWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
+	project := session.Workspace.ProjectDir
+	current := session.GetCurrentDir()
+	if project == "" || current == "" {
+		return ""
+	}
+
+	name := filepath.Base(project)
+	showProject := projectDirOptions.Bool(cfg, "show_project")
+
+	rel, inside := relativeToProject(current, project)
+	var text, color string
+	switch {
+	case inside && rel == ".":
+		text, color = name, "cyan"
+	case inside:
+		text, color = "↳ "+rel, "cyan"
+		if showProject {
+			text = name + " " + text
+		}
+	default:
+		text, color = "⚠ "+abbreviateHome(current), "yellow"
+		if showProject {
+			text = name + " " + text
+		}
+	}
+
+	if cfg.Format != "" {
+		text = FormatOutput(cfg, "", text)
+	}
+	return render.Colorize(text, color)
+}
+
+func (w *ProjectDirWidget) ShouldRender(session *input.Session, cfg *config.WidgetConfig) bool {
+	project := session.Workspace.ProjectDir
+	current := session.GetCurrentDir()
+	if project == "" || current == "" {
+		return false
+	}
+	if rel, inside := relativeToProject(current, project); inside && rel == "." {
+		return projectDirOptions.Bool(cfg, "show_when_same")
+	}
+	return true
+}
+
+// relativeToProject returns dir relative to project and whether it is inside it.
+func relativeToProject(dir, project string) (string, bool) {
+	rel, err := filepath.Rel(filepath.Clean(project), filepath.Clean(dir))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", false
+	}
+	return rel, true
+}
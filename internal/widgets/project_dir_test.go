@@ -0,0 +1,65 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+)
+
+func projectSession(current, project string) *input.Session {
+	return &input.Session{Workspace: input.Workspace{CurrentDir: current, ProjectDir: project}}
+}
+
+func TestProjectDirWidget_Render(t *testing.T) {
+	w := &ProjectDirWidget{}
+
+	tests := []struct {
+		name     string
+		session  *input.Session
+		extra    map[string]string
+		expected string
+	}{
+		{"no project", &input.Session{CWD: "/work/visor"}, nil, ""},
+		{"at root", projectSession("/work/visor", "/work/visor"), nil, "visor"},
+		{"inside", projectSession("/work/visor/internal/input", "/work/visor"), nil, "↳ internal/input"},
+		{"inside with project", projectSession("/work/visor/internal", "/work/visor"), map[string]string{"show_project": "true"}, "visor ↳ internal"},
+		{"outside", projectSession("/work/other", "/work/visor"), nil, "⚠ /work/other"},
+		{"sibling prefix", projectSession("/work/visor-old", "/work/visor"), nil, "⚠ /work/visor-old"},
+		{"cwd fallback", &input.Session{CWD: "/work/visor/docs", Workspace: input.Workspace{ProjectDir: "/work/visor"}}, nil, "↳ docs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := stripANSI(w.Render(tt.session, &config.WidgetConfig{Extra: tt.extra}))
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestProjectDirWidget_ShouldRender(t *testing.T) {
+	w := &ProjectDirWidget{}
+
+	tests := []struct {
+		name     string
+		session  *input.Session
+		extra    map[string]string
+		expected bool
+	}{
+		{"no project", &input.Session{CWD: "/work/visor"}, nil, false},
+		{"at root", projectSession("/work/visor", "/work/visor/"), nil, false},
+		{"at root shown", projectSession("/work/visor", "/work/visor"), map[string]string{"show_when_same": "true"}, true},
+		{"diverged", projectSession("/work/visor/internal", "/work/visor"), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := w.ShouldRender(tt.session, &config.WidgetConfig{Extra: tt.extra})
+			if result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package widgets
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/namyoungkim/visor/internal/config"
+)
+
+// referenceMarker matches a generated option table in the widget reference
+// (docs/08_WIDGET_REFERENCE.md):
+//
+//	<!-- options:context -->
+//	...generated...
+//	<!-- /options -->
+var referenceMarker = regexp.MustCompile(`(?s)<!-- options:(\w+) -->\n.*?<!-- /options -->`)
+
+// UpdateReference regenerates the option tables in the widget reference
+// from each widget's declared options. Every registered widget must have
+// a table, and every table must name a registered widget.
+func UpdateReference(doc string) (string, error) {
+	var errs []string
+	seen := make(map[string]bool)
+	doc = referenceMarker.ReplaceAllStringFunc(doc, func(block string) string {
+		name := referenceMarker.FindStringSubmatch(block)[1]
+		w, ok := Get(name)
+		if !ok {
+			errs = append(errs, fmt.Sprintf("unknown widget %q", name))
+			return block
+		}
+		seen[name] = true
+		return fmt.Sprintf("<!-- options:%s -->\n%s<!-- /options -->", name, OptionTable(w.Options()))
+	})
+	for _, name := range Names() {
+		if !seen[name] {
+			errs = append(errs, fmt.Sprintf("no option table for widget %q", name))
+		}
+	}
+	if len(errs) > 0 {
+		return "", fmt.Errorf("widget reference: %s", strings.Join(errs, "; "))
+	}
+	return doc, nil
+}
+
+// OptionTable renders options as a Markdown table.
+func OptionTable(opts config.Options) string {
+	if len(opts) == 0 {
+		return "없음\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("| 옵션 | 타입 | 기본값 | 허용 값 | 설명 |\n")
+	sb.WriteString("|------|------|--------|---------|------|\n")
+	for _, opt := range opts {
+		def := "—"
+		if opt.Default != "" {
+			def = "`" + opt.Default + "`"
+		}
+		fmt.Fprintf(&sb, "| `%s` | %s | %s | %s | %s |\n",
+			opt.Key, opt.Type.Name(), def, allowedValues(opt), strings.ReplaceAll(opt.Description, "|", `\|`))
+	}
+	return sb.String()
+}
+
+// allowedValues formats an option's enum or numeric range for the docs.
+func allowedValues(opt config.Option) string {
+	switch {
+	case opt.Enum != nil:
+		values := make([]string, len(opt.Enum))
+		for i, v := range opt.Enum {
+			values[i] = "`" + v + "`"
+		}
+		return strings.Join(values, ", ")
+	case opt.Min != "" && opt.Max != "":
+		return opt.Min + "–" + opt.Max
+	case opt.Min != "":
+		return "≥ " + opt.Min
+	case opt.Max != "":
+		return "≤ " + opt.Max
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package widgets
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"testing"
+)
+
+var updateReference = flag.Bool("update", false, "rewrite the option tables in docs/08_WIDGET_REFERENCE.md")
+
+const referencePath = "../../docs/08_WIDGET_REFERENCE.md"
+
+// TestReference_UpToDate fails when the option tables in the widget
+// reference differ from the declared options. Regenerate with `make docs`.
+func TestReference_UpToDate(t *testing.T) {
+	data, err := os.ReadFile(referencePath)
+	if err != nil {
+		t.Fatalf("read reference: %v", err)
+	}
+
+	updated, err := UpdateReference(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated == string(data) {
+		return
+	}
+	if *updateReference {
+		if err := os.WriteFile(referencePath, []byte(updated), 0644); err != nil {
+			t.Fatalf("write reference: %v", err)
+		}
+		return
+	}
+	t.Errorf("%s is out of date with widget options; run `make docs`", referencePath)
+}
+
+func TestUpdateReference_Errors(t *testing.T) {
+	doc := "<!-- options:nope -->\n<!-- /options -->\n"
+	if _, err := UpdateReference(doc); err == nil {
+		t.Error("UpdateReference() should reject unknown widgets and missing tables")
+	}
+}
+
+func TestOptionTable(t *testing.T) {
+	if got := OptionTable(nil); got != "없음\n" {
+		t.Errorf("OptionTable(nil) = %q", got)
+	}
+
+	got := OptionTable(todosOptions)
+	for _, want := range []string{
+		"| `mode` | string | `summary` | `summary`, `progress` |",
+		"| `bar_width` | int | `0` | ≥ 0 |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("OptionTable() missing %q in:\n%s", want, got)
+		}
+	}
+}
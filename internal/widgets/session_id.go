@@ -8,33 +8,39 @@ import (
 
 // SessionIDWidget displays the current session ID.
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - show "Session:" prefix (default: false)
-//   - max_length: maximum length to display (default: 8, 0 = full)
+// Supported Extra options are declared in sessionIDOptions.
 //
 // Output format: "a1b2c3d4" or "Session: a1b2c3d4"
 type SessionIDWidget struct{}
 
+var sessionIDOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Session:' prefix"},
+	{Key: "max_length", Type: config.OptionInt, Default: "0", Min: "0", Description: "Max ID length (0 = full)"},
+}
+
 func (w *SessionIDWidget) Name() string {
 	return "session_id"
 }
 
+func (w *SessionIDWidget) Options() config.Options {
+	return sessionIDOptions
+}
+
 func (w *SessionIDWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
 	id := session.SessionID
 	if id == "" {
 		return ""
 	}
 
-	maxLen := GetExtraInt(cfg, "max_length", 0)
+	maxLen := sessionIDOptions.Int(cfg, "max_length")
 	if maxLen > 0 && len(id) > maxLen {
 		id = id[:maxLen]
 	}
 
-	var text string
-	if GetExtraBool(cfg, "show_label", false) {
-		text = "Session: " + id
-	} else {
-		text = id
+	// Links to the transcript file
+	text := render.Hyperlink(id, render.FileURL(session.TranscriptPath))
+	if sessionIDOptions.Bool(cfg, "show_label") {
+		text = "Session: " + text
 	}
 
 	return render.Colorize(text, "gray")
@@ -2,6 +2,7 @@ package widgets
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/namyoungkim/visor/internal/config"
 	"github.com/namyoungkim/visor/internal/input"
@@ -9,24 +10,49 @@ import (
 	"github.com/namyoungkim/visor/internal/transcript"
 )
 
-// TodosWidget displays task progress from TaskCreate/TaskUpdate tools.
+// Todo progress bar characters.
+const (
+	TodoBarFilled = "▰"
+	TodoBarEmpty  = "▱"
+)
+
+// maxAutoTodoBarWidth caps the auto bar width (one cell per task).
+const maxAutoTodoBarWidth = 10
+
+// TodosWidget displays task progress from TaskCreate/TaskUpdate or TodoWrite tools.
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - show "Tasks:" prefix (default: false)
-//   - max_subject_len: maximum length for task subject (default: 30)
+// Supported Extra options are declared in todosOptions.
 //
 // Output format:
 //   - "✓ All done (5/5)" when all tasks completed
-//   - "⊙ Task name (3/5)" when tasks in progress
+//   - "⊙ Task name (3/5)" when a task is in progress
 //   - "○ Task name (0/5)" when all tasks pending
+//   - progress mode: "3/7 ▰▰▰▱▱▱▱ ⊙ Task name"
+//
+// With show_active_form, in-progress tasks show their activeForm
+// ("Running tests") instead; show_elapsed appends the time spent ("4m").
 type TodosWidget struct {
 	transcript *transcript.Data
 }
 
+var todosOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Tasks:' prefix"},
+	{Key: "max_subject_len", Type: config.OptionInt, Default: "30", Min: "0", Description: "Max task subject length"},
+	{Key: "mode", Type: config.OptionString, Default: "summary", Enum: []string{"summary", "progress"}, Description: "Layout: summary or progress"},
+	{Key: "show_elapsed", Type: config.OptionBool, Default: "false", Description: "Show time on current task"},
+	{Key: "show_active_form", Type: config.OptionBool, Default: "false", Description: "Show the in-progress task's activeForm instead of its subject"},
+	{Key: "show_percent", Type: config.OptionBool, Default: "false", Description: "Show percent complete (progress mode)"},
+	{Key: "bar_width", Type: config.OptionInt, Default: "0", Min: "0", Description: "Progress bar width (0 = one cell per task, up to 10)"},
+}
+
 func (w *TodosWidget) Name() string {
 	return "todos"
 }
 
+func (w *TodosWidget) Options() config.Options {
+	return todosOptions
+}
+
 // SetTranscript sets the transcript data for this widget.
 func (w *TodosWidget) SetTranscript(t *transcript.Data) {
 	w.transcript = t
@@ -51,7 +77,7 @@ func (w *TodosWidget) Render(session *input.Session, cfg *config.WidgetConfig) s
 			completed++
 		case transcript.TodoInProgress:
 			inProgress++
-			if currentTask == nil {
+			if currentTask == nil || currentTask.Status != transcript.TodoInProgress {
 				currentTask = &todos[i]
 			}
 		case transcript.TodoPending:
@@ -61,31 +87,35 @@ func (w *TodosWidget) Render(session *input.Session, cfg *config.WidgetConfig) s
 		}
 	}
 
-	maxSubjectLen := GetExtraInt(cfg, "max_subject_len", 30)
+	maxSubjectLen := todosOptions.Int(cfg, "max_subject_len")
+	showElapsed := todosOptions.Bool(cfg, "show_elapsed")
+	activeForm := todosOptions.Bool(cfg, "show_active_form")
 	var text string
 	var color string
 
-	if completed == total {
+	if todosOptions.Value(cfg, "mode") == "progress" {
+		text, color = renderTodoProgress(cfg, currentTask, completed, total, maxSubjectLen, showElapsed, activeForm)
+	} else if completed == total {
 		// All done
 		text = fmt.Sprintf("✓ All done (%d/%d)", completed, total)
 		color = "green"
 	} else if currentTask != nil {
 		// Show current task
-		subject := truncateString(currentTask.Subject, maxSubjectLen)
-		icon := "○" // pending
-		color = "yellow"
-		if currentTask.Status == transcript.TodoInProgress {
-			icon = "⊙"
-			color = "cyan"
+		icon, taskColor := todoIcon(currentTask)
+		color = taskColor
+		text = fmt.Sprintf("%s %s (%d/%d)", icon, todoDisplaySubject(currentTask, maxSubjectLen, activeForm), completed, total)
+		if showElapsed {
+			if elapsed := todoElapsed(currentTask); elapsed != "" {
+				text += " " + elapsed
+			}
 		}
-		text = fmt.Sprintf("%s %s (%d/%d)", icon, subject, completed, total)
 	} else {
 		// Fallback
 		text = fmt.Sprintf("○ Tasks (%d/%d)", completed, total)
 		color = "yellow"
 	}
 
-	if GetExtraBool(cfg, "show_label", false) {
+	if todosOptions.Bool(cfg, "show_label") {
 		text = "Tasks: " + text
 	}
 
@@ -95,3 +125,67 @@ func (w *TodosWidget) Render(session *input.Session, cfg *config.WidgetConfig) s
 func (w *TodosWidget) ShouldRender(session *input.Session, cfg *config.WidgetConfig) bool {
 	return w.transcript != nil && len(w.transcript.Todos) > 0
 }
+
+// renderTodoProgress renders "3/7 ▰▰▰▱▱▱▱ ⊙ Subject (4m)" and its color.
+func renderTodoProgress(cfg *config.WidgetConfig, current *transcript.Todo, completed, total, maxSubjectLen int, showElapsed, activeForm bool) (string, string) {
+	width := todosOptions.Int(cfg, "bar_width")
+	if width <= 0 {
+		width = total
+		if width > maxAutoTodoBarWidth {
+			width = maxAutoTodoBarWidth
+		}
+	}
+	filled := completed * width / total
+
+	text := fmt.Sprintf("%d/%d", completed, total)
+	if todosOptions.Bool(cfg, "show_percent") {
+		text += fmt.Sprintf(" (%d%%)", completed*100/total)
+	}
+	text += " " + strings.Repeat(TodoBarFilled, filled) + strings.Repeat(TodoBarEmpty, width-filled)
+
+	if completed == total {
+		return text + " ✓ All done", "green"
+	}
+	if current == nil {
+		return text, "yellow"
+	}
+
+	icon, color := todoIcon(current)
+	text += " " + icon + " " + todoDisplaySubject(current, maxSubjectLen, activeForm)
+	if showElapsed {
+		if elapsed := todoElapsed(current); elapsed != "" {
+			text += " (" + elapsed + ")"
+		}
+	}
+	return text, color
+}
+
+// todoIcon returns the icon and color for the current task.
+func todoIcon(todo *transcript.Todo) (string, string) {
+	if todo.Status == transcript.TodoInProgress {
+		return "⊙", "cyan"
+	}
+	return "○", "yellow"
+}
+
+// todoDisplaySubject returns the truncated subject, or the activeForm of an
+// in-progress task when activeForm is set.
+func todoDisplaySubject(todo *transcript.Todo, maxLen int, activeForm bool) string {
+	subject := todo.Subject
+	if activeForm && todo.Status == transcript.TodoInProgress && todo.ActiveForm != "" {
+		subject = todo.ActiveForm
+	}
+	return truncateString(subject, maxLen)
+}
+
+// todoElapsed returns how long the task has been in progress, or "" if unknown.
+func todoElapsed(todo *transcript.Todo) string {
+	if todo.Status != transcript.TodoInProgress || todo.StartedAt <= 0 {
+		return ""
+	}
+	elapsedSec := (nowUnixMilli() - todo.StartedAt) / 1000
+	if elapsedSec < 0 {
+		return ""
+	}
+	return formatDurationSec(elapsedSec)
+}
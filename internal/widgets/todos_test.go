@@ -0,0 +1,121 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/transcript"
+)
+
+func TestTodosWidget_Render(t *testing.T) {
+	originalNow := nowUnixMilli
+	nowUnixMilli = func() int64 { return 241000 }
+	defer func() { nowUnixMilli = originalNow }()
+
+	inProgress := []transcript.Todo{
+		{ID: "1", Subject: "Read code", Status: transcript.TodoCompleted},
+		{ID: "2", Subject: "Fix parser", Status: transcript.TodoCompleted},
+		{ID: "3", Subject: "Run tests", Status: transcript.TodoCompleted},
+		{ID: "4", Subject: "Update docs", Status: transcript.TodoPending},
+		{ID: "5", Subject: "Write tests", ActiveForm: "Writing tests", Status: transcript.TodoInProgress, StartedAt: 1000},
+		{ID: "6", Subject: "Review", Status: transcript.TodoPending},
+		{ID: "7", Subject: "Release", Status: transcript.TodoPending},
+	}
+
+	tests := []struct {
+		name     string
+		todos    []transcript.Todo
+		extra    map[string]string
+		expected string
+	}{
+		{
+			name:     "summary shows in-progress subject",
+			todos:    inProgress,
+			expected: "⊙ Write tests (3/7)",
+		},
+		{
+			name:     "summary with activeForm and elapsed",
+			todos:    inProgress,
+			extra:    map[string]string{"show_active_form": "true", "show_elapsed": "true"},
+			expected: "⊙ Writing tests (3/7) 4m",
+		},
+		{
+			name:     "progress mode",
+			todos:    inProgress,
+			extra:    map[string]string{"mode": "progress"},
+			expected: "3/7 ▰▰▰▱▱▱▱ ⊙ Write tests",
+		},
+		{
+			name:     "progress mode with elapsed",
+			todos:    inProgress,
+			extra:    map[string]string{"mode": "progress", "show_elapsed": "true", "show_active_form": "true"},
+			expected: "3/7 ▰▰▰▱▱▱▱ ⊙ Writing tests (4m)",
+		},
+		{
+			name:     "progress mode with percent and fixed width",
+			todos:    inProgress,
+			extra:    map[string]string{"mode": "progress", "show_percent": "true", "bar_width": "10"},
+			expected: "3/7 (42%) ▰▰▰▰▱▱▱▱▱▱ ⊙ Write tests",
+		},
+		{
+			name: "progress mode all done",
+			todos: []transcript.Todo{
+				{ID: "1", Subject: "A", Status: transcript.TodoCompleted},
+				{ID: "2", Subject: "B", Status: transcript.TodoCompleted},
+			},
+			extra:    map[string]string{"mode": "progress"},
+			expected: "2/2 ▰▰ ✓ All done",
+		},
+		{
+			name: "pending only",
+			todos: []transcript.Todo{
+				{ID: "1", Subject: "First", Status: transcript.TodoPending},
+				{ID: "2", Subject: "Second", Status: transcript.TodoPending},
+			},
+			expected: "○ First (0/2)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &TodosWidget{}
+			w.SetTranscript(&transcript.Data{Todos: tt.todos})
+
+			result := stripANSI(w.Render(&input.Session{}, &config.WidgetConfig{Extra: tt.extra}))
+			if result != tt.expected {
+				t.Errorf("Render() = '%s', want '%s'", result, tt.expected)
+			}
+		})
+	}
+}
+
+// The default summary matches the output from before TodoWrite support:
+// subject only, no elapsed time.
+func TestTodosWidget_DefaultSummary(t *testing.T) {
+	originalNow := nowUnixMilli
+	nowUnixMilli = func() int64 { return 241000 }
+	defer func() { nowUnixMilli = originalNow }()
+
+	w := &TodosWidget{}
+	w.SetTranscript(&transcript.Data{Todos: []transcript.Todo{
+		{ID: "1", Subject: "Read code", Status: transcript.TodoCompleted},
+		{ID: "2", Subject: "Implement feature", ActiveForm: "Implementing feature", Status: transcript.TodoInProgress, StartedAt: 1000},
+		{ID: "3", Subject: "Write tests", Status: transcript.TodoPending},
+	}})
+
+	want := "⊙ Implement feature (1/3)"
+	if result := stripANSI(w.Render(&input.Session{}, &config.WidgetConfig{})); result != want {
+		t.Errorf("Render() = '%s', want '%s'", result, want)
+	}
+}
+
+func TestTodosWidget_Empty(t *testing.T) {
+	w := &TodosWidget{}
+	if w.ShouldRender(&input.Session{}, &config.WidgetConfig{}) {
+		t.Error("expected ShouldRender=false for nil transcript")
+	}
+	if result := w.Render(&input.Session{}, &config.WidgetConfig{}); result != "" {
+		t.Errorf("expected empty output, got '%s'", result)
+	}
+}
@@ -16,18 +16,25 @@ const (
 
 // TokenSpeedWidget displays the output token generation speed.
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - show "out:" prefix (default: false)
-//   - warn_threshold: tokens/sec below which to show warning (default: 20)
-//   - critical_threshold: tokens/sec below which to show critical (default: 10)
+// Supported Extra options are declared in tokenSpeedOptions.
 //
 // Output format: "42.1 tok/s"
 type TokenSpeedWidget struct{}
 
+var tokenSpeedOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'out:' prefix"},
+	{Key: "warn_threshold", Type: config.OptionFloat, Default: optionNumber(TokenSpeedWarningTPS), Min: "0", Description: "Warning below (tok/s)"},
+	{Key: "critical_threshold", Type: config.OptionFloat, Default: optionNumber(TokenSpeedCriticalTPS), Min: "0", Description: "Critical below (tok/s)"},
+}
+
 func (w *TokenSpeedWidget) Name() string {
 	return "token_speed"
 }
 
+func (w *TokenSpeedWidget) Options() config.Options {
+	return tokenSpeedOptions
+}
+
 func (w *TokenSpeedWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
 	tokens := session.GetTotalOutputTokens()
 	durationMs := session.Cost.TotalAPIDurationMs
@@ -49,15 +56,15 @@ func (w *TokenSpeedWidget) Render(session *input.Session, cfg *config.WidgetConf
 	}
 
 	var text string
-	if GetExtraBool(cfg, "show_label", false) {
+	if tokenSpeedOptions.Bool(cfg, "show_label") {
 		text = "out: " + value
 	} else {
 		text = value
 	}
 
 	// Lower speed is worse (inverse threshold)
-	warnThreshold := GetExtraFloat(cfg, "warn_threshold", TokenSpeedWarningTPS)
-	criticalThreshold := GetExtraFloat(cfg, "critical_threshold", TokenSpeedCriticalTPS)
+	warnThreshold := tokenSpeedOptions.Float(cfg, "warn_threshold")
+	criticalThreshold := tokenSpeedOptions.Float(cfg, "critical_threshold")
 	color := colorByThresholdLowerIsWorse(speed, warnThreshold, criticalThreshold)
 
 	return render.Colorize(text, color)
@@ -0,0 +1,147 @@
+package widgets
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/render"
+	"github.com/namyoungkim/visor/internal/transcript"
+)
+
+// Tool error rate thresholds (percentage of completed invocations).
+const (
+	ToolErrorWarningPct  = 10.0
+	ToolErrorCriticalPct = 25.0
+)
+
+// ToolStatsWidget displays per-tool latency and error rate from paired
+// tool_use/tool_result entries in the transcript.
+//
+// Supported Extra options are declared in toolStatsOptions.
+//
+// Output format: "Bash 1.2s/8.4s ✗14% · Read 40ms/120ms"
+// Latency is shown as p50/p95; only p95 is shown when show_p50=false.
+type ToolStatsWidget struct {
+	transcript *transcript.Data
+}
+
+var toolStatsOptions = config.Options{
+	{Key: "max_display", Type: config.OptionInt, Default: "3", Min: "0", Description: "Max tools to display (0 = unlimited)"},
+	{Key: "sort", Type: config.OptionString, Default: "slowest", Enum: []string{"slowest", "errors", "count", "recent"}, Description: "Order: p95 latency, error rate, invocations or transcript order"},
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Tools:' prefix"},
+	{Key: "show_p50", Type: config.OptionBool, Default: "true", Description: "Show median latency"},
+	{Key: "show_errors", Type: config.OptionBool, Default: "true", Description: "Show error rate"},
+	{Key: "show_slowest", Type: config.OptionBool, Default: "false", Description: "Show slowest recent call"},
+}
+
+func (w *ToolStatsWidget) Name() string {
+	return "tool_stats"
+}
+
+func (w *ToolStatsWidget) Options() config.Options {
+	return toolStatsOptions
+}
+
+// SetTranscript sets the transcript data for this widget.
+func (w *ToolStatsWidget) SetTranscript(t *transcript.Data) {
+	w.transcript = t
+}
+
+func (w *ToolStatsWidget) Render(session *input.Session, cfg *config.WidgetConfig) string {
+	if w.transcript == nil || len(w.transcript.ToolStats) == 0 {
+		return ""
+	}
+
+	maxDisplay := toolStatsOptions.Int(cfg, "max_display")
+	showP50 := toolStatsOptions.Bool(cfg, "show_p50")
+	showErrors := toolStatsOptions.Bool(cfg, "show_errors")
+
+	stats := sortToolStats(w.transcript.ToolStats, toolStatsOptions.Value(cfg, "sort"))
+	if maxDisplay > 0 && len(stats) > maxDisplay {
+		stats = stats[:maxDisplay]
+	}
+
+	var parts []string
+	for _, s := range stats {
+		part := s.Name
+		if s.P95Ms > 0 {
+			latency := formatLatencyMs(s.P95Ms)
+			if showP50 {
+				latency = formatLatencyMs(s.P50Ms) + "/" + latency
+			}
+			part += " " + render.Colorize(latency, "dim")
+		}
+		if showErrors && s.Errors > 0 {
+			rate := s.ErrorRate()
+			color := ColorByThreshold(rate, ToolErrorWarningPct, ToolErrorCriticalPct)
+			part += " " + render.Colorize(fmt.Sprintf("✗%.0f%%", rate), color)
+		}
+		parts = append(parts, part)
+	}
+
+	text := strings.Join(parts, " · ")
+
+	if toolStatsOptions.Bool(cfg, "show_slowest") {
+		if slowest := slowestCall(w.transcript.ToolStats); slowest != nil {
+			text += render.Colorize(" (slowest: "+slowest.Name+" "+formatLatencyMs(slowest.DurationMs())+")", "dim")
+		}
+	}
+
+	if toolStatsOptions.Bool(cfg, "show_label") {
+		text = "Tools: " + text
+	}
+
+	return text
+}
+
+func (w *ToolStatsWidget) ShouldRender(session *input.Session, cfg *config.WidgetConfig) bool {
+	return w.transcript != nil && len(w.transcript.ToolStats) > 0
+}
+
+// sortToolStats returns a copy of stats ordered by the given sort mode.
+// Unknown modes keep transcript order. The sort is stable so ties keep
+// first-seen order.
+func sortToolStats(stats []transcript.ToolStats, mode string) []transcript.ToolStats {
+	sorted := make([]transcript.ToolStats, len(stats))
+	copy(sorted, stats)
+
+	switch mode {
+	case "slowest":
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].P95Ms > sorted[j].P95Ms })
+	case "errors":
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ErrorRate() > sorted[j].ErrorRate() })
+	case "count":
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Count > sorted[j].Count })
+	}
+
+	return sorted
+}
+
+// slowestCall returns the slowest timed call across all tools, or nil if none.
+func slowestCall(stats []transcript.ToolStats) *transcript.ToolCall {
+	var slowest *transcript.ToolCall
+	for i := range stats {
+		c := &stats[i].Slowest
+		if c.DurationMs() <= 0 {
+			continue
+		}
+		if slowest == nil || c.DurationMs() > slowest.DurationMs() {
+			slowest = c
+		}
+	}
+	return slowest
+}
+
+// formatLatencyMs formats a latency in milliseconds: "850ms", "1.2s", "2m".
+func formatLatencyMs(ms int64) string {
+	if ms < 1000 {
+		return itoa(int(ms)) + "ms"
+	}
+	if ms < 60000 {
+		return fmt.Sprintf("%.1fs", float64(ms)/1000)
+	}
+	return formatDurationSec(ms / 1000)
+}
@@ -0,0 +1,91 @@
+package widgets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/namyoungkim/visor/internal/config"
+	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/transcript"
+)
+
+func TestToolStatsWidget_Render(t *testing.T) {
+	w := &ToolStatsWidget{}
+	w.SetTranscript(&transcript.Data{
+		ToolStats: []transcript.ToolStats{
+			{Name: "Read", Count: 10, P50Ms: 40, P95Ms: 120},
+			{Name: "Bash", Count: 7, Errors: 1, P50Ms: 1200, P95Ms: 8400},
+		},
+	})
+
+	result := stripANSI(w.Render(&input.Session{}, &config.WidgetConfig{}))
+
+	// Default sort is slowest first
+	if !strings.HasPrefix(result, "Bash 1.2s/8.4s ✗14%") {
+		t.Errorf("expected Bash first with latency and error rate, got '%s'", result)
+	}
+	if !strings.Contains(result, "Read 40ms/120ms") {
+		t.Errorf("expected Read latency, got '%s'", result)
+	}
+	if strings.Contains(result, "Read 40ms/120ms ✗") {
+		t.Errorf("expected no error rate for Read, got '%s'", result)
+	}
+}
+
+func TestToolStatsWidget_Options(t *testing.T) {
+	w := &ToolStatsWidget{}
+	w.SetTranscript(&transcript.Data{
+		ToolStats: []transcript.ToolStats{
+			{Name: "Read", Count: 10, P50Ms: 40, P95Ms: 120},
+			{Name: "Bash", Count: 4, Errors: 2, P50Ms: 1200, P95Ms: 8400,
+				Slowest: transcript.ToolCall{Name: "Bash", StartTime: 1000, EndTime: 43000, Done: true}},
+			{Name: "Edit", Count: 3, P50Ms: 200, P95Ms: 300},
+		},
+	})
+
+	cfg := &config.WidgetConfig{Extra: map[string]string{
+		"sort":         "count",
+		"max_display":  "2",
+		"show_p50":     "false",
+		"show_errors":  "false",
+		"show_slowest": "true",
+		"show_label":   "true",
+	}}
+
+	result := stripANSI(w.Render(&input.Session{}, cfg))
+	expected := "Tools: Read 120ms · Bash 8.4s (slowest: Bash 42.0s)"
+	if result != expected {
+		t.Errorf("Render() = '%s', want '%s'", result, expected)
+	}
+}
+
+func TestToolStatsWidget_Empty(t *testing.T) {
+	w := &ToolStatsWidget{}
+	if w.ShouldRender(&input.Session{}, &config.WidgetConfig{}) {
+		t.Error("expected ShouldRender=false for nil transcript")
+	}
+
+	w.SetTranscript(&transcript.Data{})
+	if result := w.Render(&input.Session{}, &config.WidgetConfig{}); result != "" {
+		t.Errorf("expected empty string, got '%s'", result)
+	}
+}
+
+func TestFormatLatencyMs(t *testing.T) {
+	tests := []struct {
+		ms       int64
+		expected string
+	}{
+		{0, "0ms"},
+		{850, "850ms"},
+		{1200, "1.2s"},
+		{59900, "59.9s"},
+		{120000, "2m"},
+	}
+
+	for _, tt := range tests {
+		if got := formatLatencyMs(tt.ms); got != tt.expected {
+			t.Errorf("formatLatencyMs(%d) = %s, want %s", tt.ms, got, tt.expected)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package widgets
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/namyoungkim/visor/internal/config"
@@ -11,21 +12,31 @@ import (
 
 // ToolsWidget displays recent tool invocations with their status and count.
 //
-// Supported Extra options:
-//   - max_display: maximum number of tools to show, 0 = unlimited (default: "0")
-//   - show_label: "true"/"false" - show prefix (default: false)
-//   - show_count: "true"/"false" - show invocation count (default: true)
+// Supported Extra options are declared in toolsOptions.
 //
 // Output format: "✓Bash ×7 | ✓Edit ×4 | ✓Read ×6" (with counts)
+// With show_errors: "✓Bash ×7 ✗2" when 2 of 7 invocations failed.
 // Status icons: ✓ (completed), ✗ (error), ◐ (running)
 type ToolsWidget struct {
 	transcript *transcript.Data
 }
 
+var toolsOptions = config.Options{
+	{Key: "max_display", Type: config.OptionInt, Default: "0", Min: "0", Description: "Max tools to display (0 = unlimited)"},
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Tools:' prefix"},
+	{Key: "show_count", Type: config.OptionBool, Default: "true", Description: "Show invocation count"},
+	{Key: "show_errors", Type: config.OptionBool, Default: "false", Description: "Show failed invocation count"},
+	{Key: "sort", Type: config.OptionString, Default: "recent", Enum: []string{"recent", "count", "slowest", "errors"}, Description: "Order: transcript order, invocations, p95 latency or failures"},
+}
+
 func (w *ToolsWidget) Name() string {
 	return "tools"
 }
 
+func (w *ToolsWidget) Options() config.Options {
+	return toolsOptions
+}
+
 // SetTranscript sets the transcript data for this widget.
 func (w *ToolsWidget) SetTranscript(t *transcript.Data) {
 	w.transcript = t
@@ -36,32 +47,64 @@ func (w *ToolsWidget) Render(session *input.Session, cfg *config.WidgetConfig) s
 		return ""
 	}
 
-	maxDisplay := GetExtraInt(cfg, "max_display", 0) // 0 = unlimited
-	showCount := GetExtraBool(cfg, "show_count", true)
-	tools := w.transcript.Tools
+	maxDisplay := toolsOptions.Int(cfg, "max_display") // 0 = unlimited
+	showCount := toolsOptions.Bool(cfg, "show_count")
+	showErrors := toolsOptions.Bool(cfg, "show_errors")
+	sortMode := toolsOptions.Value(cfg, "sort")
+	tools := w.sortTools(sortMode)
 
-	// Show only the last N tools (0 = show all)
-	start := 0
+	// Show only the last N tools in transcript order, or the top N otherwise (0 = show all)
 	if maxDisplay > 0 && len(tools) > maxDisplay {
-		start = len(tools) - maxDisplay
+		if sortMode == "recent" {
+			tools = tools[len(tools)-maxDisplay:]
+		} else {
+			tools = tools[:maxDisplay]
+		}
 	}
 
 	var parts []string
-	for _, tool := range tools[start:] {
+	for _, tool := range tools {
 		icon, color := toolStatusIcon(tool.Status)
 		part := render.Colorize(icon+tool.Name, color) + countSuffix(showCount, tool.Count)
+		if showErrors && tool.Errors > 0 {
+			part += render.Colorize(" ✗"+itoa(tool.Errors), "red")
+		}
 		parts = append(parts, part)
 	}
 
 	text := strings.Join(parts, " | ")
 
-	if GetExtraBool(cfg, "show_label", false) {
+	if toolsOptions.Bool(cfg, "show_label") {
 		text = "Tools: " + text
 	}
 
 	return text
 }
 
+// sortTools returns the tools ordered by the given sort mode.
+// Unknown modes keep transcript order.
+func (w *ToolsWidget) sortTools(mode string) []transcript.Tool {
+	tools := make([]transcript.Tool, len(w.transcript.Tools))
+	copy(tools, w.transcript.Tools)
+
+	switch mode {
+	case "count":
+		sort.SliceStable(tools, func(i, j int) bool { return tools[i].Count > tools[j].Count })
+	case "errors":
+		sort.SliceStable(tools, func(i, j int) bool { return tools[i].Errors > tools[j].Errors })
+	case "slowest":
+		p95 := func(name string) int64 {
+			if s := w.transcript.StatsFor(name); s != nil {
+				return s.P95Ms
+			}
+			return 0
+		}
+		sort.SliceStable(tools, func(i, j int) bool { return p95(tools[i].Name) > p95(tools[j].Name) })
+	}
+
+	return tools
+}
+
 // countSuffix returns the count suffix (e.g., " ×7") if show_count is enabled and count > 1.
 func countSuffix(showCount bool, count int) string {
 	if showCount && count > 1 {
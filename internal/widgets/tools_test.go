@@ -235,3 +235,49 @@ func Test_itoa(t *testing.T) {
 		}
 	}
 }
+
+func TestToolsWidget_ShowErrors(t *testing.T) {
+	w := &ToolsWidget{}
+	w.SetTranscript(&transcript.Data{
+		Tools: []transcript.Tool{
+			{ID: "1", Name: "Bash", Status: transcript.ToolCompleted, Count: 7, Errors: 2},
+			{ID: "2", Name: "Read", Status: transcript.ToolCompleted, Count: 3},
+		},
+	})
+
+	result := stripANSI(w.Render(&input.Session{}, &config.WidgetConfig{}))
+	if strings.Contains(result, "✗2") {
+		t.Errorf("expected errors hidden by default, got '%s'", result)
+	}
+
+	cfg := &config.WidgetConfig{Extra: map[string]string{"show_errors": "true"}}
+	result = stripANSI(w.Render(&input.Session{}, cfg))
+	if !strings.Contains(result, "✓Bash ×7 ✗2") {
+		t.Errorf("expected '✓Bash ×7 ✗2', got '%s'", result)
+	}
+	if strings.Contains(result, "Read ×3 ✗") {
+		t.Errorf("expected no error suffix for Read, got '%s'", result)
+	}
+}
+
+func TestToolsWidget_SortSlowest(t *testing.T) {
+	w := &ToolsWidget{}
+	w.SetTranscript(&transcript.Data{
+		Tools: []transcript.Tool{
+			{ID: "1", Name: "Read", Status: transcript.ToolCompleted},
+			{ID: "2", Name: "Bash", Status: transcript.ToolCompleted},
+			{ID: "3", Name: "Edit", Status: transcript.ToolCompleted},
+		},
+		ToolStats: []transcript.ToolStats{
+			{Name: "Read", P95Ms: 100},
+			{Name: "Bash", P95Ms: 9000},
+			{Name: "Edit", P95Ms: 300},
+		},
+	})
+
+	cfg := &config.WidgetConfig{Extra: map[string]string{"sort": "slowest", "max_display": "2"}}
+	result := stripANSI(w.Render(&input.Session{}, cfg))
+	if result != "✓Bash | ✓Edit" {
+		t.Errorf("expected '✓Bash | ✓Edit', got '%s'", result)
+	}
+}
@@ -18,19 +18,28 @@ const (
 // WeekLimitWidget displays the 7-day rate limit utilization.
 // This is for Claude Pro users to see their weekly usage against the rate limit.
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - whether to show "7d:" prefix (default: true)
-//   - show_remaining: "true"/"false" - show remaining time (default: false)
-//   - warn_threshold: "70" - % utilization for warning color (default: 70)
-//   - critical_threshold: "90" - % utilization for critical color (default: 90)
+// Supported Extra options are declared in weekLimitOptions.
 type WeekLimitWidget struct {
 	limits *usage.Limits
 }
 
+var weekLimitOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "true", Description: "Show '7d:' prefix"},
+	{Key: "show_remaining", Type: config.OptionBool, Default: "false", Description: "Show time until reset"},
+	{Key: "warn_threshold", Type: config.OptionFloat, Default: optionNumber(WeekLimitWarningPct), Min: "0", Max: "100", Description: "Warning threshold (% used)"},
+	{Key: "critical_threshold", Type: config.OptionFloat, Default: optionNumber(WeekLimitCriticalPct), Min: "0", Max: "100", Description: "Critical threshold (% used)"},
+	{Key: "show_stale", Type: config.OptionBool, Default: "true", Description: "Mark cached data older than the TTL with '*'"},
+	{Key: "show_source", Type: config.OptionBool, Default: "false", Description: "Show limits source, e.g. [oauth]"},
+}
+
 func (w *WeekLimitWidget) Name() string {
 	return "week_limit"
 }
 
+func (w *WeekLimitWidget) Options() config.Options {
+	return weekLimitOptions
+}
+
 // SetLimits sets the usage limits for this widget.
 func (w *WeekLimitWidget) SetLimits(limits *usage.Limits) {
 	w.limits = limits
@@ -42,36 +51,38 @@ func (w *WeekLimitWidget) Render(session *input.Session, cfg *config.WidgetConfi
 	}
 
 	pct := w.limits.SevenDay.Utilization
+	stale := staleMarker(w.limits, cfg)
 
 	var value string
-	if GetExtraBool(cfg, "show_remaining", false) {
+	if weekLimitOptions.Bool(cfg, "show_remaining") {
 		remaining := w.limits.SevenDayRemaining()
 		if remaining > 0 {
 			days := int(remaining.Hours()) / 24
 			hours := int(remaining.Hours()) % 24
 			if days > 0 {
-				value = fmt.Sprintf("%.0f%% (%dd%dh)", pct, days, hours)
+				value = fmt.Sprintf("%.0f%%%s (%dd%dh)", pct, stale, days, hours)
 			} else {
-				value = fmt.Sprintf("%.0f%% (%dh)", pct, hours)
+				value = fmt.Sprintf("%.0f%%%s (%dh)", pct, stale, hours)
 			}
 		} else {
-			value = fmt.Sprintf("%.0f%%", pct)
+			value = fmt.Sprintf("%.0f%%", pct) + stale
 		}
 	} else {
-		value = fmt.Sprintf("%.0f%%", pct)
+		value = fmt.Sprintf("%.0f%%", pct) + stale
 	}
+	value += sourceHint(w.limits, cfg)
 
 	var text string
 	if cfg.Format != "" {
 		text = FormatOutput(cfg, "", value)
-	} else if GetExtraBool(cfg, "show_label", true) {
+	} else if weekLimitOptions.Bool(cfg, "show_label") {
 		text = "7d: " + value
 	} else {
 		text = value
 	}
 
-	warnThreshold := GetExtraFloat(cfg, "warn_threshold", WeekLimitWarningPct)
-	criticalThreshold := GetExtraFloat(cfg, "critical_threshold", WeekLimitCriticalPct)
+	warnThreshold := weekLimitOptions.Float(cfg, "warn_threshold")
+	criticalThreshold := weekLimitOptions.Float(cfg, "critical_threshold")
 	color := ColorByThreshold(pct, warnThreshold, criticalThreshold)
 	return render.Colorize(text, color)
 }
@@ -15,18 +15,25 @@ const (
 
 // WeeklyCostWidget displays this week's aggregated cost.
 //
-// Supported Extra options:
-//   - show_label: "true"/"false" - whether to show "Week:" prefix (default: false)
-//   - warn_threshold: "25.0" - USD for warning color (default: 25.0)
-//   - critical_threshold: "50.0" - USD for critical/red color (default: 50.0)
+// Supported Extra options are declared in weeklyCostOptions.
 type WeeklyCostWidget struct {
 	costData *cost.CostData
 }
 
+var weeklyCostOptions = config.Options{
+	{Key: "show_label", Type: config.OptionBool, Default: "false", Description: "Show 'Week:' prefix"},
+	{Key: "warn_threshold", Type: config.OptionFloat, Default: optionNumber(WeeklyCostWarningUSD), Min: "0", Description: "Warning threshold (USD)"},
+	{Key: "critical_threshold", Type: config.OptionFloat, Default: optionNumber(WeeklyCostCriticalUSD), Min: "0", Description: "Critical threshold (USD)"},
+}
+
 func (w *WeeklyCostWidget) Name() string {
 	return "weekly_cost"
 }
 
+func (w *WeeklyCostWidget) Options() config.Options {
+	return weeklyCostOptions
+}
+
 // SetCostData sets the cost data for this widget.
 func (w *WeeklyCostWidget) SetCostData(data *cost.CostData) {
 	w.costData = data
@@ -42,14 +49,14 @@ func (w *WeeklyCostWidget) Render(session *input.Session, cfg *config.WidgetConf
 	var text string
 	if cfg.Format != "" {
 		text = FormatOutput(cfg, "", value)
-	} else if GetExtraBool(cfg, "show_label", false) {
+	} else if weeklyCostOptions.Bool(cfg, "show_label") {
 		text = "Week: " + value
 	} else {
 		text = value
 	}
 
-	warnThreshold := GetExtraFloat(cfg, "warn_threshold", WeeklyCostWarningUSD)
-	criticalThreshold := GetExtraFloat(cfg, "critical_threshold", WeeklyCostCriticalUSD)
+	warnThreshold := weeklyCostOptions.Float(cfg, "warn_threshold")
+	criticalThreshold := weeklyCostOptions.Float(cfg, "critical_threshold")
 	color := ColorByThreshold(w.costData.Week, warnThreshold, criticalThreshold)
 	return render.Colorize(text, color)
 }
@@ -1,14 +1,15 @@
 package widgets
 
 import (
-	"strconv"
 	"strings"
 
+	"github.com/namyoungkim/visor/internal/auth"
 	"github.com/namyoungkim/visor/internal/claudeconfig"
 	"github.com/namyoungkim/visor/internal/config"
 	"github.com/namyoungkim/visor/internal/cost"
 	"github.com/namyoungkim/visor/internal/history"
 	"github.com/namyoungkim/visor/internal/input"
+	"github.com/namyoungkim/visor/internal/render"
 	"github.com/namyoungkim/visor/internal/transcript"
 	"github.com/namyoungkim/visor/internal/usage"
 )
@@ -84,48 +85,30 @@ func FormatOutput(cfg *config.WidgetConfig, defaultFormat, value string) string
 	return result
 }
 
-// GetExtra returns a value from the Extra map, or defaultValue if not found.
-func GetExtra(cfg *config.WidgetConfig, key, defaultValue string) string {
-	if cfg.Extra == nil {
-		return defaultValue
-	}
-	if v, ok := cfg.Extra[key]; ok {
-		return v
-	}
-	return defaultValue
-}
-
-// GetExtraBool returns a boolean value from the Extra map.
-func GetExtraBool(cfg *config.WidgetConfig, key string, defaultValue bool) bool {
-	v := GetExtra(cfg, key, "")
-	if v == "" {
-		return defaultValue
-	}
-	return v == "true" || v == "1" || v == "yes"
-}
-
-// GetExtraInt returns an integer value from the Extra map.
-func GetExtraInt(cfg *config.WidgetConfig, key string, defaultValue int) int {
-	v := GetExtra(cfg, key, "")
-	if v == "" {
-		return defaultValue
-	}
-	if n, err := strconv.Atoi(v); err == nil {
-		return n
-	}
-	return defaultValue
-}
-
-// GetExtraFloat returns a float64 value from the Extra map.
-func GetExtraFloat(cfg *config.WidgetConfig, key string, defaultValue float64) float64 {
-	v := GetExtra(cfg, key, "")
-	if v == "" {
-		return defaultValue
-	}
-	if f, err := strconv.ParseFloat(v, 64); err == nil {
-		return f
+// rawFieldPrefix starts a format placeholder for a raw stdin field,
+// e.g. "{raw.output_style.name}".
+const rawFieldPrefix = "{raw."
+
+// expandRawFields replaces {raw.<path>} placeholders with values from the
+// stdin JSON, so formats can show fields visor does not model.
+// Missing fields expand to "".
+func expandRawFields(format string, session *input.Session) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(format, rawFieldPrefix)
+		if i < 0 {
+			break
+		}
+		j := strings.IndexByte(format[i:], '}')
+		if j < 0 {
+			break
+		}
+		b.WriteString(format[:i])
+		b.WriteString(session.FieldString(format[i+len(rawFieldPrefix) : i+j]))
+		format = format[i+j+1:]
 	}
-	return defaultValue
+	b.WriteString(format)
+	return b.String()
 }
 
 // Progress bar characters.
@@ -166,16 +149,31 @@ type Widget interface {
 	Name() string
 	Render(session *input.Session, cfg *config.WidgetConfig) string
 	ShouldRender(session *input.Session, cfg *config.WidgetConfig) bool
+
+	// Options declares the widget's Extra options. It is the single source
+	// for option defaults, `visor --check`, the TUI editor and the docs.
+	Options() config.Options
 }
 
 // Registry holds all registered widgets.
 var Registry = make(map[string]Widget)
 
+// registryOrder holds widget names in registration order.
+var registryOrder []string
+
 // Register adds a widget to the registry.
 func Register(w Widget) {
+	if _, ok := Registry[w.Name()]; !ok {
+		registryOrder = append(registryOrder, w.Name())
+	}
 	Registry[w.Name()] = w
 }
 
+// Names returns the registered widget names in registration order.
+func Names() []string {
+	return append([]string(nil), registryOrder...)
+}
+
 // Get returns a widget by name.
 func Get(name string) (Widget, bool) {
 	w, ok := Registry[name]
@@ -192,19 +190,38 @@ func RenderAll(session *input.Session, widgets []config.WidgetConfig) []string {
 			continue
 		}
 
+		if strings.Contains(cfg.Format, rawFieldPrefix) {
+			cfg.Format = expandRawFields(cfg.Format, session)
+		}
+
 		if !w.ShouldRender(session, &cfg) {
 			continue
 		}
 
 		rendered := w.Render(session, &cfg)
 		if rendered != "" {
-			result = append(result, rendered)
+			result = append(result, applyStyle(rendered, cfg.Style))
 		}
 	}
 
 	return result
 }
 
+// applyStyle applies a widget's [line.widget.style] to its rendered text.
+func applyStyle(rendered string, style config.StyleConfig) string {
+	if style == (config.StyleConfig{}) {
+		return rendered
+	}
+	return render.TextStyle{
+		Fg:        style.Fg,
+		Bg:        style.Bg,
+		Bold:      style.Bold,
+		Dim:       style.Dim,
+		Italic:    style.Italic,
+		Underline: style.Underline,
+	}.Restyle(rendered)
+}
+
 // contextSparkWidget holds the singleton instance for history injection.
 var contextSparkWidget = &ContextSparkWidget{}
 
@@ -214,6 +231,15 @@ var blockTimerWidget = &BlockTimerWidget{}
 // toolsWidget holds the singleton instance for transcript injection.
 var toolsWidget = &ToolsWidget{}
 
+// toolStatsWidget holds the singleton instance for transcript injection.
+var toolStatsWidget = &ToolStatsWidget{}
+
+// currentToolWidget holds the singleton instance for transcript injection.
+var currentToolWidget = &CurrentToolWidget{}
+
+// filesWidget holds the singleton instance for transcript injection.
+var filesWidget = &FilesWidget{}
+
 // agentsWidget holds the singleton instance for transcript injection.
 var agentsWidget = &AgentsWidget{}
 
@@ -231,6 +257,10 @@ var blockCostWidget = &BlockCostWidget{}
 // Usage limit widgets (singleton instances for data injection).
 var blockLimitWidget = &BlockLimitWidget{}
 var weekLimitWidget = &WeekLimitWidget{}
+var limitForecastWidget = &LimitForecastWidget{}
+
+// authStatusWidget holds the singleton instance for auth status injection.
+var authStatusWidget = &AuthStatusWidget{}
 
 // SetHistory sets the history on widgets that need it.
 func SetHistory(h *history.History) {
@@ -241,6 +271,9 @@ func SetHistory(h *history.History) {
 // SetTranscript sets the transcript data on widgets that need it.
 func SetTranscript(t *transcript.Data) {
 	toolsWidget.SetTranscript(t)
+	toolStatsWidget.SetTranscript(t)
+	currentToolWidget.SetTranscript(t)
+	filesWidget.SetTranscript(t)
 	agentsWidget.SetTranscript(t)
 	todosWidget.SetTranscript(t)
 }
@@ -250,12 +283,19 @@ func SetCostData(data *cost.CostData) {
 	dailyCostWidget.SetCostData(data)
 	weeklyCostWidget.SetCostData(data)
 	blockCostWidget.SetCostData(data)
+	limitForecastWidget.SetCostData(data)
 }
 
 // SetUsageLimits sets the usage limits on widgets that need it.
 func SetUsageLimits(limits *usage.Limits) {
 	blockLimitWidget.SetLimits(limits)
 	weekLimitWidget.SetLimits(limits)
+	limitForecastWidget.SetLimits(limits)
+}
+
+// SetAuthStatus sets the authentication status on widgets that need it.
+func SetAuthStatus(status *auth.Status) {
+	authStatusWidget.SetStatus(status)
 }
 
 // SetConfigCounts sets the config counts on widgets that need it.
@@ -287,6 +327,7 @@ func init() {
 	// Register usage limit widgets (v0.6)
 	Register(blockLimitWidget)
 	Register(weekLimitWidget)
+	Register(limitForecastWidget)
 
 	// Register new widgets (v0.10)
 	Register(&DurationWidget{})
@@ -296,4 +337,18 @@ func init() {
 	Register(configCountsWidget)
 	Register(&SessionIDWidget{})
 	Register(&CWDWidget{})
+
+	// Register transcript analytics widgets (v0.12)
+	Register(toolStatsWidget)
+	Register(currentToolWidget)
+	Register(filesWidget)
+
+	// Register auth widgets (v0.12)
+	Register(authStatusWidget)
+
+	// Register statusline field widgets (v0.12)
+	Register(&CCVersionWidget{})
+	Register(&OutputStyleWidget{})
+	Register(&ProjectDirWidget{})
+	Register(&LongContextWidget{})
 }